@@ -45,7 +45,7 @@ func addressGenCmd() gcli.Command {
 			startIndex := c.Int("startIndex")
 			confirmAddress := c.Bool("confirmAddress")
 
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
@@ -59,47 +59,15 @@ func addressGenCmd() gcli.Command {
 				}
 			}
 
-			var pinEnc string
 			msg, err := device.AddressGen(uint32(addressN), uint32(startIndex), confirmAddress)
 			if err != nil {
 				log.Error(err)
 				return
 			}
-
-			for msg.Kind != uint16(messages.MessageType_MessageType_ResponseSkycoinAddress) && msg.Kind != uint16(messages.MessageType_MessageType_Failure) {
-				if msg.Kind == uint16(messages.MessageType_MessageType_PinMatrixRequest) {
-					fmt.Printf("PinMatrixRequest response: ")
-					fmt.Scanln(&pinEnc)
-					pinAckResponse, err := device.PinMatrixAck(pinEnc)
-					if err != nil {
-						log.Error(err)
-						return
-					}
-					log.Infof("PinMatrixAck response: %s", pinAckResponse)
-					continue
-				}
-
-				if msg.Kind == uint16(messages.MessageType_MessageType_PassphraseRequest) {
-					var passphrase string
-					fmt.Printf("Input passphrase: ")
-					fmt.Scanln(&passphrase)
-					passphraseAckResponse, err := device.PassphraseAck(passphrase)
-					if err != nil {
-						log.Error(err)
-						return
-					}
-					log.Infof("PinMatrixAck response: %s", passphraseAckResponse)
-					continue
-				}
-
-				if msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-					msg, err = device.ButtonAck()
-					if err != nil {
-						log.Error(err)
-						return
-					}
-					continue
-				}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
 			}
 
 			if msg.Kind == uint16(messages.MessageType_MessageType_ResponseSkycoinAddress) {
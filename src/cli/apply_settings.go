@@ -44,7 +44,7 @@ func applySettingsCmd() gcli.Command {
 			label := c.String("label")
 			language := c.String("language")
 
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
@@ -69,28 +69,10 @@ func applySettingsCmd() gcli.Command {
 				return
 			}
 
-			for msg.Kind != uint16(messages.MessageType_MessageType_Failure) && msg.Kind != uint16(messages.MessageType_MessageType_Success) {
-				if msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-					msg, err = device.ButtonAck()
-					if err != nil {
-						log.Error(err)
-						return
-					}
-					continue
-				}
-
-				if msg.Kind == uint16(messages.MessageType_MessageType_PinMatrixRequest) {
-					var pinEnc string
-					fmt.Printf("PinMatrixRequest response: ")
-					fmt.Scanln(&pinEnc)
-					pinAckResponse, err := device.PinMatrixAck(pinEnc)
-					if err != nil {
-						log.Error(err)
-						return
-					}
-					log.Infof("PinMatrixAck response: %s", pinAckResponse)
-					continue
-				}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
 			}
 
 			if msg.Kind == uint16(messages.MessageType_MessageType_Failure) {
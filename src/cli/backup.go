@@ -7,8 +7,6 @@ import (
 
 	gcli "github.com/urfave/cli"
 
-	messages "github.com/skycoin/hardware-wallet-protob/go"
-
 	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
 )
 
@@ -27,7 +25,7 @@ func backupCmd() gcli.Command {
 			},
 		},
 		Action: func(c *gcli.Context) {
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
@@ -47,31 +45,10 @@ func backupCmd() gcli.Command {
 				return
 			}
 
-			if msg.Kind == uint16(messages.MessageType_MessageType_PinMatrixRequest) {
-				var pinEnc string
-				fmt.Printf("PinMatrixRequest response: ")
-				fmt.Scanln(&pinEnc)
-				msg, err := device.PinMatrixAck(pinEnc)
-				if err != nil {
-					log.Error(err)
-					return
-				}
-
-				for msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-					msg, err = device.ButtonAck()
-					if err != nil {
-						log.Error(err)
-						return
-					}
-				}
-			}
-
-			for msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-				msg, err = device.ButtonAck()
-				if err != nil {
-					log.Error(err)
-					return
-				}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
 			}
 
 			responseMsg, err := skyWallet.DecodeSuccessOrFailMsg(msg)
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	gcli "github.com/urfave/cli"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/builder"
+)
+
+func buildTransactionCmd() gcli.Command {
+	name := "buildTransaction"
+	return gcli.Command{
+		Name:  name,
+		Usage: "Build an unsigned transaction from the unspent outputs of the device addresses. No device is needed.",
+		Description: `The unspent outputs are read as a JSON array of objects with the hash, address, coins in droplets and
+        hours of each output. The coins not sent go back to a change address of the device and the hours left after
+        the fee are shared between the outputs without an hour value and the change. The transaction is written to
+        --psbt, to be signed by transactionSign --psbt or carried to an offline host by exportTransaction.`,
+		Flags: append([]gcli.Flag{
+			gcli.StringFlag{
+				Name:  "utxos",
+				Usage: "JSON file of the unspent outputs, read from the standard input if not set",
+			},
+			gcli.StringSliceFlag{
+				Name:  "walletAddress",
+				Usage: "Addresses of the device, in the order of their index from startIndex",
+			},
+			gcli.IntFlag{
+				Name:  "startIndex",
+				Value: 0,
+				Usage: "Index of the first walletAddress. Assume 0 if not set.",
+			},
+			gcli.StringSliceFlag{
+				Name:  "outputAddress",
+				Usage: "Addresses of the output for the transaction",
+			},
+			gcli.Int64SliceFlag{
+				Name:  "coin",
+				Usage: "Amount of coins",
+			},
+			gcli.Int64SliceFlag{
+				Name:  "hour",
+				Usage: "Number of hours, for every output or for none to share the hours left after the fee",
+			},
+			gcli.StringFlag{
+				Name:  "strategy",
+				Value: string(builder.LargestFirst),
+				Usage: fmt.Sprintf("Unspent outputs spent first, %s, %s or %s", builder.LargestFirst, builder.SmallestFirst, builder.MostHoursFirst),
+			},
+			gcli.StringFlag{
+				Name:  "changeAddress",
+				Usage: "Address of the device receiving the coins not sent, the address of the first input if not set",
+			},
+			gcli.Float64Flag{
+				Name:  "shareFactor",
+				Value: builder.DefaultShareFactor,
+				Usage: "Share of the hours left after the fee sent to the outputs, the rest goes to the change",
+			},
+			gcli.StringFlag{
+				Name:  "psbt",
+				Usage: "File to write the unsigned partially signed transaction to",
+			},
+			gcli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the transaction summary as JSON",
+			},
+		}, verifyParamsFlags()...),
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			psbtPath := c.String("psbt")
+			if psbtPath != "" {
+				if _, err := os.Stat(psbtPath); err == nil {
+					log.Errorf("%s already exists", psbtPath)
+					return
+				}
+			}
+
+			strategy, err := builder.ParseStrategy(c.String("strategy"))
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			config := builder.NewConfig(walletAddresses(c.StringSlice("walletAddress"), uint32(c.Int("startIndex"))))
+			config.Strategy = strategy
+			config.ChangeAddress = c.String("changeAddress")
+			config.ShareFactor = c.Float64("shareFactor")
+			config.Params = verifyParams(c)
+			if len(config.Addresses) == 0 {
+				log.Error(errors.New("give the addresses of the device with --walletAddress"))
+				return
+			}
+
+			destinations, ok := destinationFlags(c)
+			if !ok {
+				return
+			}
+
+			var in io.Reader = os.Stdin
+			if path := c.String("utxos"); path != "" {
+				var f *os.File
+				f, err = os.Open(path)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				defer f.Close()
+				in = f
+			}
+			utxos, err := builder.ReadUTXOs(in)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			tx, err := builder.Build(utxos, destinations, config)
+			if err != nil {
+				reportBuildError(err)
+				return
+			}
+			summary, err := skyWallet.NewTransactionSummary(tx.Inputs, tx.Outputs, tx.Values())
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if err = writeSummary(summary, c.Bool("json")); err != nil {
+				log.Error(err)
+				return
+			}
+			if psbtPath != "" {
+				if err = tx.Partial().Save(psbtPath); err != nil {
+					log.Error(err)
+					return
+				}
+				fmt.Fprintf(os.Stderr, "Transaction written to %s\n", psbtPath)
+			}
+		},
+	}
+}
+
+// walletAddresses maps the addresses to their index in the wallet, from startIndex
+func walletAddresses(addresses []string, startIndex uint32) map[string]uint32 {
+	indexes := make(map[string]uint32, len(addresses))
+	for i, address := range addresses {
+		indexes[address] = startIndex + uint32(i)
+	}
+	return indexes
+}
+
+// destinationFlags returns the destinations given by the outputAddress, coin
+// and hour flags, it tells the user and returns false if they do not match
+func destinationFlags(c *gcli.Context) ([]builder.Destination, bool) {
+	outputs := c.StringSlice("outputAddress")
+	coins := c.Int64Slice("coin")
+	hours := c.Int64Slice("hour")
+	if len(outputs) != len(coins) {
+		fmt.Println("Every given output should have a coin value")
+		return nil, false
+	}
+	if len(hours) != 0 && len(hours) != len(outputs) {
+		fmt.Println("Every given output should have an hour value, or none to share the hours left after the fee")
+		return nil, false
+	}
+	invalid := negativeAmounts("coin", coins)
+	invalid = append(invalid, negativeAmounts("hour", hours)...)
+	if len(invalid) != 0 {
+		reportInvalidFields(invalid)
+		return nil, false
+	}
+
+	destinations := make([]builder.Destination, len(outputs))
+	for i, output := range outputs {
+		destinations[i] = builder.Destination{Address: output, Coins: uint64(coins[i])}
+		if len(hours) != 0 {
+			h := uint64(hours[i])
+			destinations[i].Hours = &h
+		}
+	}
+	return destinations, true
+}
+
+// reportBuildError writes the error returned by builder.Build, the invalid
+// fields are reported one per line
+func reportBuildError(err error) {
+	if _, ok := err.(skyWallet.ValidationErrors); ok {
+		reportInvalidFields(err)
+		return
+	}
+	log.Error(err)
+}
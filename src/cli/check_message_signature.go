@@ -41,7 +41,12 @@ func checkMessageSignatureCmd() gcli.Command {
 			signature := c.String("signature")
 			address := c.String("address")
 
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			if err := skyWallet.ValidateAddress(address); err != nil {
+				reportInvalidFields(skyWallet.ValidationErrors{{Field: "address", Err: err}})
+				return
+			}
+
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
@@ -4,10 +4,16 @@ Package cli implements an interface for creating a CLI application.
 package cli
 
 import (
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
 
 	"github.com/skycoin/skycoin/src/util/logging"
 	gcli "github.com/urfave/cli"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
 )
 
 const (
@@ -59,6 +65,18 @@ COPYRIGHT:
 `)
 
 	log = logging.MustGetLogger("skycoin-hw-cli")
+
+	// traceFile records the device traffic when the --traceFile flag is set
+	traceFile *os.File
+	// traceSecrets keeps the secrets in the trace when the --traceSecrets flag is set
+	traceSecrets bool
+
+	// passphraseStateFile keeps the passphrase state between runs when the --passphraseStateFile flag is set
+	passphraseStateFile string
+	// fingerprintBook is the path of the passphrase fingerprint nicknames file, set by --fingerprintBook
+	fingerprintBook string
+	// activeDevice is the instance returned by newDevice, if any
+	activeDevice *skyWallet.Device
 )
 
 // App Wraps the app so that main package won't use the raw App directly,
@@ -69,6 +87,7 @@ type App struct {
 
 // NewApp creates an app instance
 func NewApp() (*App, error) {
+	skyWallet.InstallLogRedaction()
 	gcli.AppHelpTemplate = appHelpTemplate
 	gcli.SubcommandHelpTemplate = commandHelpTemplate
 	gcli.CommandHelpTemplate = commandHelpTemplate
@@ -98,12 +117,73 @@ func NewApp() (*App, error) {
 		getRawEntropyCmd(),
 		getMixedEntropyCmd(),
 		getUsbDetails(),
+		dissectCmd(),
+		rawCmd(),
+		pingCmd(),
+		resetDeviceCmd(),
+		loadDeviceCmd(),
+		passphraseFingerprintCmd(),
+		validateMnemonicCmd(),
+		userEntropyMnemonicCmd(),
+		verifyBackupCmd(),
+		splitMnemonicCmd(),
+		combineSharesCmd(),
+		compareDevicesCmd(),
+		combineCmd(),
+		exportTransactionCmd(),
+		importTransactionCmd(),
+		buildTransactionCmd(),
+		sendCmd(),
 	}
 
 	app.Name = "skycoin-hw-cli"
 	app.Version = Version
 	app.Usage = "the skycoin hardware wallet command line interface"
 	app.Commands = commands
+	app.Flags = []gcli.Flag{
+		gcli.StringFlag{
+			Name:   "traceFile",
+			Usage:  "New file path to record every packet exchanged with the device, with the secrets blanked.",
+			EnvVar: "HW_TRACE_FILE",
+		},
+		gcli.BoolFlag{
+			Name:  "traceSecrets",
+			Usage: "Keep the mnemonics, PINs and passphrases in the --traceFile trace.",
+		},
+		gcli.StringFlag{
+			Name:   "passphraseStateFile",
+			Usage:  "File path to keep the passphrase state, so the same hidden wallet is used in the next runs.",
+			EnvVar: "HW_PASSPHRASE_STATE_FILE",
+		},
+		gcli.StringFlag{
+			Name:   "fingerprintBook",
+			Usage:  "JSON file mapping passphrase fingerprints to nicknames, unknown fingerprints are warned about.",
+			EnvVar: "HW_FINGERPRINT_BOOK",
+		},
+	}
+	app.Before = func(c *gcli.Context) error {
+		if path := c.GlobalString("traceFile"); path != "" {
+			// the trace may hold secrets and is only readable by its owner
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+			if err != nil {
+				return err
+			}
+			traceFile = f
+			traceSecrets = c.GlobalBool("traceSecrets")
+		}
+		passphraseStateFile = c.GlobalString("passphraseStateFile")
+		fingerprintBook = c.GlobalString("fingerprintBook")
+		return nil
+	}
+	app.After = func(c *gcli.Context) error {
+		if err := savePassphraseState(); err != nil {
+			return err
+		}
+		if traceFile != nil {
+			return traceFile.Close()
+		}
+		return nil
+	}
 	app.EnableBashCompletion = true
 	app.OnUsageError = func(context *gcli.Context, err error, _ bool) error {
 		fmt.Fprintf(context.App.Writer, "Error: %v\n\n", err)
@@ -129,3 +209,60 @@ func onCommandUsageError(command string) gcli.OnUsageErrorFunc {
 		return gcli.ShowCommandHelp(c, command)
 	}
 }
+
+// newDevice returns the device instance, recording its traffic if --traceFile is set
+// and resuming the passphrase session if --passphraseStateFile is set
+func newDevice(deviceType skyWallet.DeviceType) *skyWallet.Device {
+	device := skyWallet.NewDevice(deviceType)
+	if device == nil {
+		return nil
+	}
+	activeDevice = device
+	if traceFile != nil {
+		if driver, ok := device.Driver.(*skyWallet.Driver); ok {
+			if traceSecrets {
+				driver.RecordTraceWithSecrets(traceFile)
+			} else {
+				driver.RecordTrace(traceFile)
+			}
+		}
+	}
+	if err := loadPassphraseState(device); err != nil {
+		log.Error(err)
+		return nil
+	}
+	return device
+}
+
+// loadPassphraseState resumes the session saved in the passphrase state file
+func loadPassphraseState(device *skyWallet.Device) error {
+	if passphraseStateFile == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(passphraseStateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	state, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return fmt.Errorf("invalid passphrase state file %s: %v", passphraseStateFile, err)
+	}
+	device.SetPassphraseState(state)
+	_, err = device.Initialize()
+	return err
+}
+
+// savePassphraseState writes the passphrase state reported by the device to the passphrase state file
+func savePassphraseState() error {
+	if passphraseStateFile == "" || activeDevice == nil {
+		return nil
+	}
+	state := activeDevice.PassphraseState()
+	if len(state) == 0 {
+		return nil
+	}
+	return ioutil.WriteFile(passphraseStateFile, []byte(hex.EncodeToString(state)+"\n"), 0600)
+}
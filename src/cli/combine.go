@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	gcli "github.com/urfave/cli"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/psbt"
+)
+
+func combineCmd() gcli.Command {
+	name := "combine"
+	return gcli.Command{
+		Name:  name,
+		Usage: "Merge the signatures of partially signed transactions and print the final transaction. No device is needed.",
+		Description: `Each file is a copy of the same transaction signed by transactionSign --psbt with a different device.
+        Once all the inputs are signed the transaction is printed in hex, ready to be injected into a node. Otherwise
+        the unsigned inputs are listed and the merged transaction can be written with --outFile.`,
+		Flags: []gcli.Flag{
+			gcli.StringSliceFlag{
+				Name:  "psbt",
+				Usage: "Partially signed transaction file, repeat it for each file to merge",
+			},
+			gcli.StringFlag{
+				Name:  "outFile",
+				Usage: "File to write the merged partially signed transaction to",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			paths := c.StringSlice("psbt")
+			if len(paths) == 0 {
+				log.Error(errors.New("at least one partially signed transaction is needed"))
+				return
+			}
+
+			merged, err := psbt.Load(paths[0])
+			if err != nil {
+				log.Errorf("%s: %v", paths[0], err)
+				return
+			}
+			for _, path := range paths[1:] {
+				var other *psbt.Transaction
+				other, err = psbt.Load(path)
+				if err == nil {
+					err = merged.Merge(other)
+				}
+				if err != nil {
+					log.Errorf("%s: %v", path, err)
+					return
+				}
+			}
+
+			if outFile := c.String("outFile"); outFile != "" {
+				if err = merged.Save(outFile); err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			if unsigned := merged.Unsigned(); len(unsigned) != 0 {
+				for _, i := range unsigned {
+					fmt.Fprintf(os.Stderr, "Input %d owned by %s at address index %d is not signed\n", i+1, merged.Inputs[i].Address, merged.Inputs[i].AddressIndex)
+				}
+				log.Error(psbt.ErrNotSigned)
+				return
+			}
+
+			raw, err := merged.Encode()
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			id, err := merged.ID()
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Transaction ID: %s\n", id.Hex())
+			fmt.Println(hex.EncodeToString(raw))
+		},
+	}
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	gcli "github.com/urfave/cli"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/bip39"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/shamir"
+)
+
+func combineSharesCmd() gcli.Command {
+	name := "combineShares"
+	return gcli.Command{
+		Name:  name,
+		Usage: "Recombine the mnemonic from a quorum of shares and configure the device with it.",
+		Description: `The shares are read from stdin, one per line, they are prompted for without echo if stdin is a terminal.
+        The first address of the recombined mnemonic is checked against the address recorded by splitMnemonic
+        before the device is configured, then against the first address returned by the device.`,
+		Flags: []gcli.Flag{
+			gcli.StringFlag{
+				Name:  "address",
+				Usage: "First address of the wallet recorded by splitMnemonic",
+			},
+			secretsFdFlag(),
+			gcli.StringFlag{
+				Name:   "deviceType",
+				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
+				EnvVar: "DEVICE_TYPE",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			address := c.String("address")
+			if address == "" {
+				log.Error("address is required, use the first address printed by splitMnemonic")
+				return
+			}
+
+			input := secretsInput(c)
+			var shares []shamir.Share
+			for len(shares) == 0 || len(shares) < shares[0].Threshold {
+				line, err := input.readSecret(fmt.Sprintf("Share %d: ", len(shares)+1))
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				share, err := shamir.ParseShare(line)
+				if err != nil {
+					if e, ok := err.(*bip39.UnknownWordsError); ok {
+						reportInvalidMnemonic(e)
+						return
+					}
+					log.Errorf("share %d: %v", len(shares)+1, err)
+					return
+				}
+				shares = append(shares, share)
+			}
+
+			mnemonic, err := shamir.CombineMnemonic(shares)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			addresses, err := skyWallet.MnemonicAddresses(mnemonic, 1, 0)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if addresses[0] != address {
+				log.Errorf("recombined mnemonic gives the address %s instead of %s, the shares do not come from this split", addresses[0], address)
+				return
+			}
+
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			if device == nil {
+				return
+			}
+			defer device.Close()
+
+			if os.Getenv("AUTO_PRESS_BUTTONS") == "1" && device.Driver.DeviceType() == skyWallet.DeviceTypeEmulator && runtime.GOOS == "linux" {
+				err = device.SetAutoPressButton(true, skyWallet.ButtonRight)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			msg, err := device.SetMnemonic(mnemonic)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			responseMsg, err := skyWallet.DecodeSuccessOrFailMsg(msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if msg.Kind == uint16(messages.MessageType_MessageType_Failure) {
+				log.Error(responseMsg)
+				return
+			}
+			fmt.Println(responseMsg)
+
+			msg, err = device.AddressGen(1, 0, false)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if msg.Kind != uint16(messages.MessageType_MessageType_ResponseSkycoinAddress) {
+				failMsg, err := skyWallet.DecodeFailMsg(msg)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				log.Error(failMsg)
+				return
+			}
+			addresses, err = skyWallet.DecodeResponseSkycoinAddress(msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if len(addresses) == 0 || addresses[0] != address {
+				log.Errorf("device returned the address %v instead of %s, check no passphrase was entered", addresses, address)
+				os.Exit(1)
+			}
+			fmt.Printf("First address %s matches the recorded address\n", address)
+		},
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	gcli "github.com/urfave/cli"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func compareDevicesCmd() gcli.Command {
+	name := "compareDevices"
+	return gcli.Command{
+		Name:  name,
+		Usage: "Check that two devices hold the same seed by comparing the addresses they generate.",
+		Description: `The devices are selected by their path, as shown by getUsbDetails, or by their device_id, as shown by
+        features. Emulators are selected by path, i.e emulator21324 for the emulator listening on UDP port 21324.
+        The seeds never leave the devices, only the addresses are compared. The command exits with status 1 if
+        the addresses differ.`,
+		Flags: []gcli.Flag{
+			gcli.StringFlag{
+				Name:  "first",
+				Usage: "Path or device_id of the first device",
+			},
+			gcli.StringFlag{
+				Name:  "second",
+				Usage: "Path or device_id of the second device",
+			},
+			gcli.IntFlag{
+				Name:  "addressN",
+				Usage: "Number of addresses compared",
+				Value: 5,
+			},
+			gcli.IntFlag{
+				Name:  "startIndex",
+				Usage: "Index of the first address compared",
+				Value: 0,
+			},
+			gcli.StringFlag{
+				Name:   "deviceType",
+				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
+				EnvVar: "DEVICE_TYPE",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			selectors := []string{c.String("first"), c.String("second")}
+			if selectors[0] == "" || selectors[1] == "" {
+				log.Error("first and second devices are required")
+				return
+			}
+
+			deviceType := skyWallet.DeviceTypeFromString(c.String("deviceType"))
+			names := make(map[skyWallet.Devicer]string, len(selectors))
+			var devices []*skyWallet.Device
+			for _, selector := range selectors {
+				device, err := skyWallet.OpenDevice(deviceType, selector)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				defer device.Close()
+
+				if os.Getenv("AUTO_PRESS_BUTTONS") == "1" && device.Driver.DeviceType() == skyWallet.DeviceTypeEmulator && runtime.GOOS == "linux" {
+					err = device.SetAutoPressButton(true, skyWallet.ButtonRight)
+					if err != nil {
+						log.Error(err)
+						return
+					}
+				}
+				names[device] = selector
+				devices = append(devices, device)
+			}
+
+			// tell which device the PIN or the passphrase is asked for
+			handle := func(d skyWallet.Devicer, msg wire.Message) (wire.Message, error) {
+				switch msg.Kind {
+				case uint16(messages.MessageType_MessageType_PinMatrixRequest), uint16(messages.MessageType_MessageType_PassphraseRequest):
+					fmt.Fprintf(os.Stderr, "Device %s:\n", names[d])
+				}
+				return interact(d, msg)
+			}
+
+			comparison, err := skyWallet.CompareDevices(devices[0], devices[1], uint32(c.Int("addressN")), uint32(c.Int("startIndex")), handle)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			for i, address := range comparison.First {
+				second := ""
+				if i < len(comparison.Second) {
+					second = comparison.Second[i]
+				}
+				status := "ok"
+				if address != second {
+					status = "MISMATCH"
+				}
+				fmt.Printf("%4d  %-35s  %-35s  %s\n", comparison.StartIndex+uint32(i), address, second, status)
+			}
+
+			if !comparison.Match() {
+				fmt.Fprintf(os.Stderr, "Devices %s and %s do not hold the same seed, or a different passphrase is in use\n", selectors[0], selectors[1])
+				os.Exit(1)
+			}
+			fmt.Printf("Devices %s and %s hold the same seed\n", selectors[0], selectors[1])
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	gcli "github.com/urfave/cli"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/dissect"
+)
+
+func dissectCmd() gcli.Command {
+	name := "dissect"
+	return gcli.Command{
+		Name:        name,
+		Usage:       "Decode a capture of the device traffic into human readable messages.",
+		Description: "Reads a hex dump, an usbmon text log or a trace file recorded with --traceFile and prints every message as JSON.",
+		Flags: []gcli.Flag{
+			gcli.StringFlag{
+				Name:  "file",
+				Usage: `Capture file path, a "-" reads the capture from stdin.`,
+				Value: "-",
+			},
+			gcli.StringFlag{
+				Name:  "format",
+				Usage: "Capture format, one of auto, trace, hex or usbmon.",
+				Value: string(dissect.FormatAuto),
+			},
+			gcli.BoolFlag{
+				Name:  "showSecrets",
+				Usage: "Do not redact sensitive fields such as mnemonic, pin and passphrase.",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			var in io.Reader = os.Stdin
+			if file := c.String("file"); file != "-" {
+				f, err := os.Open(file)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				defer f.Close()
+				in = f
+			}
+
+			packets, err := dissect.ReadCapture(in, dissect.Format(c.String("format")))
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			enc := json.NewEncoder(os.Stdout)
+			showSecrets := c.Bool("showSecrets")
+			for _, frame := range dissect.Reassemble(packets) {
+				if err := enc.Encode(dissect.Dissect(frame, showSecrets)); err != nil {
+					log.Error(err)
+					return
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+)
+
+const (
+	entropyFormatRaw    = "raw"
+	entropyFormatHex    = "hex"
+	entropyFormatBase64 = "base64"
+)
+
+// nopWriteCloser adds a no-op Close method to an io.Writer
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// newEntropyEncoder wraps w so that bytes written are encoded using format
+func newEntropyEncoder(w io.Writer, format string) (io.WriteCloser, error) {
+	switch format {
+	case entropyFormatRaw:
+		return nopWriteCloser{w}, nil
+	case entropyFormatHex:
+		return nopWriteCloser{hex.NewEncoder(w)}, nil
+	case entropyFormatBase64:
+		return base64.NewEncoder(base64.StdEncoding, w), nil
+	default:
+		return nil, fmt.Errorf("invalid output format %q, valid options are %s, %s or %s",
+			format, entropyFormatRaw, entropyFormatHex, entropyFormatBase64)
+	}
+}
+
+// saveDeviceEntropy copies entropyBytes bytes of device entropy to outFile,
+// if `outFile` is the "-" string the output is written to stdout
+func saveDeviceEntropy(device *skyWallet.Device, entropyType skyWallet.EntropyType, entropyBytes int64, outFile, format string) error {
+	var out io.Writer = os.Stdout
+	var reporter skyWallet.ProgressReporter = skyWallet.NopProgressReporter{}
+	if outFile != "-" {
+		reporter = skyWallet.NewProgbar(os.Stderr)
+		log.Infoln("Saving entropy to", outFile)
+		file, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	enc, err := newEntropyEncoder(out, format)
+	if err != nil {
+		return err
+	}
+
+	r, err := device.EntropyReader(entropyType)
+	if err != nil {
+		return err
+	}
+
+	r = skyWallet.NewProgressReader(r, int(entropyBytes), reporter)
+	if _, err := io.CopyN(enc, r, entropyBytes); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	if format != entropyFormatRaw {
+		if _, err := fmt.Fprintln(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	gcli "github.com/urfave/cli"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/airgap"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/psbt"
+)
+
+func exportTransactionCmd() gcli.Command {
+	name := "exportTransaction"
+	return gcli.Command{
+		Name:  name,
+		Usage: "Carry a partially signed transaction to another host as a file or as animated QR codes. No device is needed.",
+		Description: `The online host exports the unsigned transaction, given by a file or by the transaction flags, the offline
+        host with the device imports it with importTransaction, signs it with transactionSign --psbt and exports
+        it back the same way.`,
+		Flags: append(append(transactionFlags(), verifyParamsFlags()...),
+			gcli.StringSliceFlag{
+				Name:  "inputAddress",
+				Usage: "Address owning the input, needed to create the partially signed transaction from the flags",
+			},
+			gcli.StringFlag{
+				Name:  "psbt",
+				Usage: "Partially signed transaction file to export",
+			},
+			gcli.StringFlag{
+				Name:  "outFile",
+				Usage: "File to write the partially signed transaction to, for example on removable media",
+			},
+			gcli.BoolFlag{
+				Name:  "qr",
+				Usage: "Show the transaction as a sequence of QR codes on the terminal",
+			},
+			gcli.DurationFlag{
+				Name:  "delay",
+				Value: 800 * time.Millisecond,
+				Usage: "Time each QR code is shown",
+			},
+			gcli.IntFlag{
+				Name:  "loops",
+				Value: 5,
+				Usage: "Number of times the sequence of QR codes is shown",
+			},
+		),
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			outFile := c.String("outFile")
+			if outFile == "" && !c.Bool("qr") {
+				log.Error(errors.New("nothing to export, give --outFile or --qr"))
+				return
+			}
+
+			transactionInputs, transactionOutputs, ok := transactionMessages(c)
+			if !ok {
+				return
+			}
+			var partial *psbt.Transaction
+			var err error
+			if len(transactionInputs) == 0 && len(transactionOutputs) == 0 {
+				if c.String("psbt") == "" {
+					log.Error(errors.New("give the transaction with --psbt or with the transaction flags"))
+					return
+				}
+				partial, err = psbt.Load(c.String("psbt"))
+			} else {
+				invalid := negativeAmounts("coin", c.Int64Slice("coin"))
+				invalid = append(invalid, negativeAmounts("hour", c.Int64Slice("hour"))...)
+				if len(invalid) != 0 {
+					reportInvalidFields(invalid)
+					return
+				}
+				partial, err = partialTransaction(outFile, transactionInputs, c.StringSlice("inputAddress"), transactionOutputs)
+			}
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			transactionInputs, transactionOutputs = skyWallet.PartialTransactionMessages(partial)
+			if err = verifyParams(c).ValidateTransaction(transactionInputs, transactionOutputs, nil); err != nil {
+				reportInvalidFields(err)
+				return
+			}
+
+			if outFile != "" {
+				if err = partial.Save(outFile); err != nil {
+					log.Error(err)
+					return
+				}
+				fmt.Fprintf(os.Stderr, "Transaction written to %s\n", outFile)
+			}
+			if !c.Bool("qr") {
+				return
+			}
+
+			payload, err := json.Marshal(partial)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			frames, err := airgap.Frames(payload, airgap.DefaultChunkSize)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if err = airgap.Animate(os.Stdout, frames, c.Duration("delay"), c.Int("loops")); err != nil {
+				log.Error(err)
+			}
+		},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
+	"os"
 
 	gcli "github.com/urfave/cli"
 
@@ -24,11 +25,12 @@ func firmwareUpdate() gcli.Command {
 		},
 		OnUsageError: onCommandUsageError(name),
 		Action: func(c *gcli.Context) {
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeUSB)
+			device := newDevice(skyWallet.DeviceTypeUSB)
 			if device == nil {
 				return
 			}
 			defer device.Close()
+			device.SetProgressReporter(skyWallet.NewProgbar(os.Stderr))
 
 			filePath := c.String("file")
 			fmt.Printf("File : %s\n", filePath)
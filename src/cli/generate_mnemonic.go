@@ -5,8 +5,6 @@ import (
 	"os"
 	"runtime"
 
-	messages "github.com/skycoin/hardware-wallet-protob/go"
-
 	gcli "github.com/urfave/cli"
 
 	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
@@ -39,7 +37,7 @@ func generateMnemonicCmd() gcli.Command {
 			usePassphrase := c.Bool("usePassphrase")
 			wordCount := uint32(c.Uint64("wordCount"))
 
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
@@ -59,13 +57,10 @@ func generateMnemonicCmd() gcli.Command {
 				return
 			}
 
-			if msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-				// Send ButtonAck
-				msg, err = device.ButtonAck()
-				if err != nil {
-					log.Error(err)
-					return
-				}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
 			}
 
 			responseMsg, err := skyWallet.DecodeSuccessOrFailMsg(msg)
@@ -12,21 +12,21 @@ func getMixedEntropyCmd() gcli.Command {
 		Name:  name,
 		Usage: "Get device internal mixed entropy and write it down to a file",
 		Action: func(c *gcli.Context) {
-			entropyBytes := uint32(c.Int("entropyBytes"))
+			entropyBytes := c.Int64("entropyBytes")
 			outFile := c.String("outFile")
 			if len(outFile) == 0 {
 				log.Error("outFile is mandatory")
 				return
 			}
 
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
 			defer device.Close()
 
 			log.Infoln("Getting mixed entropy from device")
-			if err := device.SaveDeviceEntropyInFile(outFile, entropyBytes, skyWallet.MessageDeviceGetMixedEntropy); err != nil {
+			if err := saveDeviceEntropy(device, skyWallet.EntropyTypeMixed, entropyBytes, outFile, c.String("format")); err != nil {
 				log.Error(err)
 				return
 			}
@@ -34,7 +34,7 @@ func getMixedEntropyCmd() gcli.Command {
 		OnUsageError: onCommandUsageError(name),
 		Subcommands:  nil,
 		Flags: []gcli.Flag{
-			gcli.IntFlag{
+			gcli.Int64Flag{
 				Name:  "entropyBytes",
 				Value: 1048576,
 				Usage: "Total number of how many bytes of mixed entropy to read.",
@@ -44,6 +44,11 @@ func getMixedEntropyCmd() gcli.Command {
 				Usage: `File path to write out the mixed entropy buffers, a "-" set the file to stdout.`,
 				Value: "-",
 			},
+			gcli.StringFlag{
+				Name:  "format",
+				Usage: "Output format of the mixed entropy buffers, one of raw, hex or base64.",
+				Value: "raw",
+			},
 			gcli.StringFlag{
 				Name:   "deviceType",
 				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
@@ -12,21 +12,21 @@ func getRawEntropyCmd() gcli.Command {
 		Name:  name,
 		Usage: "Get device raw internal entropy and write it down to a file",
 		Action: func(c *gcli.Context) {
-			entropyBytes := uint32(c.Int("entropyBytes"))
+			entropyBytes := c.Int64("entropyBytes")
 			outFile := c.String("outFile")
 			if len(outFile) == 0 {
 				log.Error("outFile is mandatory")
 				return
 			}
 
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
 			defer device.Close()
 
 			log.Infoln("Getting raw entropy from device")
-			if err := device.SaveDeviceEntropyInFile(outFile, entropyBytes, skyWallet.MessageDeviceGetRawEntropy); err != nil {
+			if err := saveDeviceEntropy(device, skyWallet.EntropyTypeRaw, entropyBytes, outFile, c.String("format")); err != nil {
 				log.Error(err)
 				return
 			}
@@ -34,7 +34,7 @@ func getRawEntropyCmd() gcli.Command {
 		OnUsageError: onCommandUsageError(name),
 		Subcommands:  nil,
 		Flags: []gcli.Flag{
-			gcli.IntFlag{
+			gcli.Int64Flag{
 				Name:  "entropyBytes",
 				Value: 1048576,
 				Usage: "Total number of how many bytes of raw entropy to read.",
@@ -44,6 +44,11 @@ func getRawEntropyCmd() gcli.Command {
 				Usage: `File path to write out the raw entropy buffers, a "-" set the file to stdout.`,
 				Value: "-",
 			},
+			gcli.StringFlag{
+				Name:  "format",
+				Usage: "Output format of the raw entropy buffers, one of raw, hex or base64.",
+				Value: "raw",
+			},
 			gcli.StringFlag{
 				Name:   "deviceType",
 				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	gcli "github.com/urfave/cli"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/airgap"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/psbt"
+)
+
+func importTransactionCmd() gcli.Command {
+	name := "importTransaction"
+	return gcli.Command{
+		Name:  name,
+		Usage: "Read a partially signed transaction shown as QR codes by exportTransaction. No device is needed.",
+		Description: `The frames of the QR codes are read from the standard input, one per line in any order, as most QR
+        scanners type them. A recording of the QR codes drawn on a terminal is read as well. If the output file
+        already holds the same transaction, the signatures read are added to it.`,
+		Flags: []gcli.Flag{
+			gcli.StringFlag{
+				Name:  "inFile",
+				Usage: "File to read the frames from instead of the standard input",
+			},
+			gcli.StringFlag{
+				Name:  "outFile",
+				Usage: "File to write the partially signed transaction to",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			outFile := c.String("outFile")
+			if outFile == "" {
+				log.Error(errors.New("give the file to write the transaction to with --outFile"))
+				return
+			}
+
+			var in io.Reader = os.Stdin
+			if inFile := c.String("inFile"); inFile != "" {
+				f, err := os.Open(inFile)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				defer f.Close()
+				in = f
+			} else {
+				fmt.Fprintln(os.Stderr, "Scan the QR codes, press Ctrl+D to stop")
+			}
+
+			payload, err := airgap.NewAssembler().Read(in, func(have, total int) {
+				fmt.Fprintf(os.Stderr, "Read frame %d of %d\n", have, total)
+			})
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			imported, err := psbt.Decode(bytes.NewReader(payload))
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			if _, err = os.Stat(outFile); err == nil {
+				var existing *psbt.Transaction
+				existing, err = psbt.Load(outFile)
+				if err == nil {
+					err = existing.Merge(imported)
+				}
+				if err != nil {
+					log.Errorf("%s: %v", outFile, err)
+					return
+				}
+				imported = existing
+			}
+			if err = imported.Save(outFile); err != nil {
+				log.Error(err)
+				return
+			}
+			signed := len(imported.Inputs) - len(imported.Unsigned())
+			fmt.Printf("Transaction written to %s, %d of %d inputs are signed\n", outFile, signed, len(imported.Inputs))
+		},
+	}
+}
@@ -3,8 +3,12 @@ package integration
 import (
 	"bufio"
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -52,6 +56,12 @@ func execCommandCombinedOutput(args ...string) ([]byte, error) {
 	return output, nil
 }
 
+func execCommandCombinedOutputWithInput(input string, args ...string) ([]byte, error) {
+	cmd := execCommand(args...)
+	cmd.Stdin = strings.NewReader(input)
+	return cmd.CombinedOutput()
+}
+
 func mode(t *testing.T) string {
 	mode := os.Getenv("HW_GO_INTEGRATION_TEST_MODE")
 	switch mode {
@@ -239,6 +249,27 @@ func TestAddressGen(t *testing.T) {
 	}
 }
 
+func TestCompareDevices(t *testing.T) {
+	device := bootstrap(t, "TestCompareDevices", "EMULATOR")
+	if device == nil {
+		return
+	}
+
+	// the emulator compared with itself
+	output, err := execCommandCombinedOutput("compareDevices", "--first", "emulator21324", "--second", "emulator21324", "--addressN", "2")
+	if err != nil {
+		require.EqualError(t, err, "exit status 1")
+	}
+	require.Contains(t, string(output), "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw")
+	require.Contains(t, string(output), "hold the same seed")
+
+	output, err = execCommandCombinedOutput("compareDevices", "--first", "emulator21324", "--second", "unknown-device-id")
+	if err != nil {
+		require.EqualError(t, err, "exit status 1")
+	}
+	require.Contains(t, string(output), "no device has this path or device id")
+}
+
 func TestApplySettings(t *testing.T) {
 	device := bootstrap(t, "TestApplySettings", "")
 	if device == nil {
@@ -396,6 +427,20 @@ func TestFeatures(t *testing.T) {
 	require.False(t, ff.HasRdpMemProtectEnabled())
 }
 
+func TestPing(t *testing.T) {
+	device := bootstrap(t, "TestPing", "")
+	if device == nil {
+		return
+	}
+
+	output, err := execCommandCombinedOutput([]string{"ping", "--message", "Hello World"}...)
+	if err != nil {
+		require.Equal(t, err, "exit status 1")
+	}
+
+	require.Contains(t, string(output), "Hello World")
+}
+
 func TestGenerateMnemonic(t *testing.T) {
 	if !doWalletOrEmulator(t) {
 		return
@@ -579,28 +624,31 @@ func TestSetMnemonic(t *testing.T) {
 
 	tt := []struct {
 		name           string
-		args           []string
+		mnemonic       string
 		expectedOutput string
 	}{
 		{
-			name: "setMnemonic 12",
-			args: []string{"setMnemonic", "--mnemonic",
-				"cloud flower upset remain green metal below cup stem infant art thank"},
+			name:           "setMnemonic 12",
+			mnemonic:       "cloud flower upset remain green metal below cup stem infant art thank",
 			expectedOutput: "cloud flower upset remain green metal below cup stem infant art thank",
 		},
 
 		{
-			name: "setMnemonic 24",
-			args: []string{"setMnemonic", "--mnemonic",
-				"dress fee animal silly multiply demand casino gold pipe matrix latin badge umbrella orbit safe cover glove one dash chicken play obey employ post"},
+			name:           "setMnemonic 24",
+			mnemonic:       "dress fee animal silly multiply demand casino gold pipe matrix latin badge umbrella orbit safe cover glove one dash chicken play obey employ post",
 			expectedOutput: "dress fee animal silly multiply demand casino gold pipe matrix latin badge umbrella orbit safe cover glove one dash chicken play obey employ post",
 		},
 
 		{
-			name: "setMnemonic invalid mnemonic length",
-			args: []string{"setMnemonic", "--mnemonic",
-				"dress fee animal silly multiply demand casino gold pipe matrix latin badge umbrella orbit safe cover glove one dash chicken play obey"},
-			expectedOutput: "Mnemonic with wrong checksum provided",
+			name:           "setMnemonic invalid mnemonic length",
+			mnemonic:       "dress fee animal silly multiply demand casino gold pipe matrix latin badge umbrella orbit safe cover glove one dash chicken play obey",
+			expectedOutput: "mnemonic must have 12 or 24 words",
+		},
+
+		{
+			name:           "setMnemonic invalid checksum",
+			mnemonic:       "cloud flower upset remain green metal below cup stem infant art zoo",
+			expectedOutput: "Invalid mnemonic checksum",
 		},
 	}
 
@@ -612,7 +660,7 @@ func TestSetMnemonic(t *testing.T) {
 			_, err = device.ButtonAck()
 			require.NoError(t, err)
 
-			output, err := execCommandCombinedOutput(tc.args...)
+			output, err := execCommandCombinedOutputWithInput(tc.mnemonic+"\n", "setMnemonic")
 			if err != nil {
 				require.Equal(t, err, "exit status 1")
 			}
@@ -623,6 +671,34 @@ func TestSetMnemonic(t *testing.T) {
 
 }
 
+func TestCombineShares(t *testing.T) {
+	device := bootstrap(t, "TestCombineShares", "")
+	if device == nil {
+		return
+	}
+
+	output, err := execCommandCombinedOutputWithInput(defaultSeed+"\n", "splitMnemonic", "--threshold", "2", "--shares", "3")
+	require.NoError(t, err)
+	var shares []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if i := strings.Index(line, "/3: "); strings.HasPrefix(line, "Share ") && i != -1 {
+			shares = append(shares, line[i+len("/3: "):])
+		}
+	}
+	require.Len(t, shares, 3)
+
+	_, err = device.Wipe()
+	require.NoError(t, err)
+	_, err = device.ButtonAck()
+	require.NoError(t, err)
+
+	output, err = execCommandCombinedOutputWithInput(shares[0]+"\n"+shares[2]+"\n", "combineShares", "--address", "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw")
+	if err != nil {
+		require.Equal(t, err, "exit status 1")
+	}
+	require.Contains(t, string(output), "matches the recorded address")
+}
+
 func TestSetPinCode(t *testing.T) {
 	// This test checks for failure on invalid input
 	// It is not possible to programmatically check for valid input
@@ -728,7 +804,7 @@ func TestTransactionSign(t *testing.T) {
 	}{
 		{
 			name: "transactionSign sample 1",
-			args: []string{"transactionSign", "--inputHash", "181bd5656115172fe81451fae4fb56498a97744d89702e73da75ba91ed5200f9",
+			args: []string{"transactionSign", "--yes", "--inputHash", "181bd5656115172fe81451fae4fb56498a97744d89702e73da75ba91ed5200f9",
 				"--inputIndex", "0", "--outputAddress=K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", "--coin", "100000", "--hour", "2"},
 			message: []string{"d11c62b1e0e9abf629b1f5f4699cef9fbc504b45ceedf0047ead686979498218"},
 			address: []string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"},
@@ -736,7 +812,7 @@ func TestTransactionSign(t *testing.T) {
 
 		{
 			name: "transactionSign sample 2",
-			args: []string{"transactionSign", "--inputHash", "01a9ef6c25271229ef9760e1536c3dc5ccf0ead7de93a64c12a01340670d87e9",
+			args: []string{"transactionSign", "--yes", "--inputHash", "01a9ef6c25271229ef9760e1536c3dc5ccf0ead7de93a64c12a01340670d87e9",
 				"--inputHash", "8c2c97bfd34e0f0f9833b789ce03c2e80ac0b94b9d0b99cee6ea76fb662e8e1c", "--inputIndex", "0", "--inputIndex", "0",
 				"--outputAddress=K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", "--coin", "20800000", "--hour", "255"},
 			message: []string{"9bbde062d665a8b11ae15aee6d4f32f0f3d61af55160c142060795a219378a54", "f947b0352b19672f7b7d04dc2f1fdc47bc5355878f3c47a43d4d4cfbae07d026"},
@@ -745,7 +821,7 @@ func TestTransactionSign(t *testing.T) {
 
 		{
 			name: "transactionSign sample 3",
-			args: []string{"transactionSign", "--inputHash", "da3b5e29250289ad78dc42dcf007ab8f61126198e71e8306ff8c11696a0c40f7", "--inputIndex", "0",
+			args: []string{"transactionSign", "--yes", "--inputHash", "da3b5e29250289ad78dc42dcf007ab8f61126198e71e8306ff8c11696a0c40f7", "--inputIndex", "0",
 				"--inputHash", "33e826d62489932905dd936d3edbb74f37211d68d4657689ed4b8027edcad0fb", "--inputIndex", "0",
 				"--inputHash", "668f4c144ad2a4458eaef89a38f10e5307b4f0e8fce2ade96fb2cc2409fa6592", "--inputIndex", "0",
 				"--outputAddress=K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", "--coin", "111000000", "--hour", "6464556",
@@ -758,7 +834,7 @@ func TestTransactionSign(t *testing.T) {
 
 		{
 			name: "transactionSign sample 4",
-			args: []string{"transactionSign", "--inputHash", "b99f62c5b42aec6be97f2ca74bb1a846be9248e8e19771943c501e0b48a43d82", "--inputIndex", "0",
+			args: []string{"transactionSign", "--yes", "--inputHash", "b99f62c5b42aec6be97f2ca74bb1a846be9248e8e19771943c501e0b48a43d82", "--inputIndex", "0",
 				"--inputHash", "cd13f705d9c1ce4ac602e4c4347e986deab8e742eae8996b34c429874799ebb2", "--inputIndex", "0",
 				"--outputAddress=22S8njPeKUNJBijQjNCzaasXVyf22rWv7gF", "--coin", "23100000", "--hour", "0"},
 			message: []string{"42a26380399172f2024067a17704fceda607283a0f17cb0024ab7a96fc6e4ac6",
@@ -768,7 +844,7 @@ func TestTransactionSign(t *testing.T) {
 
 		{
 			name: "transactionSign sample 5",
-			args: []string{"transactionSign", "--inputHash", "4c12fdd28bd580989892b0518f51de3add96b5efb0f54f0cd6115054c682e1f1", "--inputIndex", "0",
+			args: []string{"transactionSign", "--yes", "--inputHash", "4c12fdd28bd580989892b0518f51de3add96b5efb0f54f0cd6115054c682e1f1", "--inputIndex", "0",
 				"--outputAddress=2iNNt6fm9LszSWe51693BeyNUKX34pPaLx8", "--coin", "1000000", "--hour", "0"},
 			message: []string{"c40e110f5e460532bfb03a5a0e50262d92d8913a89c87869adb5a443463dea69"},
 			address: []string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"},
@@ -776,7 +852,7 @@ func TestTransactionSign(t *testing.T) {
 
 		{
 			name: "transactionSign sample 6",
-			args: []string{"transactionSign", "--inputHash", "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", "--inputIndex", "0",
+			args: []string{"transactionSign", "--yes", "--inputHash", "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", "--inputIndex", "0",
 				"--outputAddress=K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", "--coin", "10000000", "--hour", "1",
 				"--outputAddress=VNz8LR9JTSoz5o7qPHm3QHj4EiJB6LV18L", "--coin", "5500000", "--hour", "0",
 				"--outputAddress=22S8njPeKUNJBijQjNCzaasXVyf22rWv7gF", "--coin", "4500000", "--hour", "1"},
@@ -786,7 +862,7 @@ func TestTransactionSign(t *testing.T) {
 
 		{
 			name: "transactionSign sample 7",
-			args: []string{"transactionSign", "--inputHash", "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", "--inputIndex", "0",
+			args: []string{"transactionSign", "--yes", "--inputHash", "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", "--inputIndex", "0",
 				"--outputAddress=3pXt9MSQJkwgPXLNePLQkjKq8tsRnFZGQA", "--coin", "1000000", "--hour", "1000"},
 			message: []string{"47bfa37c79f7960df8e8a421250922c5165167f4c91ecca5682c1106f9010a7f"},
 			address: []string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"},
@@ -794,7 +870,7 @@ func TestTransactionSign(t *testing.T) {
 
 		{
 			name: "transactionSign sample 8",
-			args: []string{"transactionSign", "--inputHash", "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", "--inputIndex", "0",
+			args: []string{"transactionSign", "--yes", "--inputHash", "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", "--inputIndex", "0",
 				"--outputAddress=3pXt9MSQJkwgPXLNePLQkjKq8tsRnFZGQA", "--coin", "300000", "--hour", "500",
 				"--outputAddress=S6Dnv6gRTgsHCmZQxjN7cX5aRjJvDvqwp9", "--coin", "700000", "--hour", "500"},
 			message: []string{"e0c6e4982b1b8c33c5be55ac115b69be68f209c5d9054954653e14874664b57d"},
@@ -810,7 +886,7 @@ func TestTransactionSign(t *testing.T) {
 			}
 
 			lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-			resp := removeCharacters(lines[1], "[]")
+			resp := removeCharacters(lines[len(lines)-1], "[]")
 			signatures := strings.Fields(resp)
 
 			for n, signature := range signatures {
@@ -825,6 +901,179 @@ func TestTransactionSign(t *testing.T) {
 	}
 }
 
+func TestTransactionSignInvalidFields(t *testing.T) {
+	device := bootstrap(t, "TestTransactionSignInvalidFields", "")
+	if device == nil {
+		return
+	}
+
+	output, err := execCommandCombinedOutput([]string{"transactionSign", "--yes",
+		"--inputHash", "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc", "--inputIndex", "0",
+		"--outputAddress=K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2oO", "--coin", "0", "--hour", "1",
+		"--outputAddress=K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", "--coin", "1000001", "--hour", "1"}...)
+	if err != nil {
+		require.Equal(t, err, "exit status 1")
+	}
+
+	require.Contains(t, string(output), "Invalid inputHash 1:")
+	require.Contains(t, string(output), "Invalid outputAddress 1:")
+	require.Contains(t, string(output), "Invalid coin 1: coins must not be zero")
+	require.Contains(t, string(output), "Invalid coin 2: amount has more decimals than the node allows")
+}
+
+func TestPartiallySignedTransaction(t *testing.T) {
+	device := bootstrap(t, "TestPartiallySignedTransaction", "")
+	if device == nil {
+		return
+	}
+	dir, err := ioutil.TempDir("", "psbt")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "transaction.json")
+
+	// the second input is owned by another wallet
+	output, err := execCommandCombinedOutput([]string{"transactionSign", "--yes", "--psbt", path,
+		"--inputHash", "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", "--inputIndex", "0", "--inputAddress", "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw",
+		"--inputHash", "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", "--inputIndex", "0", "--inputAddress", "K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot",
+		"--outputAddress=22S8njPeKUNJBijQjNCzaasXVyf22rWv7gF", "--coin", "2000000", "--hour", "1"}...)
+	if err != nil {
+		require.Equal(t, err, "exit status 1")
+	}
+	require.Contains(t, string(output), "Signed input 1")
+	require.Contains(t, string(output), "1 of 2 inputs are still unsigned")
+
+	output, err = execCommandCombinedOutput([]string{"combine", "--psbt", path}...)
+	if err != nil {
+		require.Equal(t, err, "exit status 1")
+	}
+	require.Contains(t, string(output), "Input 2 owned by K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot at address index 0 is not signed")
+}
+
+func TestAirgapTransactionSign(t *testing.T) {
+	device := bootstrap(t, "TestAirgapTransactionSign", "")
+	if device == nil {
+		return
+	}
+	dir, err := ioutil.TempDir("", "airgap")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	online := filepath.Join(dir, "online.json")
+	offline := filepath.Join(dir, "offline.json")
+
+	// the online host exports the unsigned transaction as QR codes
+	output, err := execCommandCombinedOutput([]string{"exportTransaction", "--qr", "--delay", "0", "--loops", "1", "--outFile", online,
+		"--inputHash", "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", "--inputIndex", "0", "--inputAddress", "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw",
+		"--inputHash", "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", "--inputIndex", "0", "--inputAddress", "K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot",
+		"--outputAddress=22S8njPeKUNJBijQjNCzaasXVyf22rWv7gF", "--coin", "2000000", "--hour", "1"}...)
+	require.NoError(t, err)
+	codes := filepath.Join(dir, "codes.txt")
+	require.NoError(t, ioutil.WriteFile(codes, output, 0600))
+
+	// the offline host reads them, signs and exports the signatures back
+	output, err = execCommandCombinedOutput([]string{"importTransaction", "--inFile", codes, "--outFile", offline}...)
+	require.NoError(t, err)
+	require.Contains(t, string(output), "0 of 2 inputs are signed")
+	output, err = execCommandCombinedOutput([]string{"transactionSign", "--yes", "--psbt", offline}...)
+	if err != nil {
+		require.Equal(t, err, "exit status 1")
+	}
+	require.Contains(t, string(output), "Signed input 1")
+	output, err = execCommandCombinedOutput([]string{"exportTransaction", "--qr", "--delay", "0", "--loops", "1", "--psbt", offline}...)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(codes, output, 0600))
+
+	// the online host adds the signatures to its copy
+	output, err = execCommandCombinedOutput([]string{"importTransaction", "--inFile", codes, "--outFile", online}...)
+	require.NoError(t, err)
+	require.Contains(t, string(output), "1 of 2 inputs are signed")
+}
+
+func TestBuildTransaction(t *testing.T) {
+	device := bootstrap(t, "TestBuildTransaction", "")
+	if device == nil {
+		return
+	}
+	dir, err := ioutil.TempDir("", "build")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	utxos := filepath.Join(dir, "utxos.json")
+	path := filepath.Join(dir, "transaction.json")
+	require.NoError(t, ioutil.WriteFile(utxos, []byte(`[
+		{"hash": "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", "address": "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "coins": 2000000, "hours": 10},
+		{"hash": "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", "address": "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "coins": 5000000, "hours": 2}
+	]`), 0600))
+
+	output, err := execCommandCombinedOutput([]string{"buildTransaction", "--utxos", utxos, "--psbt", path,
+		"--walletAddress", "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw",
+		"--outputAddress", "22S8njPeKUNJBijQjNCzaasXVyf22rWv7gF", "--coin", "6000000"}...)
+	require.NoError(t, err)
+	require.Contains(t, string(output), "change, address index 0")
+
+	output, err = execCommandCombinedOutput([]string{"transactionSign", "--yes", "--psbt", path}...)
+	if err != nil {
+		require.Equal(t, err, "exit status 1")
+	}
+	require.Contains(t, string(output), "Signed input 1")
+	require.Contains(t, string(output), "Signed input 2")
+}
+
+func TestSend(t *testing.T) {
+	device := bootstrap(t, "TestSend", "")
+	if device == nil {
+		return
+	}
+
+	// stand-in node holding an output of the first address of the device
+	var injected []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/outputs":
+			fmt.Fprint(w, `{"head_outputs": [{"hash": "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59",
+				"address": "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "coins": "5.000000", "calculated_hours": 10}], "outgoing_outputs": []}`)
+		case "/api/v1/injectTransaction":
+			var req struct {
+				RawTx string `json:"rawtx"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			injected = append(injected, req.RawTx)
+			fmt.Fprint(w, `"d9ec2aff07b5e0b0b2846ff32f95d0292e906173f8d8cecd4703152fe438d9b9"`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	output, err := execCommandCombinedOutput([]string{"send", "--yes", "--node", server.URL, "--addressN", "2",
+		"--outputAddress", "22S8njPeKUNJBijQjNCzaasXVyf22rWv7gF", "--coin", "2000000"}...)
+	if err != nil {
+		require.Equal(t, err, "exit status 1")
+	}
+	require.Contains(t, string(output), "change, address index 0")
+	require.Contains(t, string(output), "Transaction ID: d9ec2aff07b5e0b0b2846ff32f95d0292e906173f8d8cecd4703152fe438d9b9")
+	require.Len(t, injected, 1)
+}
+
+func TestTransactionSignForeignChange(t *testing.T) {
+	device := bootstrap(t, "TestTransactionSignForeignChange", "")
+	if device == nil {
+		return
+	}
+
+	// 2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw is the address at index 0, not 1
+	output, err := execCommandCombinedOutput([]string{"transactionSign", "--yes",
+		"--inputHash", "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", "--inputIndex", "0",
+		"--outputAddress=2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "--coin", "5500000", "--hour", "0", "--addressIndex", "1",
+		"--outputAddress=K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", "--coin", "10000000", "--hour", "1"}...)
+	if err != nil {
+		require.Equal(t, err, "exit status 1")
+	}
+
+	require.Contains(t, string(output), "is not the device address zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs")
+}
+
 func TestWipe(t *testing.T) {
 	output, err := execCommandCombinedOutput([]string{"wipe"}...)
 	if err != nil {
@@ -847,6 +1096,20 @@ func TestWipe(t *testing.T) {
 	require.False(t, *features.Initialized)
 }
 
+func TestVerifyBackupNotInitialized(t *testing.T) {
+	output, err := execCommandCombinedOutput([]string{"wipe"}...)
+	if err != nil {
+		require.Equal(t, err, "exit status 1")
+	}
+	require.Contains(t, string(output), "Device wiped")
+
+	output, err = execCommandCombinedOutput([]string{"verifyBackup"}...)
+	if err != nil {
+		require.EqualError(t, err, "exit status 1")
+	}
+	require.Contains(t, string(output), "device is not initialized")
+}
+
 func TestGetMixedEntropy(t *testing.T) {
 	device := bootstrap(t, "TestGetMixedEntropy", "USB")
 	if device == nil {
@@ -854,14 +1117,15 @@ func TestGetMixedEntropy(t *testing.T) {
 	}
 
 	bytesNum := 4
-	output, err := execCommandCombinedOutput([]string{"getMixedEntropy", "--entropyBytes", fmt.Sprintf("%d", bytesNum)}...)
+	output, err := execCommandCombinedOutput([]string{"getMixedEntropy", "--entropyBytes", fmt.Sprintf("%d", bytesNum), "--format", "hex"}...)
 	if err != nil {
 		require.Equal(t, err, "exit status 1")
 	}
 
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	stripped := strings.ReplaceAll(lines[1], "]", "")
-	require.Len(t, strings.Fields(stripped), bytesNum)
+	entropy, err := hex.DecodeString(lines[1])
+	require.NoError(t, err)
+	require.Len(t, entropy, bytesNum)
 }
 
 func TestGetRawEntropy(t *testing.T) {
@@ -871,14 +1135,15 @@ func TestGetRawEntropy(t *testing.T) {
 	}
 
 	bytesNum := 4
-	output, err := execCommandCombinedOutput([]string{"getRawEntropy", "--entropyBytes", fmt.Sprintf("%d", bytesNum)}...)
+	output, err := execCommandCombinedOutput([]string{"getRawEntropy", "--entropyBytes", fmt.Sprintf("%d", bytesNum), "--format", "hex"}...)
 	if err != nil {
 		require.Equal(t, err, "exit status 1")
 	}
 
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	stripped := strings.ReplaceAll(lines[1], "]", "")
-	require.Len(t, strings.Fields(stripped), bytesNum)
+	entropy, err := hex.DecodeString(lines[1])
+	require.NoError(t, err)
+	require.Len(t, entropy, bytesNum)
 }
 
 func removeCharacters(input string, characters string) string {
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	gcli "github.com/urfave/cli"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/bip39"
+)
+
+func loadDeviceCmd() gcli.Command {
+	name := "loadDevice"
+	return gcli.Command{
+		Name:        name,
+		Usage:       "Load a mnemonic into the device without user interaction, meant for testing.",
+		Description: "The seed is sent in clear text, do not use this command with wallets holding funds. The mnemonic, then the PIN code if --usePin is set, are read from stdin, they are prompted for without echo if stdin is a terminal.",
+		Flags: []gcli.Flag{
+			secretsFdFlag(),
+			gcli.BoolFlag{
+				Name:  "usePin",
+				Usage: "Configure a PIN code, read after the mnemonic.",
+			},
+			gcli.StringFlag{
+				Name:  "label",
+				Usage: "Configure a device label",
+			},
+			gcli.BoolFlag{
+				Name:  "usePassphrase",
+				Usage: "Configure a passphrase",
+			},
+			gcli.BoolFlag{
+				Name:  "skipChecksum",
+				Usage: "Do not validate the mnemonic words and checksum, neither on the host nor on the device.",
+			},
+			gcli.StringFlag{
+				Name:   "deviceType",
+				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
+				EnvVar: "DEVICE_TYPE",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			if device == nil {
+				return
+			}
+			defer device.Close()
+
+			if os.Getenv("AUTO_PRESS_BUTTONS") == "1" && device.Driver.DeviceType() == skyWallet.DeviceTypeEmulator && runtime.GOOS == "linux" {
+				err := device.SetAutoPressButton(true, skyWallet.ButtonRight)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			input := secretsInput(c)
+			mnemonic, err := input.readMnemonic()
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if !c.Bool("skipChecksum") {
+				if err := bip39.ValidateMnemonic(mnemonic); err != nil {
+					reportInvalidMnemonic(err)
+					return
+				}
+			}
+			var pin string
+			if c.Bool("usePin") {
+				pin, err = input.readSecret("PIN: ")
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			msg, err := device.LoadDevice(mnemonic, nil, pin, c.String("label"), c.Bool("usePassphrase"), c.Bool("skipChecksum"))
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			responseMsg, err := skyWallet.DecodeSuccessOrFailMsg(msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			fmt.Println(responseMsg)
+		},
+	}
+}
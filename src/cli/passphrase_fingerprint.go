@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	gcli "github.com/urfave/cli"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+)
+
+func passphraseFingerprintCmd() gcli.Command {
+	name := "passphraseFingerprint"
+	return gcli.Command{
+		Name:        name,
+		Usage:       "Show the fingerprint of the wallet opened by the passphrase and optionally give it a nickname.",
+		Description: "The fingerprint is a hash of the first address of the wallet, a typo in the passphrase opens a different wallet with a different fingerprint.",
+		Flags: []gcli.Flag{
+			gcli.StringFlag{
+				Name:  "nickname",
+				Usage: "Save the fingerprint with this nickname in the --fingerprintBook.",
+			},
+			gcli.StringFlag{
+				Name:   "deviceType",
+				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
+				EnvVar: "DEVICE_TYPE",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			nickname := c.String("nickname")
+			if nickname != "" && fingerprintBook == "" {
+				log.Error(errors.New("--nickname requires the global --fingerprintBook option"))
+				return
+			}
+
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			if device == nil {
+				return
+			}
+			defer device.Close()
+
+			if os.Getenv("AUTO_PRESS_BUTTONS") == "1" && device.Driver.DeviceType() == skyWallet.DeviceTypeEmulator && runtime.GOOS == "linux" {
+				err := device.SetAutoPressButton(true, skyWallet.ButtonRight)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			msg, err := device.AddressGen(1, 0, false)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			msg, _, err = answerRequests(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			if msg.Kind != uint16(messages.MessageType_MessageType_ResponseSkycoinAddress) {
+				failMsg, err := skyWallet.DecodeFailMsg(msg)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				fmt.Printf("Failed with message: %s\n", failMsg)
+				return
+			}
+
+			addresses, err := skyWallet.DecodeResponseSkycoinAddress(msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if len(addresses) == 0 {
+				log.Error("device returned no address")
+				return
+			}
+			fp := skyWallet.FingerprintAddress(addresses[0])
+			fmt.Println(fp)
+
+			if nickname == "" {
+				return
+			}
+			book, err := skyWallet.LoadFingerprintBook(fingerprintBook)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			book.Add(fp, nickname)
+			if err := book.Save(fingerprintBook); err != nil {
+				log.Error(err)
+				return
+			}
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	gcli "github.com/urfave/cli"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+)
+
+func pingCmd() gcli.Command {
+	name := "ping"
+	return gcli.Command{
+		Name:        name,
+		Usage:       "Send a message the device echoes back, useful to check it is alive and unlocked.",
+		Description: "",
+		Flags: []gcli.Flag{
+			gcli.StringFlag{
+				Name:  "message",
+				Usage: "The message the device should echo back.",
+				Value: "ping",
+			},
+			gcli.BoolFlag{
+				Name:  "buttonProtection",
+				Usage: "Ask the user to press a button before answering.",
+			},
+			gcli.BoolFlag{
+				Name:  "pinProtection",
+				Usage: "Ask for the PIN code before answering.",
+			},
+			gcli.BoolFlag{
+				Name:  "passphraseProtection",
+				Usage: "Ask for the passphrase before answering.",
+			},
+			gcli.StringFlag{
+				Name:   "deviceType",
+				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
+				EnvVar: "DEVICE_TYPE",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			if device == nil {
+				return
+			}
+			defer device.Close()
+
+			if os.Getenv("AUTO_PRESS_BUTTONS") == "1" && device.Driver.DeviceType() == skyWallet.DeviceTypeEmulator && runtime.GOOS == "linux" {
+				err := device.SetAutoPressButton(true, skyWallet.ButtonRight)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			msg, err := device.Ping(c.String("message"), c.Bool("buttonProtection"), c.Bool("pinProtection"), c.Bool("passphraseProtection"))
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			responseMsg, err := skyWallet.DecodeSuccessOrFailMsg(msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			fmt.Println(responseMsg)
+		},
+	}
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"runtime"
+
+	gcli "github.com/urfave/cli"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/codec"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/redact"
+)
+
+// rawResponse is the JSON output of the raw command
+type rawResponse struct {
+	Kind    string      `json:"kind"`
+	Message interface{} `json:"message"`
+}
+
+func rawCmd() gcli.Command {
+	name := "raw"
+	return gcli.Command{
+		Name:        name,
+		Usage:       "Send an arbitrary protobuf message to the device.",
+		Description: "Encodes a JSON body into the protobuf message named by --type, handles the button, PIN and passphrase requests and prints the device answer as JSON.",
+		Flags: []gcli.Flag{
+			gcli.StringFlag{
+				Name:  "type",
+				Usage: "Message type name, i.e Ping or GetFeatures.",
+			},
+			gcli.StringFlag{
+				Name:  "body",
+				Usage: `Message body as JSON, a "-" reads the body from stdin. Required for the messages carrying secrets.`,
+				Value: "{}",
+			},
+			gcli.StringFlag{
+				Name:   "deviceType",
+				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
+				EnvVar: "DEVICE_TYPE",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			kind, err := codec.KindByName(c.String("type"))
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			if codec.Sensitive(kind) && c.IsSet("body") && c.String("body") != "-" {
+				log.Errorf("%s carries secrets, give its body on stdin with --body - so it is not kept in the shell history or seen in the process list", codec.Name(kind))
+				return
+			}
+
+			body := []byte(c.String("body"))
+			if c.String("body") == "-" {
+				body, err = ioutil.ReadAll(os.Stdin)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			req, err := codec.New(kind)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			err = json.Unmarshal(body, req)
+			redact.Wipe(body)
+			if err != nil {
+				log.Errorf("invalid %s body: %v", codec.Name(kind), err)
+				return
+			}
+
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			if device == nil {
+				return
+			}
+			defer device.Close()
+
+			if os.Getenv("AUTO_PRESS_BUTTONS") == "1" && device.Driver.DeviceType() == skyWallet.DeviceTypeEmulator && runtime.GOOS == "linux" {
+				err := device.SetAutoPressButton(true, skyWallet.ButtonRight)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			msg, err := device.Call(kind, req)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			resp, err := codec.Decode(msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "\t")
+			if err := enc.Encode(rawResponse{
+				Kind:    codec.Name(msg.Kind),
+				Message: resp,
+			}); err != nil {
+				log.Error(err)
+				return
+			}
+		},
+	}
+}
@@ -40,7 +40,7 @@ func recoveryCmd() gcli.Command {
 		},
 		OnUsageError: onCommandUsageError(name),
 		Action: func(c *gcli.Context) {
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
@@ -68,33 +68,24 @@ func recoveryCmd() gcli.Command {
 				return
 			}
 
-			if msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-				msg, err = device.ButtonAck()
+			msg, err = interact(device, msg)
+			input := newTerminalInput(os.Stderr)
+			for err == nil && msg.Kind == uint16(messages.MessageType_MessageType_WordRequest) {
+				var word string
+				word, err = input.readWord()
 				if err != nil {
-					log.Error(err)
-					return
+					break
 				}
-			}
-
-			for msg.Kind == uint16(messages.MessageType_MessageType_WordRequest) {
-				var word string
-				fmt.Printf("Word: ")
-				fmt.Scanln(&word)
 				msg, err = device.WordAck(word)
 				if err != nil {
-					log.Error(err.Error())
+					log.Error(err)
 					os.Exit(1)
-					return
 				}
+				msg, err = interact(device, msg)
 			}
-
-			if msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-				// Send ButtonAck
-				msg, err = device.ButtonAck()
-				if err != nil {
-					log.Error(err)
-					return
-				}
+			if err != nil {
+				log.Error(err)
+				return
 			}
 
 			responseMsg, err := skyWallet.DecodeSuccessOrFailMsg(msg)
@@ -7,8 +7,6 @@ import (
 
 	gcli "github.com/urfave/cli"
 
-	messages "github.com/skycoin/hardware-wallet-protob/go"
-
 	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
 )
 
@@ -27,7 +25,7 @@ func removePinCode() gcli.Command {
 		},
 		OnUsageError: onCommandUsageError(name),
 		Action: func(c *gcli.Context) {
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
@@ -41,7 +39,6 @@ func removePinCode() gcli.Command {
 				}
 			}
 
-			var pinEnc string
 			removePin := new(bool)
 			*removePin = true
 			msg, err := device.ChangePin(removePin)
@@ -50,22 +47,10 @@ func removePinCode() gcli.Command {
 				return
 			}
 
-			if msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-				msg, err = device.ButtonAck()
-				if err != nil {
-					log.Error(err)
-					return
-				}
-			}
-
-			for msg.Kind == uint16(messages.MessageType_MessageType_PinMatrixRequest) {
-				fmt.Printf("PinMatrixRequest response: ")
-				fmt.Scanln(&pinEnc)
-				msg, err = device.PinMatrixAck(pinEnc)
-				if err != nil {
-					log.Error(err)
-					return
-				}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
 			}
 
 			// handle success or failure msg
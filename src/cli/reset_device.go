@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	gcli "github.com/urfave/cli"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+)
+
+func resetDeviceCmd() gcli.Command {
+	name := "resetDevice"
+	return gcli.Command{
+		Name:        name,
+		Usage:       "Ask the device to generate a new seed using its entropy mixed with host entropy.",
+		Description: "",
+		Flags: []gcli.Flag{
+			gcli.IntFlag{
+				Name:  "wordCount",
+				Usage: "Use a specific (12 | 24) number of words for the Mnemonic",
+				Value: 12,
+			},
+			gcli.BoolFlag{
+				Name:  "usePassphrase",
+				Usage: "Configure a passphrase",
+			},
+			gcli.BoolFlag{
+				Name:  "usePin",
+				Usage: "Configure a PIN code",
+			},
+			gcli.BoolFlag{
+				Name:  "displayRandom",
+				Usage: "Show the device internal entropy on screen before using it",
+			},
+			gcli.BoolFlag{
+				Name:  "skipBackup",
+				Usage: "Do not ask the user to backup the seed, the backup can be done later",
+			},
+			gcli.StringFlag{
+				Name:  "label",
+				Usage: "Configure a device label",
+			},
+			gcli.StringFlag{
+				Name:   "deviceType",
+				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
+				EnvVar: "DEVICE_TYPE",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			wordCount := uint32(c.Uint64("wordCount"))
+
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			if device == nil {
+				return
+			}
+			defer device.Close()
+
+			if os.Getenv("AUTO_PRESS_BUTTONS") == "1" && device.Driver.DeviceType() == skyWallet.DeviceTypeEmulator && runtime.GOOS == "linux" {
+				err := device.SetAutoPressButton(true, skyWallet.ButtonRight)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			msg, err := device.ResetDevice(wordCount, c.Bool("usePassphrase"), c.Bool("usePin"), c.Bool("displayRandom"), c.Bool("skipBackup"), c.String("label"))
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			responseMsg, err := skyWallet.DecodeSuccessOrFailMsg(msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			fmt.Println(responseMsg)
+		},
+	}
+}
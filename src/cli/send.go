@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	gcli "github.com/urfave/cli"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/builder"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/node"
+)
+
+func sendCmd() gcli.Command {
+	name := "send"
+	return gcli.Command{
+		Name:  name,
+		Usage: "Send coins from the device addresses through a Skycoin node.",
+		Description: `The unspent outputs of the first addressN addresses of the device are looked up on the node, the
+        transaction is built as buildTransaction does, previewed, signed by the device and injected into the node.`,
+		Flags: append([]gcli.Flag{
+			gcli.StringFlag{
+				Name:   "node",
+				Value:  node.DefaultAddr,
+				Usage:  "Address of the REST API of the Skycoin node",
+				EnvVar: "SKYCOIN_NODE",
+			},
+			gcli.IntFlag{
+				Name:  "addressN",
+				Value: 10,
+				Usage: "Number of addresses of the device whose outputs can be spent",
+			},
+			gcli.IntFlag{
+				Name:  "startIndex",
+				Value: 0,
+				Usage: "Index of the first address of the device. Assume 0 if not set.",
+			},
+			gcli.StringSliceFlag{
+				Name:  "outputAddress",
+				Usage: "Addresses of the output for the transaction",
+			},
+			gcli.Int64SliceFlag{
+				Name:  "coin",
+				Usage: "Amount of coins",
+			},
+			gcli.Int64SliceFlag{
+				Name:  "hour",
+				Usage: "Number of hours, for every output or for none to share the hours left after the fee",
+			},
+			gcli.StringFlag{
+				Name:  "strategy",
+				Value: string(builder.LargestFirst),
+				Usage: fmt.Sprintf("Unspent outputs spent first, %s, %s or %s", builder.LargestFirst, builder.SmallestFirst, builder.MostHoursFirst),
+			},
+			gcli.StringFlag{
+				Name:  "changeAddress",
+				Usage: "Address of the device receiving the coins not sent, the address of the first input if not set",
+			},
+			gcli.Float64Flag{
+				Name:  "shareFactor",
+				Value: builder.DefaultShareFactor,
+				Usage: "Share of the hours left after the fee sent to the outputs, the rest goes to the change",
+			},
+			gcli.BoolTFlag{
+				Name:  "verifyChange",
+				Usage: "Check that the change goes to the device address at its addressIndex before signing",
+			},
+			gcli.BoolFlag{
+				Name:  "yes",
+				Usage: "Send the transaction without asking for confirmation",
+			},
+			gcli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the transaction summary as JSON",
+			},
+			gcli.StringFlag{
+				Name:   "deviceType",
+				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
+				EnvVar: "DEVICE_TYPE",
+			},
+		}, verifyParamsFlags()...),
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			strategy, err := builder.ParseStrategy(c.String("strategy"))
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			destinations, ok := destinationFlags(c)
+			if !ok {
+				return
+			}
+
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			if device == nil {
+				return
+			}
+			defer device.Close()
+
+			if os.Getenv("AUTO_PRESS_BUTTONS") == "1" && device.Driver.DeviceType() == skyWallet.DeviceTypeEmulator && runtime.GOOS == "linux" {
+				err = device.SetAutoPressButton(true, skyWallet.ButtonRight)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			startIndex := uint32(c.Int("startIndex"))
+			addresses, err := skyWallet.RequestAddresses(device, uint32(c.Int("addressN")), startIndex, interact)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			client := node.NewHTTPClient(c.String("node"))
+			utxos, err := client.UnspentOutputs(addresses)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			config := builder.NewConfig(walletAddresses(addresses, startIndex))
+			config.Strategy = strategy
+			config.ChangeAddress = c.String("changeAddress")
+			config.ShareFactor = c.Float64("shareFactor")
+			config.Params = verifyParams(c)
+			config.Limit = device.TransactionSignLimit()
+			tx, err := builder.Build(utxos, destinations, config)
+			if err != nil {
+				reportBuildError(err)
+				return
+			}
+
+			summary, err := skyWallet.NewTransactionSummary(tx.Inputs, tx.Outputs, tx.Values())
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if err = writeSummary(summary, c.Bool("json")); err != nil {
+				log.Error(err)
+				return
+			}
+			if !c.Bool("yes") {
+				var confirmed bool
+				confirmed, err = confirmTransaction()
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				if !confirmed {
+					fmt.Println("Transaction not sent")
+					return
+				}
+			}
+
+			if c.BoolT("verifyChange") {
+				device.SetChangeVerification(interact)
+			}
+			txid, err := sendTransaction(device, client, tx)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			fmt.Printf("Transaction ID: %s\n", txid)
+		},
+	}
+}
+
+// sendTransaction signs tx with the device and injects it into the node, it
+// returns the id of the transaction
+func sendTransaction(device skyWallet.Devicer, client node.Client, tx *builder.Transaction) (string, error) {
+	partial := tx.Partial()
+	if _, err := skyWallet.SignPartialTransaction(device, partial, interact); err != nil {
+		return "", err
+	}
+	raw, err := partial.Encode()
+	if err != nil {
+		return "", err
+	}
+	return client.InjectTransaction(raw)
+}
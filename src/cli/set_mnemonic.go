@@ -5,11 +5,10 @@ import (
 	"os"
 	"runtime"
 
-	messages "github.com/skycoin/hardware-wallet-protob/go"
-
 	gcli "github.com/urfave/cli"
 
 	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/bip39"
 )
 
 func setMnemonicCmd() gcli.Command {
@@ -17,12 +16,9 @@ func setMnemonicCmd() gcli.Command {
 	return gcli.Command{
 		Name:        name,
 		Usage:       "Configure the device with a mnemonic.",
-		Description: "",
+		Description: "The mnemonic is read from stdin, it is prompted for without echo if stdin is a terminal. It is checked against the BIP39 wordlist and checksum before it is sent to the device.",
 		Flags: []gcli.Flag{
-			gcli.StringFlag{
-				Name:  "mnemonic",
-				Usage: "Mnemonic that will be stored in the device to generate addresses.",
-			},
+			secretsFdFlag(),
 			gcli.StringFlag{
 				Name:   "deviceType",
 				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
@@ -31,7 +27,7 @@ func setMnemonicCmd() gcli.Command {
 		},
 		OnUsageError: onCommandUsageError(name),
 		Action: func(c *gcli.Context) {
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
@@ -45,19 +41,26 @@ func setMnemonicCmd() gcli.Command {
 				}
 			}
 
-			mnemonic := c.String("mnemonic")
+			mnemonic, err := secretsInput(c).readMnemonic()
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if err := bip39.ValidateMnemonic(mnemonic); err != nil {
+				reportInvalidMnemonic(err)
+				return
+			}
+
 			msg, err := device.SetMnemonic(mnemonic)
 			if err != nil {
 				log.Error(err)
 				return
 			}
 
-			if msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-				msg, err = device.ButtonAck()
-				if err != nil {
-					log.Error(err)
-					return
-				}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
 			}
 
 			responseMsg, err := skyWallet.DecodeSuccessOrFailMsg(msg)
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	gcli "github.com/urfave/cli"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/bip39"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/shamir"
+)
+
+func splitMnemonicCmd() gcli.Command {
+	name := "splitMnemonic"
+	return gcli.Command{
+		Name:  name,
+		Usage: "Split a mnemonic into shares, a quorum of them is needed to recombine it. No device is needed.",
+		Description: `The mnemonic is read from stdin, it is prompted for without echo if stdin is a terminal. Each share is a list
+        of BIP39 words with a checksum. The first address of the wallet is printed, record it with each share so
+        combineShares can check the recombined mnemonic.`,
+		Flags: []gcli.Flag{
+			gcli.IntFlag{
+				Name:  "threshold",
+				Usage: "Number of shares needed to recombine the mnemonic",
+				Value: 2,
+			},
+			gcli.IntFlag{
+				Name:  "shares",
+				Usage: fmt.Sprintf("Number of shares, up to %d", shamir.MaxShares),
+				Value: 3,
+			},
+			secretsFdFlag(),
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			mnemonic, err := secretsInput(c).readMnemonic()
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if err = bip39.ValidateMnemonic(mnemonic); err != nil {
+				reportInvalidMnemonic(err)
+				return
+			}
+
+			shares, err := shamir.SplitMnemonic(mnemonic, c.Int("threshold"), c.Int("shares"), rand.Reader)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			addresses, err := skyWallet.MnemonicAddresses(mnemonic, 1, 0)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			fmt.Printf("Split %04x, any %d of the %d shares recombine the mnemonic\n", shares[0].ID, shares[0].Threshold, len(shares))
+			fmt.Printf("First address, record it with each share: %s\n", addresses[0])
+			for i, share := range shares {
+				fmt.Printf("Share %d/%d: %s\n", i+1, len(shares), share)
+			}
+		},
+	}
+}
@@ -0,0 +1,453 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"unicode"
+
+	gcli "github.com/urfave/cli"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/bip39"
+)
+
+var (
+	errInterrupted        = errors.New("Input interrupted")
+	errInvalidPinMatrix   = errors.New("Invalid PIN matrix positions, use digits 1 to 9")
+	errPassphraseMismatch = errors.New("Passphrases do not match")
+)
+
+const (
+	maxPinLen           = 9
+	maxWordLen          = 8
+	maxPassphraseTrials = 3
+	maxListedCandidates = 24
+)
+
+// pinMatrixLegend describes the positions expected by the PIN matrix,
+// see skyWallet.Device.ChangePin
+const pinMatrixLegend = `Enter the positions of the PIN digits shown on the device screen,
+using the numeric keypad or the matching digits:
+    7 8 9
+    4 5 6
+    1 2 3
+`
+
+// stdin is shared by all the prompts so buffered input is not lost between them
+var stdin = bufio.NewReader(os.Stdin)
+
+// terminalInput reads PINs, passphrases and recovery words from the user.
+// When stdin is a terminal the input is not echoed, the numeric keypad is
+// accepted for the PIN matrix and recovery words are autocompleted, otherwise
+// each answer is read from a line of stdin.
+type terminalInput struct {
+	in  *bufio.Reader
+	out io.Writer
+	fd  int
+}
+
+// newTerminalInput creates a terminalInput writing its prompts to out
+func newTerminalInput(out io.Writer) *terminalInput {
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		fd = -1
+	}
+	return &terminalInput{
+		in:  stdin,
+		out: out,
+		fd:  fd,
+	}
+}
+
+func (t *terminalInput) isTerminal() bool {
+	return t.fd >= 0
+}
+
+// secretsFdFlag is the flag of the commands reading secrets, they are
+// never given on the command line where they would show up in ps and in
+// the shell history
+func secretsFdFlag() gcli.Flag {
+	return gcli.IntFlag{
+		Name:  "secretsFd",
+		Usage: "Read the secrets from this file descriptor, one per line, instead of stdin or a prompt.",
+	}
+}
+
+// secretsInput returns the input a command reads its secrets from, the file
+// descriptor set with --secretsFd if any, otherwise stdin
+func secretsInput(c *gcli.Context) *terminalInput {
+	if !c.IsSet("secretsFd") {
+		return newTerminalInput(os.Stderr)
+	}
+	return &terminalInput{
+		in:  bufio.NewReader(os.NewFile(uintptr(c.Int("secretsFd")), "secretsFd")),
+		out: ioutil.Discard,
+		fd:  -1,
+	}
+}
+
+// readMnemonic reads a mnemonic without echoing it, see bip39.NormalizeMnemonic
+func (t *terminalInput) readMnemonic() (string, error) {
+	mnemonic, err := t.readSecret("Mnemonic: ")
+	if err != nil {
+		return "", err
+	}
+	return bip39.NormalizeMnemonic(mnemonic), nil
+}
+
+// readPinMatrix reads the positions of the PIN digits on the device matrix
+func (t *terminalInput) readPinMatrix() (string, error) {
+	fmt.Fprint(t.out, pinMatrixLegend)
+	const prompt = "PinMatrixRequest response: "
+	if !t.isTerminal() {
+		fmt.Fprint(t.out, prompt)
+		pin, err := t.readLine()
+		if err != nil {
+			return "", err
+		}
+		pin = strings.TrimSpace(pin)
+		if !isPinMatrix(pin) {
+			return "", errInvalidPinMatrix
+		}
+		return pin, nil
+	}
+
+	return t.editRaw(pinMatrixEditor(prompt))
+}
+
+// readPassphrase reads a passphrase twice and checks both entries match
+func (t *terminalInput) readPassphrase() (string, error) {
+	for i := 0; i < maxPassphraseTrials; i++ {
+		passphrase, err := t.readSecret("Input passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		confirmation, err := t.readSecret("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if passphrase == confirmation {
+			return passphrase, nil
+		}
+		fmt.Fprintln(t.out, "Passphrases do not match, try again")
+	}
+	return "", errPassphraseMismatch
+}
+
+// readWord reads a recovery word, autocompleting it from the BIP39 wordlist.
+// Tab completes the word or lists the candidates and enter accepts any
+// unambiguous prefix.
+func (t *terminalInput) readWord() (string, error) {
+	const prompt = "Word: "
+	if !t.isTerminal() {
+		fmt.Fprint(t.out, prompt)
+		word, err := t.readLine()
+		return strings.TrimSpace(word), err
+	}
+
+	return t.editRaw(wordEditor(prompt))
+}
+
+// readSecret reads a line without echoing it
+func (t *terminalInput) readSecret(prompt string) (string, error) {
+	if !t.isTerminal() {
+		fmt.Fprint(t.out, prompt)
+		return t.readLine()
+	}
+
+	return t.editRaw(secretEditor(prompt))
+}
+
+// readLine reads a line of stdin without its line ending
+func (t *terminalInput) readLine() (string, error) {
+	line, err := t.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// editRaw runs the line editor with the terminal in raw mode
+func (t *terminalInput) editRaw(e lineEditor) (string, error) {
+	state, err := terminal.MakeRaw(t.fd)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := terminal.Restore(t.fd, state); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	return e.run(t.in, t.out)
+}
+
+// lineEditor reads keys until a line is accepted, redrawing the prompt after each key
+type lineEditor struct {
+	prompt string
+	// keypad maps the numeric keypad keys to digits when num lock is off
+	keypad bool
+	// maxLen limits the number of runes, zero means no limit
+	maxLen int
+	// hide clears the line once it is accepted
+	hide   bool
+	accept func(r rune) bool
+	// display renders the line after the prompt
+	display func(line []rune) string
+	// complete is called on tab with the line, it returns the completed line
+	// and the candidates to list
+	complete func(line []rune) ([]rune, []string)
+	// enter is called on enter with the line, it returns the result and
+	// whether the line is accepted
+	enter func(line []rune) (string, bool)
+}
+
+// pinMatrixEditor reads PIN matrix positions, echoing a star for each of them
+func pinMatrixEditor(prompt string) lineEditor {
+	return lineEditor{
+		prompt: prompt,
+		keypad: true,
+		maxLen: maxPinLen,
+		accept: func(r rune) bool {
+			return r >= '1' && r <= '9'
+		},
+		display: func(line []rune) string {
+			return strings.Repeat("*", len(line))
+		},
+		enter: func(line []rune) (string, bool) {
+			return string(line), len(line) > 0
+		},
+	}
+}
+
+// wordEditor reads a word of the BIP39 wordlist and clears it once accepted
+func wordEditor(prompt string) lineEditor {
+	return lineEditor{
+		prompt: prompt,
+		maxLen: maxWordLen,
+		hide:   true,
+		accept: func(r rune) bool {
+			return r >= 'a' && r <= 'z'
+		},
+		display: func(line []rune) string {
+			return string(line)
+		},
+		complete: func(line []rune) ([]rune, []string) {
+			candidates := bip39.Complete(string(line))
+			if len(candidates) == 0 {
+				return line, nil
+			}
+			return []rune(commonPrefix(candidates)), candidates
+		},
+		enter: func(line []rune) (string, bool) {
+			return bip39.Expand(string(line))
+		},
+	}
+}
+
+// secretEditor reads a line without echoing anything
+func secretEditor(prompt string) lineEditor {
+	return lineEditor{
+		prompt: prompt,
+		accept: unicode.IsPrint,
+		display: func(line []rune) string {
+			return ""
+		},
+		enter: func(line []rune) (string, bool) {
+			return string(line), true
+		},
+	}
+}
+
+func (e lineEditor) run(in *bufio.Reader, out io.Writer) (string, error) {
+	var line []rune
+	redraw := func() {
+		fmt.Fprintf(out, "\r\x1b[K%s%s", e.prompt, e.display(line))
+	}
+
+	redraw()
+	for {
+		key, err := readKey(in, e.keypad)
+		if err != nil {
+			return "", err
+		}
+
+		switch key {
+		case keyInterrupt:
+			fmt.Fprint(out, "\r\n")
+			return "", errInterrupted
+		case keyEOF:
+			fmt.Fprint(out, "\r\n")
+			return "", io.ErrUnexpectedEOF
+		case keyBackspace:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		case keyTab:
+			if e.complete == nil {
+				break
+			}
+			var candidates []string
+			line, candidates = e.complete(line)
+			switch {
+			case len(candidates) > maxListedCandidates:
+				fmt.Fprintf(out, "\r\n(%d candidates)\r\n", len(candidates))
+			case len(candidates) > 1:
+				fmt.Fprintf(out, "\r\n%s\r\n", strings.Join(candidates, " "))
+			}
+		case keyEnter:
+			if result, ok := e.enter(line); ok {
+				if e.hide {
+					fmt.Fprintf(out, "\r\x1b[K%s", e.prompt)
+				}
+				fmt.Fprint(out, "\r\n")
+				return result, nil
+			}
+			fmt.Fprint(out, "\a")
+		default:
+			if key >= 0 && e.accept(key) && (e.maxLen == 0 || len(line) < e.maxLen) {
+				line = append(line, key)
+			}
+		}
+		redraw()
+	}
+}
+
+// Keys returned by readKey besides the printable runes
+const (
+	keyEnter rune = -(iota + 1)
+	keyBackspace
+	keyTab
+	keyInterrupt
+	keyEOF
+	keyUnknown
+)
+
+// keypadKeys maps the escape sequences sent by the numeric keypad keys
+// when num lock is off to the digits printed on them
+var keypadKeys = map[string]rune{
+	"[H": '7', "[1~": '7', "[7~": '7', "OH": '7',
+	"[A": '8', "OA": '8',
+	"[5~": '9',
+	"[D":  '4', "OD": '4',
+	"[E": '5', "[G": '5', "OE": '5',
+	"[C": '6', "OC": '6',
+	"[F": '1', "[4~": '1', "[8~": '1', "OF": '1',
+	"[B": '2', "OB": '2',
+	"[6~": '3',
+}
+
+// readKey reads a key typed in a terminal in raw mode. If keypad is set the
+// numeric keypad keys are mapped to digits whatever the num lock state.
+func readKey(in *bufio.Reader, keypad bool) (rune, error) {
+	r, _, err := in.ReadRune()
+	if err == io.EOF {
+		return keyEOF, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	switch r {
+	case '\r', '\n':
+		return keyEnter, nil
+	case 0x7f, '\b':
+		return keyBackspace, nil
+	case '\t':
+		return keyTab, nil
+	case 0x03:
+		return keyInterrupt, nil
+	case 0x04:
+		return keyEOF, nil
+	case 0x1b:
+		seq, err := readEscapeSequence(in)
+		if err != nil {
+			return 0, err
+		}
+		if seq == "OM" {
+			// keypad enter in application mode
+			return keyEnter, nil
+		}
+		if !keypad {
+			return keyUnknown, nil
+		}
+		if len(seq) == 2 && seq[0] == 'O' && seq[1] >= 'p' && seq[1] <= 'y' {
+			// keypad digits in application mode
+			return rune('0' + seq[1] - 'p'), nil
+		}
+		if d, ok := keypadKeys[seq]; ok {
+			return d, nil
+		}
+		return keyUnknown, nil
+	}
+
+	if !unicode.IsPrint(r) {
+		return keyUnknown, nil
+	}
+	return r, nil
+}
+
+// readEscapeSequence reads the rest of an escape sequence after the escape character
+func readEscapeSequence(in *bufio.Reader) (string, error) {
+	b, err := in.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	seq := []byte{b}
+	switch b {
+	case 'O':
+		b, err := in.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		return string(append(seq, b)), nil
+	case '[':
+		// parameters until the final byte
+		for {
+			b, err := in.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			seq = append(seq, b)
+			if b >= 0x40 && b <= 0x7e {
+				return string(seq), nil
+			}
+		}
+	default:
+		return string(seq), nil
+	}
+}
+
+// isPinMatrix tells if pin is a valid sequence of PIN matrix positions
+func isPinMatrix(pin string) bool {
+	if len(pin) == 0 || len(pin) > maxPinLen {
+		return false
+	}
+	for _, c := range pin {
+		if c < '1' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// commonPrefix returns the longest prefix shared by words
+func commonPrefix(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	prefix := words[0]
+	for _, w := range words[1:] {
+		i := 0
+		for i < len(prefix) && i < len(w) && prefix[i] == w[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+	return prefix
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTerminalInput(input string) (*terminalInput, *bytes.Buffer) {
+	var out bytes.Buffer
+	return &terminalInput{
+		in:  bufio.NewReader(strings.NewReader(input)),
+		out: &out,
+		fd:  -1,
+	}, &out
+}
+
+func TestReadKey(t *testing.T) {
+	tt := []struct {
+		name   string
+		input  string
+		keypad bool
+		key    rune
+	}{
+		{"letter", "a", false, 'a'},
+		{"utf8", "ñ", false, 'ñ'},
+		{"enter", "\r", false, keyEnter},
+		{"backspace", "\x7f", false, keyBackspace},
+		{"tab", "\t", false, keyTab},
+		{"ctrl-c", "\x03", false, keyInterrupt},
+		{"ctrl-d", "\x04", false, keyEOF},
+		{"eof", "", false, keyEOF},
+		{"control", "\x01", false, keyUnknown},
+		{"keypad enter", "\x1bOM", false, keyEnter},
+		{"arrow", "\x1b[A", false, keyUnknown},
+		{"keypad 8", "\x1b[A", true, '8'},
+		{"keypad 7", "\x1b[H", true, '7'},
+		{"keypad 9", "\x1b[5~", true, '9'},
+		{"keypad 5", "\x1b[E", true, '5'},
+		{"keypad 1", "\x1b[4~", true, '1'},
+		{"keypad 3", "\x1b[6~", true, '3'},
+		{"application keypad 4", "\x1bOt", true, '4'},
+		{"unknown sequence", "\x1b[15~", true, keyUnknown},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := readKey(bufio.NewReader(strings.NewReader(tc.input)), tc.keypad)
+			require.NoError(t, err)
+			require.Equal(t, tc.key, key)
+		})
+	}
+}
+
+func TestLineEditorWord(t *testing.T) {
+	tt := []struct {
+		name  string
+		input string
+		word  string
+		err   error
+	}{
+		{"full word", "abandon\r", "abandon", nil},
+		{"unique prefix", "aban\r", "abandon", nil},
+		{"tab completion", "zon\t\r", "zone", nil},
+		{"tab common prefix", "ab\tandon\r", "abandon", nil},
+		{"ambiguous prefix rejected", "ab\randon\r", "abandon", nil},
+		{"backspace", "zoe\x7f\x7fero\r", "zero", nil},
+		{"digits ignored", "z1o2o\r", "zoo", nil},
+		{"interrupt", "zo\x03", "", errInterrupted},
+		{"eof", "zo", "", io.ErrUnexpectedEOF},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ti, out := newTestTerminalInput(tc.input)
+			word, err := wordEditor("Word: ").run(ti.in, out)
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.word, word)
+		})
+	}
+}
+
+func TestLineEditorHidesSecrets(t *testing.T) {
+	ti, out := newTestTerminalInput("s3cr3t\r")
+	secret, err := secretEditor("Input passphrase: ").run(ti.in, out)
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", secret)
+	require.NotContains(t, out.String(), "s3cr3t")
+}
+
+func TestLineEditorPinMatrix(t *testing.T) {
+	// keypad 7, 8 and 9 with num lock off then 2 and a letter that is ignored
+	ti, out := newTestTerminalInput("\x1b[H\x1b[A\x1b[5~2x\r")
+	pin, err := pinMatrixEditor("PinMatrixRequest response: ").run(ti.in, out)
+	require.NoError(t, err)
+	require.Equal(t, "7892", pin)
+	require.Contains(t, out.String(), "****")
+	require.NotContains(t, out.String(), "7892")
+
+	ti, out = newTestTerminalInput("1234567891\r")
+	pin, err = pinMatrixEditor("PinMatrixRequest response: ").run(ti.in, out)
+	require.NoError(t, err)
+	require.Equal(t, "123456789", pin)
+
+	// an empty PIN is not accepted
+	ti, out = newTestTerminalInput("\r5\r")
+	pin, err = pinMatrixEditor("PinMatrixRequest response: ").run(ti.in, out)
+	require.NoError(t, err)
+	require.Equal(t, "5", pin)
+}
+
+func TestReadPinMatrix(t *testing.T) {
+	ti, out := newTestTerminalInput("8376\n")
+	pin, err := ti.readPinMatrix()
+	require.NoError(t, err)
+	require.Equal(t, "8376", pin)
+	require.Contains(t, out.String(), "7 8 9")
+
+	ti, _ = newTestTerminalInput("1230\n")
+	_, err = ti.readPinMatrix()
+	require.Equal(t, errInvalidPinMatrix, err)
+}
+
+func TestReadPassphrase(t *testing.T) {
+	ti, _ := newTestTerminalInput("my secret\nmy secret\n")
+	passphrase, err := ti.readPassphrase()
+	require.NoError(t, err)
+	require.Equal(t, "my secret", passphrase)
+
+	ti, out := newTestTerminalInput("typo\nsecret\nsecret\nsecret\n")
+	passphrase, err = ti.readPassphrase()
+	require.NoError(t, err)
+	require.Equal(t, "secret", passphrase)
+	require.Contains(t, out.String(), "do not match")
+
+	ti, _ = newTestTerminalInput("a\nb\nc\nd\ne\nf\n")
+	_, err = ti.readPassphrase()
+	require.Equal(t, errPassphraseMismatch, err)
+
+	ti, _ = newTestTerminalInput("\n\n")
+	passphrase, err = ti.readPassphrase()
+	require.NoError(t, err)
+	require.Empty(t, passphrase)
+}
+
+func TestReadWord(t *testing.T) {
+	ti, _ := newTestTerminalInput("foobar\r\n")
+	word, err := ti.readWord()
+	require.NoError(t, err)
+	require.Equal(t, "foobar", word)
+}
+
+func TestCommonPrefix(t *testing.T) {
+	require.Equal(t, "z", commonPrefix([]string{"zebra", "zero", "zone", "zoo"}))
+	require.Equal(t, "zo", commonPrefix([]string{"zone", "zoo"}))
+	require.Equal(t, "zoo", commonPrefix([]string{"zoo"}))
+	require.Equal(t, "", commonPrefix(nil))
+}
+
+func TestReadMnemonic(t *testing.T) {
+	ti, out := newTestTerminalInput("  cloud  flower\tupset \n")
+	mnemonic, err := ti.readMnemonic()
+	require.NoError(t, err)
+	require.Equal(t, "cloud flower upset", mnemonic)
+	require.NotContains(t, out.String(), "cloud")
+}
@@ -1,9 +1,12 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
 	"github.com/gogo/protobuf/proto"
 
@@ -12,6 +15,8 @@ import (
 	messages "github.com/skycoin/hardware-wallet-protob/go"
 
 	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/psbt"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
 )
 
 func transactionSignCmd() gcli.Command {
@@ -20,47 +25,50 @@ func transactionSignCmd() gcli.Command {
 		Name:        name,
 		Usage:       "Ask the device to sign a transaction using the provided information.",
 		Description: "",
-		Flags: []gcli.Flag{
-			gcli.StringSliceFlag{
-				Name:  "inputHash",
-				Usage: "Hash of the Input of the transaction we expect the device to sign",
+		Flags: append(append(transactionFlags(), verifyParamsFlags()...),
+			gcli.Int64SliceFlag{
+				Name:  "inputCoin",
+				Usage: "Amount of coins of the input, in droplets, to show the hours burned",
 			},
-			gcli.IntSliceFlag{
-				Name:  "inputIndex",
-				Usage: "Index of the input in the wallet",
+			gcli.Int64SliceFlag{
+				Name:  "inputHour",
+				Usage: "Number of hours of the input, to show the hours burned",
 			},
 			gcli.StringSliceFlag{
-				Name:  "outputAddress",
-				Usage: "Addresses of the output for the transaction",
+				Name:  "inputAddress",
+				Usage: "Address owning the input, needed to create a partially signed transaction",
 			},
-			gcli.Int64SliceFlag{
-				Name:  "coin",
-				Usage: "Amount of coins",
+			gcli.StringFlag{
+				Name:  "psbt",
+				Usage: "Partially signed transaction file to sign the inputs of this device in, it is created from the other flags if they are given",
 			},
-			gcli.Int64SliceFlag{
-				Name:  "hour",
-				Usage: "Number of hours",
+			gcli.BoolTFlag{
+				Name:  "verifyChange",
+				Usage: "Check that the outputs with an addressIndex go to the device address at that index before signing",
+			},
+			gcli.BoolFlag{
+				Name:  "yes",
+				Usage: "Send the transaction to the device without asking for confirmation",
 			},
-			gcli.IntSliceFlag{
-				Name:  "addressIndex",
-				Usage: "If the address is a return address tell its index in the wallet",
+			gcli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the transaction summary as JSON",
 			},
 			gcli.StringFlag{
 				Name:   "deviceType",
 				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
 				EnvVar: "DEVICE_TYPE",
 			},
-		},
+		),
 		OnUsageError: onCommandUsageError(name),
 		Action: func(c *gcli.Context) {
 			inputs := c.StringSlice("inputHash")
-			inputIndex := c.IntSlice("inputIndex")
-			outputs := c.StringSlice("outputAddress")
 			coins := c.Int64Slice("coin")
 			hours := c.Int64Slice("hour")
-			addressIndex := c.IntSlice("addressIndex")
+			inputCoins := c.Int64Slice("inputCoin")
+			inputHours := c.Int64Slice("inputHour")
 
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
@@ -74,91 +82,327 @@ func transactionSignCmd() gcli.Command {
 				}
 			}
 
-			if len(inputs) != len(inputIndex) {
-				fmt.Println("Every given input hash should have the an inputIndex")
+			transactionInputs, transactionOutputs, ok := transactionMessages(c)
+			if !ok {
 				return
 			}
-			if len(outputs) != len(coins) || len(outputs) != len(hours) {
-				fmt.Println("Every given output should have a coin and hour value")
+
+			var values []skyWallet.InputValue
+			if len(inputCoins) != 0 || len(inputHours) != 0 {
+				if len(inputCoins) != len(inputs) || len(inputHours) != len(inputs) {
+					fmt.Println("Every given input should have an inputCoin and inputHour value")
+					return
+				}
+				for i := range inputs {
+					values = append(values, skyWallet.InputValue{
+						Coins: uint64(inputCoins[i]),
+						Hours: uint64(inputHours[i]),
+					})
+				}
+			}
+
+			psbtPath := c.String("psbt")
+			var partial *psbt.Transaction
+			if psbtPath != "" {
+				var err error
+				partial, err = partialTransaction(psbtPath, transactionInputs, c.StringSlice("inputAddress"), transactionOutputs)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				if partial.Complete() {
+					fmt.Println("All the inputs are already signed, use combine to get the transaction")
+					return
+				}
+				transactionInputs, transactionOutputs = skyWallet.PartialTransactionMessages(partial)
+			}
+
+			invalid := negativeAmounts("coin", coins)
+			invalid = append(invalid, negativeAmounts("hour", hours)...)
+			invalid = append(invalid, negativeAmounts("inputCoin", inputCoins)...)
+			invalid = append(invalid, negativeAmounts("inputHour", inputHours)...)
+			if len(invalid) != 0 {
+				reportInvalidFields(invalid)
+				return
+			}
+			err := verifyParams(c).ValidateTransaction(transactionInputs, transactionOutputs, values)
+			if err != nil {
+				reportInvalidFields(err)
 				return
 			}
 
-			var transactionInputs []*messages.SkycoinTransactionInput
-			var transactionOutputs []*messages.SkycoinTransactionOutput
-			for i, input := range inputs {
-				var transactionInput messages.SkycoinTransactionInput
-				transactionInput.HashIn = proto.String(input)
-				transactionInput.Index = proto.Uint32(uint32(inputIndex[i]))
-				transactionInputs = append(transactionInputs, &transactionInput)
+			summary, err := skyWallet.NewTransactionSummary(transactionInputs, transactionOutputs, values)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if err = writeSummary(summary, c.Bool("json")); err != nil {
+				log.Error(err)
+				return
 			}
-			for i, output := range outputs {
-				var transactionOutput messages.SkycoinTransactionOutput
-				transactionOutput.Address = proto.String(output)
-				transactionOutput.Coin = proto.Uint64(uint64(coins[i]))
-				transactionOutput.Hour = proto.Uint64(uint64(hours[i]))
-				if i < len(addressIndex) {
-					transactionOutput.AddressIndex = proto.Uint32(uint32(addressIndex[i]))
+			if !c.Bool("yes") {
+				var confirmed bool
+				confirmed, err = confirmTransaction()
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				if !confirmed {
+					fmt.Println("Transaction not signed")
+					return
 				}
-				transactionOutputs = append(transactionOutputs, &transactionOutput)
 			}
 
-			msg, err := device.TransactionSign(transactionInputs, transactionOutputs)
+			if c.BoolT("verifyChange") {
+				device.SetChangeVerification(interact)
+			}
+			if partial != nil {
+				signPartialTransaction(device, partial, psbtPath)
+				return
+			}
+			var msg wire.Message
+			msg, err = device.TransactionSign(transactionInputs, transactionOutputs)
 			if err != nil {
 				log.Error(err)
 				return
 			}
 
-			for {
-				switch msg.Kind {
-				case uint16(messages.MessageType_MessageType_ResponseTransactionSign):
-					signatures, err := skyWallet.DecodeResponseTransactionSign(msg)
-					if err != nil {
-						log.Error(err)
-						return
-					}
-					fmt.Println(signatures)
-					return
-				case uint16(messages.MessageType_MessageType_Success):
-					fmt.Println("Should end with ResponseTransactionSign request")
-					return
-				case uint16(messages.MessageType_MessageType_ButtonRequest):
-					msg, err = device.ButtonAck()
-					if err != nil {
-						log.Error(err)
-						return
-					}
-				case uint16(messages.MessageType_MessageType_PassphraseRequest):
-					var passphrase string
-					fmt.Printf("Input passphrase: ")
-					fmt.Scanln(&passphrase)
-					msg, err = device.PassphraseAck(passphrase)
-					if err != nil {
-						log.Error(err)
-						return
-					}
-				case uint16(messages.MessageType_MessageType_PinMatrixRequest):
-					var pinEnc string
-					fmt.Printf("PinMatrixRequest response: ")
-					fmt.Scanln(&pinEnc)
-					msg, err = device.PinMatrixAck(pinEnc)
-					if err != nil {
-						log.Error(err)
-						return
-					}
-				case uint16(messages.MessageType_MessageType_Failure):
-					failMsg, err := skyWallet.DecodeFailMsg(msg)
-					if err != nil {
-						log.Error(err)
-						return
-					}
-
-					fmt.Printf("Failed with message: %s\n", failMsg)
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			switch msg.Kind {
+			case uint16(messages.MessageType_MessageType_ResponseTransactionSign):
+				signatures, err := skyWallet.DecodeResponseTransactionSign(msg)
+				if err != nil {
+					log.Error(err)
 					return
-				default:
-					log.Errorf("received unexpected message type: %s", messages.MessageType(msg.Kind))
+				}
+				fmt.Println(signatures)
+			case uint16(messages.MessageType_MessageType_Success):
+				fmt.Println("Should end with ResponseTransactionSign request")
+			case uint16(messages.MessageType_MessageType_Failure):
+				failMsg, err := skyWallet.DecodeFailMsg(msg)
+				if err != nil {
+					log.Error(err)
 					return
 				}
+
+				fmt.Printf("Failed with message: %s\n", failMsg)
+			default:
+				log.Errorf("received unexpected message type: %s", messages.MessageType(msg.Kind))
 			}
 		},
 	}
 }
+
+// transactionFlags are the flags describing the inputs and outputs of a transaction
+func transactionFlags() []gcli.Flag {
+	return []gcli.Flag{
+		gcli.StringSliceFlag{
+			Name:  "inputHash",
+			Usage: "Hash of the Input of the transaction we expect the device to sign",
+		},
+		gcli.IntSliceFlag{
+			Name:  "inputIndex",
+			Usage: "Index of the input in the wallet",
+		},
+		gcli.StringSliceFlag{
+			Name:  "outputAddress",
+			Usage: "Addresses of the output for the transaction",
+		},
+		gcli.Int64SliceFlag{
+			Name:  "coin",
+			Usage: "Amount of coins",
+		},
+		gcli.Int64SliceFlag{
+			Name:  "hour",
+			Usage: "Number of hours",
+		},
+		gcli.IntSliceFlag{
+			Name:  "addressIndex",
+			Usage: "If the address is a return address tell its index in the wallet",
+		},
+	}
+}
+
+// verifyParamsFlags are the flags giving the rules of the node the
+// transaction is checked against
+func verifyParamsFlags() []gcli.Flag {
+	return []gcli.Flag{
+		gcli.Uint64Flag{
+			Name:   "burnFactor",
+			Value:  skyWallet.DefaultBurnFactor,
+			Usage:  "Burn factor of the node, the inverse of the minimum share of the input hours to burn",
+			EnvVar: "USER_BURN_FACTOR",
+		},
+		gcli.Uint64Flag{
+			Name:   "maxDecimals",
+			Value:  skyWallet.DefaultMaxDropletPrecision,
+			Usage:  "Number of decimals of the amounts the node allows",
+			EnvVar: "USER_MAX_DECIMALS",
+		},
+	}
+}
+
+// verifyParams returns the rules of the node given by the verifyParamsFlags
+func verifyParams(c *gcli.Context) skyWallet.VerifyParams {
+	return skyWallet.VerifyParams{
+		BurnFactor:          c.Uint64("burnFactor"),
+		MaxDropletPrecision: c.Uint64("maxDecimals"),
+	}
+}
+
+// transactionMessages returns the inputs and outputs given by the transactionFlags,
+// it tells the user and returns false if they do not match
+func transactionMessages(c *gcli.Context) ([]*messages.SkycoinTransactionInput, []*messages.SkycoinTransactionOutput, bool) {
+	inputs := c.StringSlice("inputHash")
+	inputIndex := c.IntSlice("inputIndex")
+	outputs := c.StringSlice("outputAddress")
+	coins := c.Int64Slice("coin")
+	hours := c.Int64Slice("hour")
+	addressIndex := c.IntSlice("addressIndex")
+
+	if len(inputs) != len(inputIndex) {
+		fmt.Println("Every given input hash should have the an inputIndex")
+		return nil, nil, false
+	}
+	if len(outputs) != len(coins) || len(outputs) != len(hours) {
+		fmt.Println("Every given output should have a coin and hour value")
+		return nil, nil, false
+	}
+
+	var transactionInputs []*messages.SkycoinTransactionInput
+	var transactionOutputs []*messages.SkycoinTransactionOutput
+	for i, input := range inputs {
+		var transactionInput messages.SkycoinTransactionInput
+		transactionInput.HashIn = proto.String(input)
+		transactionInput.Index = proto.Uint32(uint32(inputIndex[i]))
+		transactionInputs = append(transactionInputs, &transactionInput)
+	}
+	for i, output := range outputs {
+		var transactionOutput messages.SkycoinTransactionOutput
+		transactionOutput.Address = proto.String(output)
+		transactionOutput.Coin = proto.Uint64(uint64(coins[i]))
+		transactionOutput.Hour = proto.Uint64(uint64(hours[i]))
+		if i < len(addressIndex) {
+			transactionOutput.AddressIndex = proto.Uint32(uint32(addressIndex[i]))
+		}
+		transactionOutputs = append(transactionOutputs, &transactionOutput)
+	}
+	return transactionInputs, transactionOutputs, true
+}
+
+// writeSummary prints the summary as JSON on stdout, or as text on stderr so
+// that the output of the command is not mixed with it
+func writeSummary(summary *skyWallet.TransactionSummary, asJSON bool) error {
+	if !asJSON {
+		summary.Write(os.Stderr)
+		return nil
+	}
+	b, err := json.MarshalIndent(summary, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+// confirmTransaction asks the user to review the summary before the transaction
+// is sent to the device, where it has to be confirmed again
+func confirmTransaction() (bool, error) {
+	fmt.Fprint(os.Stderr, "Sign this transaction? [y/N]: ")
+	answer, err := newTerminalInput(os.Stderr).readLine()
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+var errNegativeAmount = errors.New("amount must not be negative")
+
+// negativeAmounts returns an error for each negative value of the flag name
+func negativeAmounts(name string, values []int64) skyWallet.ValidationErrors {
+	var errs skyWallet.ValidationErrors
+	for i, v := range values {
+		if v < 0 {
+			errs = append(errs, skyWallet.FieldError{Field: fmt.Sprintf("%s %d", name, i+1), Err: errNegativeAmount})
+		}
+	}
+	return errs
+}
+
+// reportInvalidFields writes the error returned by the validation of a request
+// to stderr, one line per invalid field
+func reportInvalidFields(err error) {
+	errs, ok := err.(skyWallet.ValidationErrors)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Invalid request: %v\n", err)
+		return
+	}
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "Invalid %s: %v\n", e.Field, e.Err)
+	}
+}
+
+// partialTransaction loads the partially signed transaction at path or, if
+// the transaction is given by the flags, creates it. An existing file is not
+// replaced by a new transaction.
+func partialTransaction(path string, inputs []*messages.SkycoinTransactionInput, inputAddresses []string, outputs []*messages.SkycoinTransactionOutput) (*psbt.Transaction, error) {
+	if len(inputs) == 0 && len(outputs) == 0 {
+		return psbt.Load(path)
+	}
+	if len(inputAddresses) != len(inputs) {
+		return nil, errors.New("every given input should have an inputAddress to create a partially signed transaction")
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%s already exists, remove the transaction flags to sign it", path)
+	}
+
+	partialInputs := make([]psbt.Input, len(inputs))
+	for i, in := range inputs {
+		partialInputs[i] = psbt.Input{
+			Hash:         in.GetHashIn(),
+			Address:      inputAddresses[i],
+			AddressIndex: in.GetIndex(),
+		}
+	}
+	partialOutputs := make([]psbt.Output, len(outputs))
+	for i, out := range outputs {
+		partialOutputs[i] = psbt.Output{
+			Address:      out.GetAddress(),
+			Coins:        out.GetCoin(),
+			Hours:        out.GetHour(),
+			AddressIndex: out.AddressIndex,
+		}
+	}
+	return psbt.New(partialInputs, partialOutputs), nil
+}
+
+// signPartialTransaction signs the inputs of partial owned by the device and saves it to path
+func signPartialTransaction(device skyWallet.Devicer, partial *psbt.Transaction, path string) {
+	signed, err := skyWallet.SignPartialTransaction(device, partial, interact)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if err = partial.Save(path); err != nil {
+		log.Error(err)
+		return
+	}
+
+	if len(signed) == 0 {
+		fmt.Println("The device does not own any unsigned input")
+	}
+	for _, i := range signed {
+		fmt.Printf("Signed input %d\n", i+1)
+	}
+	if unsigned := partial.Unsigned(); len(unsigned) != 0 {
+		fmt.Printf("%d of %d inputs are still unsigned, sign them with the other devices\n", len(unsigned), len(partial.Inputs))
+		return
+	}
+	fmt.Println("All the inputs are signed, use combine to get the transaction")
+}
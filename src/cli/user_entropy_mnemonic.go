@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	gcli "github.com/urfave/cli"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/bip39"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/userentropy"
+)
+
+func userEntropyMnemonicCmd() gcli.Command {
+	name := "userEntropyMnemonic"
+	return gcli.Command{
+		Name:  name,
+		Usage: "Build a mnemonic from dice rolls or shuffled cards and optionally configure the device with it.",
+		Description: `The dice rolls or the cards are read from stdin, they are prompted for without echo if stdin is a terminal.
+        Dice rolls are digits from 1 to 6. Cards are a rank, A 2 3 4 5 6 7 8 9 10 T J Q K, followed by a suit, C D H S,
+        separated by spaces, for instance "10H AS QD". The entropy is the beginning of the sha256 of the input, every
+        step of the derivation is printed so it can be checked with other tools.`,
+		Flags: []gcli.Flag{
+			gcli.StringFlag{
+				Name:  "source",
+				Usage: "Entropy source, dice or cards",
+				Value: "dice",
+			},
+			gcli.IntFlag{
+				Name:  "wordCount",
+				Usage: "Use a specific (12 | 24) number of words for the Mnemonic",
+				Value: 12,
+			},
+			secretsFdFlag(),
+			gcli.BoolFlag{
+				Name:  "setMnemonic",
+				Usage: "Configure the device with the mnemonic and show its first addresses.",
+			},
+			gcli.IntFlag{
+				Name:  "addressN",
+				Usage: "Number of addresses shown once the device is configured",
+				Value: 3,
+			},
+			gcli.StringFlag{
+				Name:   "deviceType",
+				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
+				EnvVar: "DEVICE_TYPE",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			wordCount := c.Int("wordCount")
+			if wordCount != 12 && wordCount != 24 {
+				log.Error(skyWallet.ErrInvalidWordCount)
+				return
+			}
+
+			var parse func(string) (userentropy.Source, error)
+			var prompt string
+			switch c.String("source") {
+			case "dice":
+				parse = userentropy.ParseDice
+				prompt = "Dice rolls: "
+			case "cards":
+				parse = userentropy.ParseCards
+				prompt = "Cards: "
+			default:
+				log.Errorf("invalid source %q, valid values are dice or cards", c.String("source"))
+				return
+			}
+
+			input, err := secretsInput(c).readSecret(prompt)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			source, err := parse(input)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			// 11 bits per word, 1 bit out of 33 is the checksum
+			entropy, err := source.Entropy(wordCount * 11 * 32 / 33)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			derivation, err := bip39.NewDerivation(entropy)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			printDerivation(os.Stdout, source, derivation)
+
+			if !c.Bool("setMnemonic") {
+				return
+			}
+
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			if device == nil {
+				return
+			}
+			defer device.Close()
+
+			if os.Getenv("AUTO_PRESS_BUTTONS") == "1" && device.Driver.DeviceType() == skyWallet.DeviceTypeEmulator && runtime.GOOS == "linux" {
+				err := device.SetAutoPressButton(true, skyWallet.ButtonRight)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			msg, err := device.SetMnemonic(derivation.Mnemonic())
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			responseMsg, err := skyWallet.DecodeSuccessOrFailMsg(msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if msg.Kind == uint16(messages.MessageType_MessageType_Failure) {
+				log.Error(responseMsg)
+				return
+			}
+			fmt.Println(responseMsg)
+
+			msg, err = device.AddressGen(uint32(c.Int("addressN")), 0, false)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if msg.Kind != uint16(messages.MessageType_MessageType_ResponseSkycoinAddress) {
+				failMsg, err := skyWallet.DecodeFailMsg(msg)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+				log.Error(failMsg)
+				return
+			}
+			addresses, err := skyWallet.DecodeResponseSkycoinAddress(msg)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			fmt.Println("First addresses of the wallet, check them with another wallet software restoring the mnemonic:")
+			for _, address := range addresses {
+				fmt.Println(address)
+			}
+		},
+	}
+}
+
+// printDerivation writes every step turning the user input into the mnemonic
+func printDerivation(w io.Writer, source userentropy.Source, d *bip39.Derivation) {
+	hash := sha256.Sum256([]byte(source.Normalized))
+	fmt.Fprintf(w, "Input: %d %s, %.1f bits of entropy\n", source.Count, source.Kind, source.Bits)
+	fmt.Fprintf(w, "Normalized input: %s\n", source.Normalized)
+	fmt.Fprintf(w, "sha256(normalized input): %x\n", hash)
+	fmt.Fprintf(w, "Entropy, first %d bits of the hash: %x\n", len(d.Entropy)*8, d.Entropy)
+	fmt.Fprintf(w, "Checksum, first %d bits of sha256(entropy): %0*b\n", d.ChecksumBits, d.ChecksumBits, d.Checksum)
+	fmt.Fprintln(w, "Words, 11 bits of the entropy followed by the checksum each:")
+	for i, index := range d.Indices {
+		fmt.Fprintf(w, "%4d  %011b  %4d  %s\n", i+1, index, index, d.Words[i])
+	}
+	fmt.Fprintf(w, "Mnemonic: %s\n", d.Mnemonic())
+}
@@ -2,6 +2,13 @@ package cli
 
 import (
 	"errors"
+	"fmt"
+	"os"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
 )
 
 func parseBool(s string) (*bool, error) {
@@ -18,3 +25,75 @@ func parseBool(s string) (*bool, error) {
 	}
 	return &b, nil
 }
+
+// interact answers the button, PIN matrix and passphrase requests of the device
+// until it sends any other message. Prompts are written to stderr and the passphrase
+// fingerprint is shown if a passphrase was entered.
+func interact(device skyWallet.Devicer, msg wire.Message) (wire.Message, error) {
+	msg, passphraseEntered, err := answerRequests(device, msg)
+	if err != nil {
+		return wire.Message{}, err
+	}
+	if passphraseEntered {
+		showPassphraseFingerprint(device)
+	}
+	return msg, nil
+}
+
+// answerRequests is interact without showing the passphrase fingerprint,
+// it also tells if a passphrase was entered
+func answerRequests(device skyWallet.Devicer, msg wire.Message) (wire.Message, bool, error) {
+	var err error
+	passphraseEntered := false
+	for {
+		switch msg.Kind {
+		case uint16(messages.MessageType_MessageType_ButtonRequest):
+			msg, err = device.ButtonAck()
+		case uint16(messages.MessageType_MessageType_PinMatrixRequest):
+			var pinEnc string
+			pinEnc, err = newTerminalInput(os.Stderr).readPinMatrix()
+			if err != nil {
+				return wire.Message{}, false, err
+			}
+			msg, err = device.PinMatrixAck(pinEnc)
+		case uint16(messages.MessageType_MessageType_PassphraseRequest):
+			var passphrase string
+			passphrase, err = newTerminalInput(os.Stderr).readPassphrase()
+			if err != nil {
+				return wire.Message{}, false, err
+			}
+			msg, err = device.PassphraseAck(passphrase)
+			passphraseEntered = true
+		default:
+			return msg, passphraseEntered, nil
+		}
+		if err != nil {
+			return wire.Message{}, false, err
+		}
+	}
+}
+
+// showPassphraseFingerprint writes the fingerprint of the wallet opened by the
+// passphrase to stderr, with its nickname if known in the --fingerprintBook
+func showPassphraseFingerprint(device skyWallet.Devicer) {
+	fp, err := device.PassphraseFingerprint()
+	if err != nil {
+		log.Errorf("failed to compute the passphrase fingerprint: %v", err)
+		return
+	}
+
+	if fingerprintBook == "" {
+		fmt.Fprintf(os.Stderr, "Passphrase fingerprint: %s\n", fp)
+		return
+	}
+	book, err := skyWallet.LoadFingerprintBook(fingerprintBook)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if nickname, ok := book.Nickname(fp); ok {
+		fmt.Fprintf(os.Stderr, "Passphrase fingerprint: %s (%s)\n", fp, nickname)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "WARNING: unknown passphrase fingerprint %s, check the passphrase for typos if this is not a new hidden wallet\n", fp)
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	gcli "github.com/urfave/cli"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/bip39"
+)
+
+func validateMnemonicCmd() gcli.Command {
+	name := "validateMnemonic"
+	return gcli.Command{
+		Name:        name,
+		Usage:       "Check the words, the word count and the checksum of a mnemonic, no device is needed.",
+		Description: "The mnemonic is read from stdin, it is prompted for without echo if stdin is a terminal. The command exits with status 1 if the mnemonic is not valid.",
+		Flags: []gcli.Flag{
+			secretsFdFlag(),
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			mnemonic, err := secretsInput(c).readMnemonic()
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			if err := bip39.ValidateMnemonic(mnemonic); err != nil {
+				reportInvalidMnemonic(err)
+				os.Exit(1)
+			}
+
+			fmt.Println("Valid mnemonic")
+		},
+	}
+}
+
+// reportInvalidMnemonic writes the error returned by bip39.ValidateMnemonic to stderr
+// with the suggestions to fix the mnemonic
+func reportInvalidMnemonic(err error) {
+	switch e := err.(type) {
+	case *bip39.UnknownWordsError:
+		for _, w := range e.Words {
+			if len(w.Suggestions) == 0 {
+				fmt.Fprintf(os.Stderr, "Word %d is not in the BIP39 wordlist\n", w.Position)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Word %d is not in the BIP39 wordlist, did you mean: %s\n", w.Position, strings.Join(w.Suggestions, ", "))
+		}
+	case *bip39.ChecksumError:
+		fmt.Fprintf(os.Stderr, "Invalid mnemonic checksum, check the words or replace the last word with one of:\n%s\n", strings.Join(e.LastWords, " "))
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid mnemonic: %v\n", err)
+	}
+}
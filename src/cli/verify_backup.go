@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	gcli "github.com/urfave/cli"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+)
+
+func verifyBackupCmd() gcli.Command {
+	name := "verifyBackup"
+	return gcli.Command{
+		Name:  name,
+		Usage: "Check that the written down recovery words match the device seed with a dry-run recovery.",
+		Description: `The device must be initialized and its backup done. The words are asked in the order displayed by
+        the device, the seed of the device is left untouched whatever the result. The command exits with status 1
+        if the words do not match the seed.`,
+		Flags: []gcli.Flag{
+			gcli.IntFlag{
+				Name:  "wordCount",
+				Usage: "Number of words (12 | 24) of the backup",
+				Value: 12,
+			},
+			gcli.StringFlag{
+				Name:  "record",
+				Usage: "Append a timestamped JSON line with the result of the verification and the device id to this file",
+			},
+			gcli.StringFlag{
+				Name:   "deviceType",
+				Usage:  "Device type to send instructions to, hardware wallet (USB) or emulator.",
+				EnvVar: "DEVICE_TYPE",
+			},
+		},
+		OnUsageError: onCommandUsageError(name),
+		Action: func(c *gcli.Context) {
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			if device == nil {
+				return
+			}
+			defer device.Close()
+
+			if os.Getenv("AUTO_PRESS_BUTTONS") == "1" && device.Driver.DeviceType() == skyWallet.DeviceTypeEmulator && runtime.GOOS == "linux" {
+				err := device.SetAutoPressButton(true, skyWallet.ButtonRight)
+				if err != nil {
+					log.Error(err)
+					return
+				}
+			}
+
+			verification, err := skyWallet.VerifyBackup(device, uint32(c.Uint64("wordCount")), interact, newTerminalInput(os.Stderr).readWord)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			if path := c.String("record"); path != "" {
+				if err = skyWallet.AppendBackupVerification(path, *verification); err != nil {
+					log.Error(err)
+				}
+			}
+
+			if !verification.Verified {
+				fmt.Fprintf(os.Stderr, "Backup verification failed, the words do not match the device seed: %s\n", verification.Message)
+				os.Exit(1)
+			}
+			fmt.Printf("Backup verified, the words match the seed of device %s\n", verification.DeviceID)
+		},
+	}
+}
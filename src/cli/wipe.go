@@ -5,8 +5,6 @@ import (
 	"os"
 	"runtime"
 
-	messages "github.com/skycoin/hardware-wallet-protob/go"
-
 	gcli "github.com/urfave/cli"
 
 	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
@@ -27,7 +25,7 @@ func wipeCmd() gcli.Command {
 			},
 		},
 		Action: func(c *gcli.Context) {
-			device := skyWallet.NewDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
+			device := newDevice(skyWallet.DeviceTypeFromString(c.String("deviceType")))
 			if device == nil {
 				return
 			}
@@ -47,12 +45,10 @@ func wipeCmd() gcli.Command {
 				return
 			}
 
-			if msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-				msg, err = device.ButtonAck()
-				if err != nil {
-					log.Error(err)
-					return
-				}
+			msg, err = interact(device, msg)
+			if err != nil {
+				log.Error(err)
+				return
 			}
 
 			responseMsg, err := skyWallet.DecodeSuccessOrFailMsg(msg)
@@ -0,0 +1,27 @@
+package skywallet
+
+import (
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// MnemonicAddresses returns the addresses AddressGen(n, startIndex, false) gives
+// once the device is configured with mnemonic and no passphrase is in use
+func MnemonicAddresses(mnemonic string, n, startIndex uint32) ([]string, error) {
+	if n == 0 {
+		return nil, ErrAddressNZero
+	}
+	keys, err := cipher.GenerateDeterministicKeyPairs([]byte(mnemonic), int(startIndex+n))
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, 0, n)
+	for _, key := range keys[startIndex:] {
+		var address cipher.Address
+		address, err = cipher.AddressFromSecKey(key)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address.String())
+	}
+	return addresses, nil
+}
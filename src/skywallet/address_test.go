@@ -0,0 +1,21 @@
+package skywallet
+
+import (
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *devicerSuit) TestMnemonicAddresses() {
+	// addresses returned by the emulator configured with this mnemonic
+	mnemonic := "cloud flower upset remain green metal below cup stem infant art thank"
+
+	addresses, err := MnemonicAddresses(mnemonic, 2, 0)
+	suite.Nil(err)
+	require.Equal(suite.T(), []string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"}, addresses)
+
+	addresses, err = MnemonicAddresses(mnemonic, 1, 1)
+	suite.Nil(err)
+	require.Equal(suite.T(), []string{"zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"}, addresses)
+
+	_, err = MnemonicAddresses(mnemonic, 0, 0)
+	suite.Equal(ErrAddressNZero, err)
+}
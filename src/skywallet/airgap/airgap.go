@@ -0,0 +1,207 @@
+/*
+Package airgap carries payloads, such as partially signed transactions, between
+an online host and an offline host with no network connection.
+
+A payload is split into text frames small enough for a QR code each. The frames
+are shown one after the other as an animated sequence of codes and may be read
+back in any order, an Assembler collects them until the payload is complete and
+its checksum matches.
+*/
+package airgap
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/qr"
+)
+
+// FramePrefix starts every frame, it tells the frames apart from other text
+const FramePrefix = "SKYAG1"
+
+// DefaultChunkSize is the number of payload bytes of a frame, so that the
+// frame of the largest payload fits in a QR code
+const DefaultChunkSize = 128
+
+// MaxFrames is the highest number of frames of a payload
+const MaxFrames = 9999
+
+var (
+	// ErrInvalidFrame is returned if a text is not a frame
+	ErrInvalidFrame = errors.New("airgap: invalid frame")
+	// ErrMixedFrames is returned if a frame belongs to another payload than the frames added before
+	ErrMixedFrames = errors.New("airgap: frame of another payload")
+	// ErrChecksum is returned if the assembled payload does not match its checksum
+	ErrChecksum = errors.New("airgap: payload checksum mismatch")
+	// ErrIncomplete is returned if frames are missing to assemble the payload
+	ErrIncomplete = errors.New("airgap: frames are missing")
+	// ErrEmptyPayload is returned when splitting an empty payload
+	ErrEmptyPayload = errors.New("airgap: empty payload")
+)
+
+// Frames splits payload in frames of at most chunkSize bytes of payload,
+// each of them fits in a QR code
+func Frames(payload []byte, chunkSize int) ([]string, error) {
+	if len(payload) == 0 {
+		return nil, ErrEmptyPayload
+	}
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("airgap: invalid chunk size %d", chunkSize)
+	}
+	total := (len(payload) + chunkSize - 1) / chunkSize
+	if total > MaxFrames {
+		return nil, fmt.Errorf("airgap: payload needs %d frames, at most %d are supported", total, MaxFrames)
+	}
+
+	checksum := crc32.ChecksumIEEE(payload)
+	frames := make([]string, total)
+	for i := range frames {
+		end := (i + 1) * chunkSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		frames[i] = fmt.Sprintf("%s:%d/%d:%08x:%s", FramePrefix, i+1, total, checksum,
+			base64.StdEncoding.EncodeToString(payload[i*chunkSize:end]))
+		if len(frames[i]) > qr.MaxBytes {
+			return nil, fmt.Errorf("airgap: chunk size %d does not fit in a QR code", chunkSize)
+		}
+	}
+	return frames, nil
+}
+
+// frame is a parsed frame
+type frame struct {
+	index, total int
+	checksum     uint32
+	chunk        []byte
+}
+
+// parseFrame parses a frame written by Frames
+func parseFrame(text string) (frame, error) {
+	parts := strings.Split(strings.TrimSpace(text), ":")
+	if len(parts) != 4 || parts[0] != FramePrefix {
+		return frame{}, ErrInvalidFrame
+	}
+	position := strings.Split(parts[1], "/")
+	if len(position) != 2 {
+		return frame{}, ErrInvalidFrame
+	}
+	index, err := strconv.Atoi(position[0])
+	if err != nil {
+		return frame{}, ErrInvalidFrame
+	}
+	total, err := strconv.Atoi(position[1])
+	if err != nil || total < 1 || total > MaxFrames || index < 1 || index > total {
+		return frame{}, ErrInvalidFrame
+	}
+	checksum, err := strconv.ParseUint(parts[2], 16, 32)
+	if err != nil {
+		return frame{}, ErrInvalidFrame
+	}
+	chunk, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil || len(chunk) == 0 {
+		return frame{}, ErrInvalidFrame
+	}
+	return frame{index: index, total: total, checksum: uint32(checksum), chunk: chunk}, nil
+}
+
+// IsFrame tells whether text looks like a frame, it may still be invalid
+func IsFrame(text string) bool {
+	return strings.HasPrefix(strings.TrimSpace(text), FramePrefix+":")
+}
+
+// Assembler collects the frames of a payload, in any order and with
+// duplicates, as they are read from a scanner
+type Assembler struct {
+	total    int
+	checksum uint32
+	chunks   map[int][]byte
+}
+
+// NewAssembler returns an Assembler with no frame
+func NewAssembler() *Assembler {
+	return &Assembler{chunks: make(map[int][]byte)}
+}
+
+// Add adds a frame and tells whether the payload is complete. A frame already
+// added is ignored.
+func (a *Assembler) Add(text string) (bool, error) {
+	f, err := parseFrame(text)
+	if err != nil {
+		return false, err
+	}
+	if a.total == 0 {
+		a.total = f.total
+		a.checksum = f.checksum
+	} else if f.total != a.total || f.checksum != a.checksum {
+		return false, ErrMixedFrames
+	}
+	if _, ok := a.chunks[f.index]; !ok {
+		a.chunks[f.index] = f.chunk
+	}
+	return a.Complete(), nil
+}
+
+// Progress returns the number of frames added and the number of frames of the
+// payload, 0 until a frame is added
+func (a *Assembler) Progress() (int, int) {
+	return len(a.chunks), a.total
+}
+
+// Complete tells whether all the frames of the payload were added
+func (a *Assembler) Complete() bool {
+	return a.total != 0 && len(a.chunks) == a.total
+}
+
+// Payload returns the payload of the frames and checks its checksum
+func (a *Assembler) Payload() ([]byte, error) {
+	if !a.Complete() {
+		return nil, ErrIncomplete
+	}
+	var payload []byte
+	for i := 1; i <= a.total; i++ {
+		payload = append(payload, a.chunks[i]...)
+	}
+	if crc32.ChecksumIEEE(payload) != a.checksum {
+		return nil, ErrChecksum
+	}
+	return payload, nil
+}
+
+// Animate writes the frames to w as QR codes, each of them drawn over the
+// previous one after delay, and shows the sequence loops times
+func Animate(w io.Writer, frames []string, delay time.Duration, loops int) error {
+	codes := make([]string, len(frames))
+	for i, f := range frames {
+		c, err := qr.Encode([]byte(f))
+		if err != nil {
+			return err
+		}
+		codes[i] = c.Terminal()
+	}
+
+	height := 0
+	for loop := 0; loop < loops; loop++ {
+		for i, code := range codes {
+			if height != 0 {
+				// move the cursor up to the start of the previous code and clear the screen below
+				if _, err := fmt.Fprintf(w, "\x1b[%dA\x1b[J", height); err != nil {
+					return err
+				}
+			}
+			caption := fmt.Sprintf("frame %d/%d\n", i+1, len(codes))
+			if _, err := io.WriteString(w, code+caption); err != nil {
+				return err
+			}
+			height = strings.Count(code+caption, "\n")
+			time.Sleep(delay)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package airgap
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomPayload(t *testing.T, n int) []byte {
+	payload := make([]byte, n)
+	_, err := rand.New(rand.NewSource(int64(n))).Read(payload)
+	require.NoError(t, err)
+	return payload
+}
+
+func TestFrames(t *testing.T) {
+	tt := []struct {
+		name   string
+		size   int
+		frames int
+	}{
+		{"one byte", 1, 1},
+		{"one chunk", DefaultChunkSize, 1},
+		{"partial last chunk", 3*DefaultChunkSize + 1, 4},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := randomPayload(t, tc.size)
+			frames, err := Frames(payload, DefaultChunkSize)
+			require.NoError(t, err)
+			require.Len(t, frames, tc.frames)
+
+			// the frames may be read in any order and more than once
+			a := NewAssembler()
+			order := rand.New(rand.NewSource(1)).Perm(len(frames))
+			for i, j := range append(order, order...) {
+				done, err := a.Add(frames[j])
+				require.NoError(t, err)
+				require.Equal(t, i >= len(frames)-1, done)
+			}
+			have, total := a.Progress()
+			require.Equal(t, tc.frames, have)
+			require.Equal(t, tc.frames, total)
+			assembled, err := a.Payload()
+			require.NoError(t, err)
+			require.Equal(t, payload, assembled)
+		})
+	}
+
+	_, err := Frames(nil, DefaultChunkSize)
+	require.Equal(t, ErrEmptyPayload, err)
+	_, err = Frames(randomPayload(t, 1000), 1000)
+	require.Error(t, err)
+}
+
+func TestAssemblerErrors(t *testing.T) {
+	frames, err := Frames(randomPayload(t, 300), DefaultChunkSize)
+	require.NoError(t, err)
+	other, err := Frames(randomPayload(t, 301), DefaultChunkSize)
+	require.NoError(t, err)
+
+	a := NewAssembler()
+	_, err = a.Payload()
+	require.Equal(t, ErrIncomplete, err)
+	for _, invalid := range []string{"", "SKYAG1:1/2:00000000", "SKYAG1:3/2:00000000:c2t5", "SKYAG1:1/2:00000000:!"} {
+		_, err = a.Add(invalid)
+		require.Equal(t, ErrInvalidFrame, err, invalid)
+	}
+	_, err = a.Add(frames[0])
+	require.NoError(t, err)
+	_, err = a.Add(other[1])
+	require.Equal(t, ErrMixedFrames, err)
+
+	// a damaged chunk is caught by the checksum
+	a = NewAssembler()
+	for i, f := range frames {
+		if i == 1 {
+			parts := strings.Split(f, ":")
+			parts[3] = "AAAA" + parts[3][4:]
+			f = strings.Join(parts, ":")
+		}
+		_, err = a.Add(f)
+		require.NoError(t, err)
+	}
+	_, err = a.Payload()
+	require.Equal(t, ErrChecksum, err)
+}
+
+func TestRead(t *testing.T) {
+	payload := randomPayload(t, 500)
+	frames, err := Frames(payload, DefaultChunkSize)
+	require.NoError(t, err)
+
+	t.Run("scanned lines", func(t *testing.T) {
+		text := "scanner ready\n" + strings.Join([]string{frames[3], frames[1], frames[1], frames[0], frames[2]}, "\n") + "\n"
+		var progress []int
+		read, err := NewAssembler().Read(strings.NewReader(text), func(have, total int) {
+			require.Equal(t, len(frames), total)
+			progress = append(progress, have)
+		})
+		require.NoError(t, err)
+		require.Equal(t, payload, read)
+		require.Equal(t, []int{1, 2, 3, 4}, progress)
+	})
+
+	t.Run("animated codes", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, Animate(&b, frames, 0, 2))
+		read, err := NewAssembler().Read(&b, nil)
+		require.NoError(t, err)
+		require.Equal(t, payload, read)
+	})
+
+	t.Run("missing frame", func(t *testing.T) {
+		_, err := NewAssembler().Read(strings.NewReader(strings.Join(frames[1:], "\n")), nil)
+		require.Equal(t, ErrIncomplete, err)
+	})
+}
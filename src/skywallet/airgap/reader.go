@@ -0,0 +1,88 @@
+package airgap
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/qr"
+)
+
+// escapes are the terminal control sequences written by Animate
+var escapes = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+
+// isBlockLine tells whether line is a line of a QR code drawn by qr.Terminal
+func isBlockLine(line string) bool {
+	if strings.TrimSpace(line) == "" {
+		return false
+	}
+	for _, r := range line {
+		switch r {
+		case '█', '▀', '▄', ' ':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Read adds to a the frames read from r until the payload is complete. The
+// frames are either one per line, as most QR scanners type them, or the QR
+// codes drawn by Animate, for example recorded from a terminal. progress is
+// called after each new frame, it may be nil.
+func (a *Assembler) Read(r io.Reader, progress func(have, total int)) ([]byte, error) {
+	var code []string
+	add := func(text string) error {
+		have, _ := a.Progress()
+		if _, err := a.Add(text); err != nil {
+			return err
+		}
+		if n, total := a.Progress(); n != have && progress != nil {
+			progress(n, total)
+		}
+		return nil
+	}
+	// flush decodes the QR code whose lines were read so far
+	flush := func() error {
+		if len(code) == 0 {
+			return nil
+		}
+		c, err := qr.ParseTerminal(strings.Join(code, "\n"))
+		code = code[:0]
+		if err != nil {
+			return err
+		}
+		data, err := qr.Decode(c)
+		if err != nil {
+			return err
+		}
+		return add(string(data))
+	}
+
+	scanner := bufio.NewScanner(r)
+	for !a.Complete() && scanner.Scan() {
+		line := escapes.ReplaceAllString(scanner.Text(), "")
+		if isBlockLine(line) {
+			code = append(code, line)
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		if IsFrame(line) {
+			if err := add(line); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !a.Complete() {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	return a.Payload()
+}
@@ -0,0 +1,50 @@
+/*
+Package bip39 contains the BIP39 wordlist and helpers to look up and
+complete mnemonic words offline.
+*/
+package bip39
+
+import (
+	"sort"
+	"strings"
+)
+
+// UniquePrefixLen is the number of letters that identify a word of the wordlist
+const UniquePrefixLen = 4
+
+// WordIndex returns the index of word in the english wordlist
+func WordIndex(word string) (int, bool) {
+	i := sort.SearchStrings(English, word)
+	if i < len(English) && English[i] == word {
+		return i, true
+	}
+	return 0, false
+}
+
+// IsWord tells if word belongs to the english wordlist
+func IsWord(word string) bool {
+	_, ok := WordIndex(word)
+	return ok
+}
+
+// Complete returns the words of the english wordlist starting with prefix
+func Complete(prefix string) []string {
+	i := sort.SearchStrings(English, prefix)
+	j := i
+	for j < len(English) && strings.HasPrefix(English[j], prefix) {
+		j++
+	}
+	return English[i:j]
+}
+
+// Expand returns the only word of the english wordlist starting with prefix,
+// or the word itself if it belongs to the wordlist
+func Expand(prefix string) (string, bool) {
+	if IsWord(prefix) {
+		return prefix, true
+	}
+	if words := Complete(prefix); len(words) == 1 {
+		return words[0], true
+	}
+	return "", false
+}
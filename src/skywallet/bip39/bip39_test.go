@@ -0,0 +1,68 @@
+package bip39
+
+import (
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnglish(t *testing.T) {
+	// crc32 of https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+	require.Equal(t, "c1dbd296", fmt.Sprintf("%x", crc32.ChecksumIEEE([]byte(english))))
+	require.Len(t, English, 2048)
+	require.True(t, sort.StringsAreSorted(English))
+
+	prefixes := make(map[string]bool)
+	for _, w := range English {
+		p := w
+		if len(p) > UniquePrefixLen {
+			p = p[:UniquePrefixLen]
+		}
+		require.False(t, prefixes[p], p)
+		prefixes[p] = true
+	}
+}
+
+func TestWordIndex(t *testing.T) {
+	i, ok := WordIndex("abandon")
+	require.True(t, ok)
+	require.Equal(t, 0, i)
+	i, ok = WordIndex("zoo")
+	require.True(t, ok)
+	require.Equal(t, 2047, i)
+	_, ok = WordIndex("skycoin")
+	require.False(t, ok)
+	require.False(t, IsWord(""))
+}
+
+func TestComplete(t *testing.T) {
+	require.Equal(t, []string{"zebra", "zero", "zone", "zoo"}, Complete("z"))
+	require.Equal(t, []string{"zone", "zoo"}, Complete("zo"))
+	require.Empty(t, Complete("zz"))
+	require.Len(t, Complete(""), 2048)
+}
+
+func TestExpand(t *testing.T) {
+	tt := []struct {
+		prefix string
+		word   string
+		ok     bool
+	}{
+		{"aban", "abandon", true},
+		{"abandon", "abandon", true},
+		{"act", "act", true},
+		{"ab", "", false},
+		{"", "", false},
+		{"xyz", "", false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.prefix, func(t *testing.T) {
+			word, ok := Expand(tc.prefix)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.word, word)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package bip39
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/redact"
+)
+
+var (
+	// ErrInvalidEntropyLength is returned if the entropy is not 128 or 256 bits long,
+	// the entropy of the 12 and 24 words mnemonics the device accepts
+	ErrInvalidEntropyLength = errors.New("entropy must be 16 or 32 bytes long")
+)
+
+// Derivation details how a mnemonic encodes its entropy, so it can be audited
+type Derivation struct {
+	Entropy []byte
+	// Checksum holds the first ChecksumBits bits of the sha256 of the entropy, right aligned
+	Checksum     byte
+	ChecksumBits int
+	// Indices are the positions in the wordlist of the Words, each one encoding 11 bits
+	// of the entropy followed by the checksum
+	Indices []int
+	Words   []string
+}
+
+// Mnemonic returns the words of the derivation separated by spaces
+func (d *Derivation) Mnemonic() string {
+	return strings.Join(d.Words, " ")
+}
+
+// NewDerivation encodes entropy into mnemonic words
+func NewDerivation(entropy []byte) (*Derivation, error) {
+	if len(entropy) != 16 && len(entropy) != 32 {
+		return nil, ErrInvalidEntropyLength
+	}
+
+	checksumBits := len(entropy) * 8 / 32
+	hash := sha256.Sum256(entropy)
+	checksum := hash[0] >> uint(8-checksumBits)
+
+	bits := make([]byte, len(entropy)+1)
+	copy(bits, entropy)
+	bits[len(entropy)] = hash[0]
+
+	wordCount := (len(entropy)*8 + checksumBits) / bitsPerWord
+	d := &Derivation{
+		Entropy:      append([]byte(nil), entropy...),
+		Checksum:     checksum,
+		ChecksumBits: checksumBits,
+		Indices:      make([]int, wordCount),
+		Words:        make([]string, wordCount),
+	}
+	for i := range d.Indices {
+		index := 0
+		for b := 0; b < bitsPerWord; b++ {
+			pos := i*bitsPerWord + b
+			index <<= 1
+			if bits[pos/8]&(0x80>>uint(pos%8)) != 0 {
+				index |= 1
+			}
+		}
+		d.Indices[i] = index
+		d.Words[i] = English[index]
+	}
+	return d, nil
+}
+
+// NewMnemonic encodes entropy into a mnemonic
+func NewMnemonic(entropy []byte) (string, error) {
+	d, err := NewDerivation(entropy)
+	if err != nil {
+		return "", err
+	}
+	return d.Mnemonic(), nil
+}
+
+// MnemonicEntropy validates mnemonic and returns the entropy it encodes, see ValidateMnemonic
+func MnemonicEntropy(mnemonic string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	words := strings.Fields(NormalizeMnemonic(mnemonic))
+	bits, err := Decode(words)
+	if err != nil {
+		return nil, err
+	}
+	defer redact.Wipe(bits)
+	entropyBits := len(words) * bitsPerWord * 32 / 33
+	return append([]byte(nil), bits[:entropyBits/8]...), nil
+}
+
+// Encode splits data into wordCount groups of 11 bits and returns the words
+// at these positions of the wordlist, missing bits are zeroes
+func Encode(data []byte, wordCount int) []string {
+	words := make([]string, wordCount)
+	for i := range words {
+		index := 0
+		for b := 0; b < bitsPerWord; b++ {
+			pos := i*bitsPerWord + b
+			index <<= 1
+			if pos/8 < len(data) && data[pos/8]&(0x80>>uint(pos%8)) != 0 {
+				index |= 1
+			}
+		}
+		words[i] = English[index]
+	}
+	return words
+}
+
+// Decode packs the 11 bits positions in the wordlist of words, the last
+// byte is padded with zeroes. It returns a *UnknownWordsError if words are
+// not in the wordlist.
+func Decode(words []string) ([]byte, error) {
+	bits := make([]byte, (len(words)*bitsPerWord+7)/8)
+	var unknown []UnknownWord
+	for i, w := range words {
+		index, ok := WordIndex(w)
+		if !ok {
+			unknown = append(unknown, UnknownWord{
+				Position:    i + 1,
+				Word:        w,
+				Suggestions: Suggest(w),
+			})
+			continue
+		}
+		for b := 0; b < bitsPerWord; b++ {
+			if index&(1<<uint(bitsPerWord-1-b)) != 0 {
+				pos := i*bitsPerWord + b
+				bits[pos/8] |= 0x80 >> uint(pos%8)
+			}
+		}
+	}
+	if len(unknown) != 0 {
+		redact.Wipe(bits)
+		return nil, &UnknownWordsError{Words: unknown}
+	}
+	return bits, nil
+}
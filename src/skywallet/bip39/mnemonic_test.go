@@ -0,0 +1,82 @@
+package bip39
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMnemonic(t *testing.T) {
+	// test vectors from https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+	tt := []struct {
+		entropy  string
+		mnemonic string
+	}{
+		{"00000000000000000000000000000000", strings.Repeat("abandon ", 11) + "about"},
+		{"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f", "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+		{"ffffffffffffffffffffffffffffffff", strings.Repeat("zoo ", 11) + "wrong"},
+		{"0000000000000000000000000000000000000000000000000000000000000000", strings.Repeat("abandon ", 23) + "art"},
+		{"9e885d952ad362caeb4efe34a8e91bd2", "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic"},
+		{"f585c11aec520db57dd353c69554b21a89b20fb0650966fa0a9d6f74fd989d8f", "void come effort suffer camp survey warrior heavy shoot primary clutch crush open amazing screen patrol group space point ten exist slush involve unfold"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.entropy, func(t *testing.T) {
+			entropy, err := hex.DecodeString(tc.entropy)
+			require.NoError(t, err)
+			mnemonic, err := NewMnemonic(entropy)
+			require.NoError(t, err)
+			require.Equal(t, tc.mnemonic, mnemonic)
+			require.NoError(t, ValidateMnemonic(mnemonic))
+			decoded, err := MnemonicEntropy(mnemonic)
+			require.NoError(t, err)
+			require.Equal(t, entropy, decoded)
+		})
+	}
+}
+
+func TestNewDerivation(t *testing.T) {
+	entropy := make([]byte, 16)
+	d, err := NewDerivation(entropy)
+	require.NoError(t, err)
+	require.Equal(t, 4, d.ChecksumBits)
+	// sha256 of 16 zero bytes starts with 0x37
+	require.Equal(t, byte(0x3), d.Checksum)
+	require.Len(t, d.Indices, 12)
+	require.Equal(t, 3, d.Indices[11])
+	require.Equal(t, "about", d.Words[11])
+
+	d, err = NewDerivation(make([]byte, 32))
+	require.NoError(t, err)
+	require.Equal(t, 8, d.ChecksumBits)
+	require.Len(t, d.Words, 24)
+
+	// 15, 18 and 21 words mnemonics are valid BIP39 but the device refuses them
+	for _, n := range []int{0, 15, 17, 20, 24, 28, 33} {
+		_, err := NewDerivation(make([]byte, n))
+		require.Equal(t, ErrInvalidEntropyLength, err)
+	}
+}
+
+func TestMnemonicEntropyInvalid(t *testing.T) {
+	_, err := MnemonicEntropy("cloud flower upset remain green metal below cup stem infant art zoo")
+	require.IsType(t, &ChecksumError{}, err)
+}
+
+func TestEncodeDecode(t *testing.T) {
+	data := []byte{0xde, 0xad, 0xbe, 0xef, 0x01}
+	words := Encode(data, 4)
+	require.Equal(t, []string{"team", "hospital", "rookie", "acoustic"}, words)
+
+	decoded, err := Decode(words)
+	require.NoError(t, err)
+	// 44 bits are packed in 6 bytes, the missing bits are zeroes
+	require.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef, 0x01, 0x00}, decoded)
+
+	_, err = Decode([]string{"team", "hospitla"})
+	require.Equal(t, &UnknownWordsError{Words: []UnknownWord{
+		{Position: 2, Word: "hospitla", Suggestions: Suggest("hospitla")},
+	}}, err)
+}
@@ -0,0 +1,236 @@
+package bip39
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/redact"
+)
+
+const (
+	// bits encoded by each word
+	bitsPerWord = 11
+	// maxSuggestions is the maximum number of suggestions for an unknown word
+	maxSuggestions = 5
+	// maxSuggestionDistance is the maximum edit distance of a suggestion to the unknown word
+	maxSuggestionDistance = 2
+)
+
+var (
+	// ErrInvalidWordCount is returned if the mnemonic does not have 12 or 24 words
+	ErrInvalidWordCount = errors.New("mnemonic must have 12 or 24 words")
+)
+
+// UnknownWord is a word of a mnemonic missing from the wordlist
+type UnknownWord struct {
+	// Position of the word in the mnemonic, starting at 1
+	Position int
+	Word     string
+	// Suggestions are the closest words of the wordlist
+	Suggestions []string
+}
+
+// UnknownWordsError is returned if words of a mnemonic are not in the wordlist.
+// Its message gives the positions of the words but not the words themselves.
+type UnknownWordsError struct {
+	Words []UnknownWord
+}
+
+func (e *UnknownWordsError) Error() string {
+	positions := make([]string, len(e.Words))
+	for i, w := range e.Words {
+		positions[i] = fmt.Sprint(w.Position)
+	}
+	if len(positions) == 1 {
+		return fmt.Sprintf("word %s is not in the wordlist", positions[0])
+	}
+	return fmt.Sprintf("words %s are not in the wordlist", strings.Join(positions, ", "))
+}
+
+// ChecksumError is returned if the checksum encoded by the last word of a mnemonic is wrong
+type ChecksumError struct {
+	// LastWords are the words that give a valid checksum when they replace the last word
+	LastWords []string
+}
+
+func (e *ChecksumError) Error() string {
+	return "invalid mnemonic checksum"
+}
+
+// ValidWordCount tells if n is the number of words of a mnemonic the device
+// accepts. BIP39 also defines mnemonics of 15, 18 and 21 words, the firmware
+// only handles 12 and 24.
+func ValidWordCount(n int) bool {
+	return n == 12 || n == 24
+}
+
+// NormalizeMnemonic lower cases the mnemonic and separates its words by a single space
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+// ValidateMnemonic checks the word count, the words and the checksum of mnemonic.
+// It returns ErrInvalidWordCount, a *UnknownWordsError with suggestions for
+// each unknown word or a *ChecksumError with the candidate last words.
+func ValidateMnemonic(mnemonic string) error {
+	words := strings.Fields(NormalizeMnemonic(mnemonic))
+	if !ValidWordCount(len(words)) {
+		return ErrInvalidWordCount
+	}
+
+	indices := make([]int, len(words))
+	var unknown []UnknownWord
+	for i, w := range words {
+		index, ok := WordIndex(w)
+		if !ok {
+			unknown = append(unknown, UnknownWord{
+				Position:    i + 1,
+				Word:        w,
+				Suggestions: Suggest(w),
+			})
+			continue
+		}
+		indices[i] = index
+	}
+	if len(unknown) != 0 {
+		return &UnknownWordsError{Words: unknown}
+	}
+
+	if !validChecksum(indices) {
+		return &ChecksumError{LastWords: LastWords(words[:len(words)-1])}
+	}
+	return nil
+}
+
+// IsMnemonicValid tells if ValidateMnemonic accepts mnemonic
+func IsMnemonicValid(mnemonic string) bool {
+	return ValidateMnemonic(mnemonic) == nil
+}
+
+// LastWords returns the words completing the first words of a mnemonic
+// with a valid checksum. The first words must belong to the wordlist.
+func LastWords(first []string) []string {
+	if !ValidWordCount(len(first) + 1) {
+		return nil
+	}
+	indices := make([]int, len(first)+1)
+	for i, w := range first {
+		index, ok := WordIndex(w)
+		if !ok {
+			return nil
+		}
+		indices[i] = index
+	}
+
+	var words []string
+	for i, w := range English {
+		indices[len(first)] = i
+		if validChecksum(indices) {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// Suggest returns the words of the wordlist closest to word: the word sharing
+// its first letters, then the words at the smallest edit distance
+func Suggest(word string) []string {
+	var suggestions []string
+	if len(word) >= UniquePrefixLen {
+		if w, ok := Expand(word[:UniquePrefixLen]); ok {
+			suggestions = append(suggestions, w)
+		}
+	}
+
+	type candidate struct {
+		word     string
+		distance int
+	}
+	var candidates []candidate
+	for _, w := range English {
+		if d := editDistance(word, w); d <= maxSuggestionDistance {
+			candidates = append(candidates, candidate{w, d})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+	for _, c := range candidates {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+		if len(suggestions) == 0 || suggestions[0] != c.word {
+			suggestions = append(suggestions, c.word)
+		}
+	}
+	return suggestions
+}
+
+// validChecksum tells if the checksum bits at the end of the word indices
+// match the sha256 of the entropy bits before them
+func validChecksum(indices []int) bool {
+	checksumBits := len(indices) * bitsPerWord / 33
+	entropyBits := len(indices)*bitsPerWord - checksumBits
+
+	bits := make([]byte, (len(indices)*bitsPerWord+7)/8)
+	defer redact.Wipe(bits)
+	for i, index := range indices {
+		for b := 0; b < bitsPerWord; b++ {
+			if index&(1<<uint(bitsPerWord-1-b)) != 0 {
+				pos := i*bitsPerWord + b
+				bits[pos/8] |= 0x80 >> uint(pos%8)
+			}
+		}
+	}
+
+	hash := sha256.Sum256(bits[:entropyBits/8])
+	for b := 0; b < checksumBits; b++ {
+		pos := entropyBits + b
+		expected := hash[b/8] & (0x80 >> uint(b%8))
+		actual := bits[pos/8] & (0x80 >> uint(pos%8))
+		if (expected != 0) != (actual != 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// editDistance is the optimal string alignment distance between a and b, the
+// Levenshtein distance counting the transposition of adjacent letters as one edit
+func editDistance(a, b string) int {
+	// rows of the distance matrix for the two previous letters of a and the current one
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = minInt(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
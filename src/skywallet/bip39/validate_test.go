@@ -0,0 +1,113 @@
+package bip39
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validMnemonic12 = "cloud flower upset remain green metal below cup stem infant art thank"
+	validMnemonic24 = "dress fee animal silly multiply demand casino gold pipe matrix latin badge umbrella orbit safe cover glove one dash chicken play obey employ post"
+)
+
+func TestValidateMnemonic(t *testing.T) {
+	tt := []struct {
+		name     string
+		mnemonic string
+		err      error
+	}{
+		{"12 words", validMnemonic12, nil},
+		{"24 words", validMnemonic24, nil},
+		{"bip39 test vector", strings.Repeat("abandon ", 11) + "about", nil},
+		{"bip39 test vector 24 words", strings.Repeat("zoo ", 23) + "vote", nil},
+		{"extra spaces and upper case", "  Cloud flower upset remain green metal below cup stem infant art THANK\n", nil},
+		{"empty", "", ErrInvalidWordCount},
+		{"11 words", strings.Repeat("abandon ", 11), ErrInvalidWordCount},
+		{"23 words", strings.Join(strings.Fields(validMnemonic24)[:23], " "), ErrInvalidWordCount},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.err, ValidateMnemonic(tc.mnemonic))
+			require.Equal(t, tc.err == nil, IsMnemonicValid(tc.mnemonic))
+		})
+	}
+}
+
+func TestValidateMnemonicUnknownWords(t *testing.T) {
+	err := ValidateMnemonic("cloud flowr upset remain green metal below cup stem infant art thnak")
+	require.IsType(t, &UnknownWordsError{}, err)
+	unknown := err.(*UnknownWordsError).Words
+	require.Len(t, unknown, 2)
+	require.Equal(t, 2, unknown[0].Position)
+	require.Equal(t, "flowr", unknown[0].Word)
+	require.Equal(t, "flower", unknown[0].Suggestions[0])
+	require.Equal(t, 12, unknown[1].Position)
+	require.Equal(t, "thank", unknown[1].Suggestions[0])
+	require.Equal(t, "words 2, 12 are not in the wordlist", err.Error())
+	require.NotContains(t, err.Error(), "flowr")
+}
+
+func TestValidateMnemonicChecksum(t *testing.T) {
+	words := strings.Fields(validMnemonic12)
+	words[11] = "zoo"
+	err := ValidateMnemonic(strings.Join(words, " "))
+	require.IsType(t, &ChecksumError{}, err)
+	lastWords := err.(*ChecksumError).LastWords
+	// 7 bits of entropy in the last word of a 12 words mnemonic
+	require.Len(t, lastWords, 128)
+	require.Contains(t, lastWords, "thank")
+	require.NotContains(t, lastWords, "zoo")
+
+	for _, w := range lastWords {
+		words[11] = w
+		require.NoError(t, ValidateMnemonic(strings.Join(words, " ")))
+	}
+}
+
+func TestLastWords(t *testing.T) {
+	words := strings.Fields(validMnemonic24)
+	// 3 bits of entropy in the last word of a 24 words mnemonic
+	lastWords := LastWords(words[:23])
+	require.Len(t, lastWords, 8)
+	require.Contains(t, lastWords, "post")
+
+	require.Nil(t, LastWords(words[:10]))
+	require.Nil(t, LastWords(append([]string{"skycoin"}, words[1:23]...)))
+}
+
+func TestSuggest(t *testing.T) {
+	tt := []struct {
+		word     string
+		expected string
+	}{
+		{"abandn", "abandon"},
+		{"abando", "abandon"},
+		{"zooo", "zoo"},
+		{"gren", "green"},
+		{"mettal", "metal"},
+		{"thnak", "thank"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.word, func(t *testing.T) {
+			suggestions := Suggest(tc.word)
+			require.NotEmpty(t, suggestions)
+			require.True(t, len(suggestions) <= maxSuggestions)
+			require.Equal(t, tc.expected, suggestions[0])
+		})
+	}
+
+	require.Empty(t, Suggest("skycoinwallet"))
+}
+
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, editDistance("zoo", "zoo"))
+	require.Equal(t, 1, editDistance("zoo", "zo"))
+	require.Equal(t, 1, editDistance("zoo", "zon"))
+	require.Equal(t, 1, editDistance("thank", "thnak"))
+	require.Equal(t, 2, editDistance("thank", "tanks"))
+	require.Equal(t, 3, editDistance("", "zoo"))
+}
@@ -0,0 +1,359 @@
+/*
+Package builder builds the unsigned transactions signed by a skywallet from the
+unspent outputs of its addresses.
+
+Build selects the outputs to spend with a Strategy, sends the remaining coins
+back to a change address of the device and shares the coin hours left after
+the fee between the destinations and the change the way the Skycoin wallet
+does. The inputs and outputs it returns are sent as they are to
+Device.TransactionSign.
+*/
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+
+	"github.com/gogo/protobuf/proto"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/psbt"
+)
+
+// DefaultShareFactor is the share of the hours left after the fee sent to the
+// destinations without hours, the rest goes to the change
+const DefaultShareFactor = 0.5
+
+var (
+	// ErrNoDestinations is returned if a transaction sends coins nowhere
+	ErrNoDestinations = errors.New("no destination to send coins to")
+	// ErrInsufficientCoins is returned if the outputs of the device do not hold the coins to send
+	ErrInsufficientCoins = errors.New("not enough coins in the unspent outputs of the device")
+	// ErrInsufficientHours is returned if the outputs of the device do not hold the hours to send and burn
+	ErrInsufficientHours = errors.New("not enough coin hours in the unspent outputs of the device to send and pay the fee")
+	// ErrUnknownChangeAddress is returned if the change address is not an address of the device
+	ErrUnknownChangeAddress = errors.New("change address is not an address of the device")
+	// ErrInvalidShareFactor is returned if the share factor is not between 0 and 1
+	ErrInvalidShareFactor = errors.New("share factor must be between 0 and 1")
+)
+
+// UTXO is an unspent output, the amounts are in droplets and coin hours
+type UTXO struct {
+	Hash    string `json:"hash"`
+	Address string `json:"address"`
+	Coins   uint64 `json:"coins"`
+	Hours   uint64 `json:"hours"`
+}
+
+// ReadUTXOs reads a JSON array of unspent outputs
+func ReadUTXOs(r io.Reader) ([]UTXO, error) {
+	var utxos []UTXO
+	if err := json.NewDecoder(r).Decode(&utxos); err != nil {
+		return nil, err
+	}
+	return utxos, nil
+}
+
+// Destination is an output of the transaction. Hours is nil for a share of
+// the hours left after the fee, proportional to the coins sent.
+type Destination struct {
+	Address string  `json:"address"`
+	Coins   uint64  `json:"coins"`
+	Hours   *uint64 `json:"hours,omitempty"`
+}
+
+// Strategy tells which unspent outputs are spent first
+type Strategy string
+
+const (
+	// LargestFirst spends the outputs with the most coins first, for the fewest inputs
+	LargestFirst Strategy = "largest"
+	// SmallestFirst spends the outputs with the fewest coins first, to consolidate them
+	SmallestFirst Strategy = "smallest"
+	// MostHoursFirst spends the outputs with the most coin hours first, to send hours
+	MostHoursFirst Strategy = "hours"
+)
+
+// ParseStrategy returns the strategy named s
+func ParseStrategy(s string) (Strategy, error) {
+	switch strategy := Strategy(s); strategy {
+	case LargestFirst, SmallestFirst, MostHoursFirst:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown strategy %q, valid options are %s, %s or %s", s, LargestFirst, SmallestFirst, MostHoursFirst)
+}
+
+// less tells whether a is spent before b
+func (s Strategy) less(a, b UTXO) bool {
+	switch s {
+	case SmallestFirst:
+		if a.Coins != b.Coins {
+			return a.Coins < b.Coins
+		}
+	case MostHoursFirst:
+		if a.Hours != b.Hours {
+			return a.Hours > b.Hours
+		}
+	default:
+		if a.Coins != b.Coins {
+			return a.Coins > b.Coins
+		}
+	}
+	if a.Hours != b.Hours {
+		return a.Hours > b.Hours
+	}
+	return a.Hash < b.Hash
+}
+
+// Config tells how a transaction is built
+type Config struct {
+	// Addresses maps the addresses of the device to their index in its
+	// wallet, only the outputs they own are spent
+	Addresses map[string]uint32
+	// Strategy selects the outputs to spend, LargestFirst if empty
+	Strategy Strategy
+	// ChangeAddress receives the coins not sent, the address of the first
+	// input if empty
+	ChangeAddress string
+	// ShareFactor is the share of the hours left after the fee sent to the
+	// destinations without hours
+	ShareFactor float64
+	// Limit is the maximum number of inputs and of outputs, 0 for
+	// skywallet.DefaultTransactionSignLimit
+	Limit int
+	// Params are the burn factor and the precision of the node the
+	// transaction is sent to
+	Params skyWallet.VerifyParams
+}
+
+// NewConfig returns the default configuration to spend the outputs of addresses
+func NewConfig(addresses map[string]uint32) Config {
+	return Config{
+		Addresses:   addresses,
+		Strategy:    LargestFirst,
+		ShareFactor: DefaultShareFactor,
+		Params:      skyWallet.DefaultVerifyParams,
+	}
+}
+
+// Transaction is an unsigned transaction
+type Transaction struct {
+	Inputs  []*messages.SkycoinTransactionInput
+	Outputs []*messages.SkycoinTransactionOutput
+	// Spent are the unspent outputs of the inputs, in the same order
+	Spent []UTXO
+	// Fee is the number of coin hours burned
+	Fee uint64
+}
+
+// Values returns the coins and hours of the inputs
+func (t *Transaction) Values() []skyWallet.InputValue {
+	values := make([]skyWallet.InputValue, len(t.Spent))
+	for i, u := range t.Spent {
+		values[i] = skyWallet.InputValue{Coins: u.Coins, Hours: u.Hours}
+	}
+	return values
+}
+
+// Partial returns the transaction as a partially signed transaction without signatures
+func (t *Transaction) Partial() *psbt.Transaction {
+	inputs := make([]psbt.Input, len(t.Inputs))
+	for i, in := range t.Inputs {
+		inputs[i] = psbt.Input{
+			Hash:         in.GetHashIn(),
+			Address:      t.Spent[i].Address,
+			AddressIndex: in.GetIndex(),
+		}
+	}
+	outputs := make([]psbt.Output, len(t.Outputs))
+	for i, out := range t.Outputs {
+		outputs[i] = psbt.Output{
+			Address:      out.GetAddress(),
+			Coins:        out.GetCoin(),
+			Hours:        out.GetHour(),
+			AddressIndex: out.AddressIndex,
+		}
+	}
+	return psbt.New(inputs, outputs)
+}
+
+// Build returns the transaction sending the coins of the destinations from
+// the unspent outputs of the device. The error is one of the errors of this
+// package, skywallet.ErrInvalidVerifyParams, a *skywallet.TransactionLimitError
+// or the skywallet.ValidationErrors of the transaction built.
+func Build(utxos []UTXO, destinations []Destination, config Config) (*Transaction, error) {
+	if len(destinations) == 0 {
+		return nil, ErrNoDestinations
+	}
+	if config.ShareFactor < 0 || config.ShareFactor > 1 {
+		return nil, ErrInvalidShareFactor
+	}
+	if err := config.Params.Validate(); err != nil {
+		return nil, err
+	}
+	limit := config.Limit
+	if limit <= 0 {
+		limit = skyWallet.DefaultTransactionSignLimit
+	}
+
+	var coins, hours uint64
+	for _, d := range destinations {
+		if coins+d.Coins < coins {
+			return nil, skyWallet.ErrAmountOverflow
+		}
+		coins += d.Coins
+		if d.Hours != nil {
+			if hours+*d.Hours < hours {
+				return nil, skyWallet.ErrAmountOverflow
+			}
+			hours += *d.Hours
+		}
+	}
+
+	spent, err := selectOutputs(utxos, config, coins, hours)
+	if err != nil {
+		return nil, err
+	}
+	var inputCoins, inputHours uint64
+	for _, u := range spent {
+		inputCoins += u.Coins
+		inputHours += u.Hours
+	}
+
+	changeAddress := config.ChangeAddress
+	if changeAddress == "" {
+		changeAddress = spent[0].Address
+	}
+	changeIndex, ok := config.Addresses[changeAddress]
+	if !ok {
+		return nil, ErrUnknownChangeAddress
+	}
+	change := inputCoins - coins
+
+	// the hours left after the fee and the hours of the destinations are shared
+	shareFactor := config.ShareFactor
+	if change == 0 {
+		shareFactor = 1
+	}
+	left := inputHours - config.Params.RequiredFee(inputHours) - hours
+	shared := distributeHours(destinations, uint64(float64(left)*shareFactor))
+
+	t := &Transaction{Spent: spent}
+	for _, u := range spent {
+		t.Inputs = append(t.Inputs, &messages.SkycoinTransactionInput{
+			HashIn: proto.String(u.Hash),
+			Index:  proto.Uint32(config.Addresses[u.Address]),
+		})
+	}
+	var outputHours uint64
+	for i, d := range destinations {
+		h := shared[i]
+		if d.Hours != nil {
+			h = *d.Hours
+		}
+		outputHours += h
+		t.Outputs = append(t.Outputs, &messages.SkycoinTransactionOutput{
+			Address: proto.String(d.Address),
+			Coin:    proto.Uint64(d.Coins),
+			Hour:    proto.Uint64(h),
+		})
+	}
+	if change != 0 {
+		changeHours := inputHours - config.Params.RequiredFee(inputHours) - outputHours
+		outputHours += changeHours
+		t.Outputs = append(t.Outputs, &messages.SkycoinTransactionOutput{
+			Address:      proto.String(changeAddress),
+			Coin:         proto.Uint64(change),
+			Hour:         proto.Uint64(changeHours),
+			AddressIndex: proto.Uint32(changeIndex),
+		})
+	}
+	t.Fee = inputHours - outputHours
+
+	if len(t.Inputs) > limit || len(t.Outputs) > limit {
+		return nil, &skyWallet.TransactionLimitError{Inputs: len(t.Inputs), Outputs: len(t.Outputs), Limit: limit}
+	}
+	if err = config.Params.ValidateTransaction(t.Inputs, t.Outputs, t.Values()); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// selectOutputs returns the outputs of the device to spend, in the order of
+// the strategy, until they hold the coins and the hours to send and the fee
+func selectOutputs(utxos []UTXO, config Config, coins, hours uint64) ([]UTXO, error) {
+	var owned []UTXO
+	for _, u := range utxos {
+		if _, ok := config.Addresses[u.Address]; ok {
+			owned = append(owned, u)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return config.Strategy.less(owned[i], owned[j])
+	})
+
+	var spent []UTXO
+	var inputCoins, inputHours uint64
+	enough := func() bool {
+		// a transaction burning no hours is rejected by the nodes
+		return inputCoins >= coins && inputHours != 0 && inputHours-config.Params.RequiredFee(inputHours) >= hours
+	}
+	for _, u := range owned {
+		if enough() {
+			break
+		}
+		if inputCoins+u.Coins < inputCoins || inputHours+u.Hours < inputHours {
+			return nil, skyWallet.ErrAmountOverflow
+		}
+		spent = append(spent, u)
+		inputCoins += u.Coins
+		inputHours += u.Hours
+	}
+	if inputCoins < coins {
+		return nil, ErrInsufficientCoins
+	}
+	if !enough() {
+		return nil, ErrInsufficientHours
+	}
+	return spent, nil
+}
+
+// distributeHours shares hours between the destinations without hours,
+// proportionally to their coins, the hours left by the rounding go to the
+// first of them
+func distributeHours(destinations []Destination, hours uint64) []uint64 {
+	shared := make([]uint64, len(destinations))
+	var coins uint64
+	for _, d := range destinations {
+		if d.Hours == nil {
+			coins += d.Coins
+		}
+	}
+	if coins == 0 {
+		return shared
+	}
+
+	left := hours
+	for i, d := range destinations {
+		if d.Hours != nil {
+			continue
+		}
+		// hours * d.Coins / coins, without overflow
+		hi, lo := bits.Mul64(hours, d.Coins)
+		shared[i], _ = bits.Div64(hi, lo, coins)
+		left -= shared[i]
+	}
+	for i := 0; left != 0; i = (i + 1) % len(destinations) {
+		if destinations[i].Hours == nil {
+			shared[i]++
+			left--
+		}
+	}
+	return shared
+}
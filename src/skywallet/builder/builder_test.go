@@ -0,0 +1,212 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+)
+
+const (
+	first       = "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"
+	second      = "K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot"
+	destination = "22S8njPeKUNJBijQjNCzaasXVyf22rWv7gF"
+	foreign     = "2YZcU78BnGN62TungHSdmbLJDFYaVs3SzmT"
+)
+
+var utxos = []UTXO{
+	{Hash: "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", Address: first, Coins: 2000000, Hours: 10},
+	{Hash: "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", Address: second, Coins: 5000000, Hours: 2},
+	{Hash: "d11c62b1e0e9abf629b1f5f4699cef9fbc504b45ceedf0047ead686979498218", Address: first, Coins: 1000000, Hours: 40},
+	{Hash: "4eaa7a5ba4a1a2ef8a8a5ea2d1c1c93fed7ee1dd8a4bc30da4cbd33ff4e1fd8d", Address: foreign, Coins: 9000000, Hours: 100},
+}
+
+var addresses = map[string]uint32{first: 0, second: 3}
+
+func TestBuild(t *testing.T) {
+	tt := []struct {
+		name         string
+		destinations []Destination
+		config       func(*Config)
+		inputs       []string
+		// outputs are the address, coins and hours of each output
+		outputs [][3]interface{}
+		change  *uint32
+		fee     uint64
+	}{
+		{
+			name:         "largest first with change",
+			destinations: []Destination{{Address: destination, Coins: 3000000}},
+			inputs:       []string{"ae6fcae5"},
+			// 2 hours, 1 burned, the half of 1 left rounds to 0
+			outputs: [][3]interface{}{{destination, uint64(3000000), uint64(0)}, {second, uint64(2000000), uint64(1)}},
+			change:  proto.Uint32(3),
+			fee:     1,
+		},
+		{
+			name:         "smallest first",
+			destinations: []Destination{{Address: destination, Coins: 3000000}},
+			config:       func(c *Config) { c.Strategy = SmallestFirst },
+			inputs:       []string{"d11c62b1", "c5467f39"},
+			// 50 hours, 5 burned, without change the 45 left go to the destination
+			outputs: [][3]interface{}{{destination, uint64(3000000), uint64(45)}},
+			fee:     5,
+		},
+		{
+			name:         "most hours first with given hours and change address",
+			destinations: []Destination{{Address: destination, Coins: 500000, Hours: proto.Uint64(15)}},
+			config: func(c *Config) {
+				c.Strategy = MostHoursFirst
+				c.ChangeAddress = second
+			},
+			inputs:  []string{"d11c62b1"},
+			outputs: [][3]interface{}{{destination, uint64(500000), uint64(15)}, {second, uint64(500000), uint64(21)}},
+			change:  proto.Uint32(3),
+			fee:     4,
+		},
+		{
+			name:         "more hours than the largest output holds",
+			destinations: []Destination{{Address: destination, Coins: 1000000, Hours: proto.Uint64(20)}},
+			inputs:       []string{"ae6fcae5", "c5467f39", "d11c62b1"},
+			outputs:      [][3]interface{}{{destination, uint64(1000000), uint64(20)}, {second, uint64(7000000), uint64(26)}},
+			change:       proto.Uint32(3),
+			fee:          6,
+		},
+		{
+			name: "shared between destinations",
+			destinations: []Destination{
+				{Address: destination, Coins: 1000000},
+				{Address: foreign, Coins: 3000000},
+			},
+			config: func(c *Config) { c.Strategy = MostHoursFirst },
+			inputs: []string{"d11c62b1", "c5467f39", "ae6fcae5"},
+			// 52 hours, 6 burned, 23 shared 1:3 with the rounding to the first destination
+			outputs: [][3]interface{}{{destination, uint64(1000000), uint64(6)}, {foreign, uint64(3000000), uint64(17)}, {first, uint64(4000000), uint64(23)}},
+			change:  proto.Uint32(0),
+			fee:     6,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			config := NewConfig(addresses)
+			if tc.config != nil {
+				tc.config(&config)
+			}
+			tx, err := Build(utxos, tc.destinations, config)
+			require.NoError(t, err)
+
+			require.Len(t, tx.Inputs, len(tc.inputs))
+			for i, prefix := range tc.inputs {
+				require.True(t, strings.HasPrefix(tx.Inputs[i].GetHashIn(), prefix), "input %d", i)
+				require.Equal(t, addresses[tx.Spent[i].Address], tx.Inputs[i].GetIndex())
+			}
+			require.Len(t, tx.Outputs, len(tc.outputs))
+			for i, out := range tc.outputs {
+				require.Equal(t, out[0], tx.Outputs[i].GetAddress())
+				require.Equal(t, out[1], tx.Outputs[i].GetCoin())
+				require.Equal(t, out[2], tx.Outputs[i].GetHour())
+				if i < len(tc.destinations) {
+					require.Nil(t, tx.Outputs[i].AddressIndex)
+				}
+			}
+			if tc.change != nil {
+				require.Equal(t, *tc.change, tx.Outputs[len(tx.Outputs)-1].GetAddressIndex())
+			}
+			require.Equal(t, tc.fee, tx.Fee)
+
+			partial := tx.Partial()
+			require.Len(t, partial.Inputs, len(tx.Inputs))
+			require.Equal(t, tx.Spent[0].Address, partial.Inputs[0].Address)
+		})
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tt := []struct {
+		name         string
+		destinations []Destination
+		config       func(*Config)
+		err          error
+	}{
+		{
+			name: "no destination",
+			err:  ErrNoDestinations,
+		},
+		{
+			name:         "foreign outputs are not spent",
+			destinations: []Destination{{Address: destination, Coins: 9000000}},
+			err:          ErrInsufficientCoins,
+		},
+		{
+			name:         "hours and fee",
+			destinations: []Destination{{Address: destination, Coins: 1000000, Hours: proto.Uint64(47)}},
+			err:          ErrInsufficientHours,
+		},
+		{
+			name:         "change to a foreign address",
+			destinations: []Destination{{Address: destination, Coins: 1000000}},
+			config:       func(c *Config) { c.ChangeAddress = foreign },
+			err:          ErrUnknownChangeAddress,
+		},
+		{
+			name:         "share factor",
+			destinations: []Destination{{Address: destination, Coins: 1000000}},
+			config:       func(c *Config) { c.ShareFactor = 1.5 },
+			err:          ErrInvalidShareFactor,
+		},
+		{
+			name:         "burn factor of the node",
+			destinations: []Destination{{Address: destination, Coins: 1000000, Hours: proto.Uint64(27)}},
+			config:       func(c *Config) { c.Params.BurnFactor = 2 },
+			err:          ErrInsufficientHours,
+		},
+		{
+			name:         "invalid params",
+			destinations: []Destination{{Address: destination, Coins: 1000000}},
+			config:       func(c *Config) { c.Params = skyWallet.VerifyParams{} },
+			err:          skyWallet.ErrInvalidVerifyParams,
+		},
+		{
+			name:         "limit",
+			destinations: []Destination{{Address: destination, Coins: 7000000}},
+			config:       func(c *Config) { c.Limit = 1 },
+			err:          &skyWallet.TransactionLimitError{Inputs: 2, Outputs: 1, Limit: 1},
+		},
+		{
+			name:         "invalid destination",
+			destinations: []Destination{{Address: destination, Coins: 1000001}},
+			err: skyWallet.ValidationErrors{
+				{Field: "coin 1", Err: skyWallet.ErrDropletPrecision},
+				{Field: "coin 2", Err: skyWallet.ErrDropletPrecision},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			config := NewConfig(addresses)
+			if tc.config != nil {
+				tc.config(&config)
+			}
+			_, err := Build(utxos, tc.destinations, config)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestReadUTXOs(t *testing.T) {
+	read, err := ReadUTXOs(strings.NewReader(`[{"hash": "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59",
+		"address": "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "coins": 2000000, "hours": 10}]`))
+	require.NoError(t, err)
+	require.Equal(t, utxos[:1], read)
+
+	_, err = ParseStrategy("random")
+	require.Error(t, err)
+	strategy, err := ParseStrategy("smallest")
+	require.NoError(t, err)
+	require.Equal(t, SmallestFirst, strategy)
+}
@@ -0,0 +1,59 @@
+package skywallet
+
+import (
+	"fmt"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+)
+
+// ChangeAddressError is returned if the address of a change output is not the
+// address of the device at its AddressIndex
+type ChangeAddressError struct {
+	// Output is the position of the output in the transaction, starting from 0
+	Output        int
+	AddressIndex  uint32
+	Address       string
+	DeviceAddress string
+}
+
+func (e *ChangeAddressError) Error() string {
+	return fmt.Sprintf("output %d claims to go back to the wallet at index %d but its address %s is not the device address %s",
+		e.Output+1, e.AddressIndex, e.Address, e.DeviceAddress)
+}
+
+// VerifyChangeAddresses checks that every output with an AddressIndex goes to
+// the address generated by d at that index. The PIN and passphrase requests of
+// the device are answered by handle.
+func VerifyChangeAddresses(d Devicer, outputs []*messages.SkycoinTransactionOutput, handle RequestHandler) error {
+	for i, out := range outputs {
+		if out.AddressIndex == nil {
+			continue
+		}
+		addresses, err := RequestAddresses(d, 1, out.GetAddressIndex(), handle)
+		if err != nil {
+			return fmt.Errorf("failed to get the address of output %d: %v", i+1, err)
+		}
+		if len(addresses) != 1 || addresses[0] != out.GetAddress() {
+			e := &ChangeAddressError{
+				Output:       i,
+				AddressIndex: out.GetAddressIndex(),
+				Address:      out.GetAddress(),
+			}
+			if len(addresses) != 0 {
+				e.DeviceAddress = addresses[0]
+			}
+			return e
+		}
+	}
+	return nil
+}
+
+// SetChangeVerification makes TransactionSign check the change outputs with
+// VerifyChangeAddresses before sending the transaction to the device, a
+// transaction with a change address not belonging to the device is refused.
+// The address requests are answered by handle, a nil handle disables the check.
+func (d *Device) SetChangeVerification(handle RequestHandler) {
+	d.Lock()
+	defer d.Unlock()
+	d.changeVerification = handle
+}
@@ -0,0 +1,97 @@
+package skywallet
+
+import (
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func changeOutputs() []*messages.SkycoinTransactionOutput {
+	return []*messages.SkycoinTransactionOutput{
+		{Address: proto.String("K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot"), Coin: proto.Uint64(1000000), Hour: proto.Uint64(1)},
+		{Address: proto.String("zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"), Coin: proto.Uint64(1000000), Hour: proto.Uint64(1), AddressIndex: proto.Uint32(1)},
+	}
+}
+
+func (suite *devicerSuit) TestVerifyChangeAddresses() {
+	tt := []struct {
+		name    string
+		address string
+		err     error
+	}{
+		{
+			name:    "change address of the device",
+			address: "zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs",
+		},
+		{
+			name:    "substituted change address",
+			address: "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw",
+			err: &ChangeAddressError{
+				Output:        1,
+				AddressIndex:  1,
+				Address:       "zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs",
+				DeviceAddress: "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw",
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		// NOTE: Giving
+		device := &MockDevicer{}
+		device.On("AddressGen", uint32(1), uint32(1), false).Return(addressesMsg(tc.address), nil)
+
+		// NOTE: When
+		err := VerifyChangeAddresses(device, changeOutputs(), noRequests)
+
+		// NOTE: Assert
+		require.Equal(suite.T(), tc.err, err, tc.name)
+		mock.AssertExpectationsForObjects(suite.T(), device)
+	}
+}
+
+func (suite *devicerSuit) TestTransactionSignVerifiesChange() {
+	// NOTE: Giving
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Return(
+		addressesMsg("zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"), nil).Once()
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Success), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+	device.SetChangeVerification(noRequests)
+
+	// NOTE: When
+	msg, err := device.TransactionSign(nil, changeOutputs())
+
+	// NOTE: Assert
+	suite.Nil(err)
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 2)
+	require.Equal(suite.T(), uint16(messages.MessageType_MessageType_Success), msg.Kind)
+}
+
+func (suite *devicerSuit) TestTransactionSignRefusesForeignChange() {
+	// NOTE: Giving
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Return(
+		addressesMsg("2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"), nil)
+	device := getMockDevice(driverMock)
+	device.SetChangeVerification(noRequests)
+
+	// NOTE: When
+	_, err := device.TransactionSign(nil, changeOutputs())
+
+	// NOTE: Assert
+	_, ok := err.(*ChangeAddressError)
+	suite.True(ok)
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 1)
+
+	// NOTE: a nil handler disables the check
+	device.SetChangeVerification(nil)
+	_, err = device.TransactionSign(nil, changeOutputs())
+	suite.Nil(err)
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 2)
+}
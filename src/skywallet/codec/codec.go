@@ -0,0 +1,271 @@
+/*
+Package codec maps the skywallet message kinds to their protobuf types,
+encoding and decoding messages without spelling out their kind.
+*/
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/redact"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+const (
+	packetLen = 64
+
+	kindPrefix = "MessageType_"
+)
+
+var (
+	// ErrUnknownKind is returned decoding a message whose kind is not registered
+	ErrUnknownKind = errors.New("unknown message kind")
+	// ErrUnknownType is returned encoding a message whose type is not registered
+	ErrUnknownType = errors.New("unknown message type")
+	// ErrUnknownName is returned looking up a message name not registered
+	ErrUnknownName = errors.New("unknown message name")
+	// ErrAlreadyRegistered is returned registering a kind, name or type twice
+	ErrAlreadyRegistered = errors.New("message already registered")
+)
+
+// Registry maps message kinds to protobuf types, it is safe for concurrent use
+type Registry struct {
+	mu    sync.RWMutex
+	types map[uint16]reflect.Type
+	kinds map[reflect.Type]uint16
+	names map[uint16]string
+	named map[string]uint16
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		types: make(map[uint16]reflect.Type),
+		kinds: make(map[reflect.Type]uint16),
+		names: make(map[uint16]string),
+		named: make(map[string]uint16),
+	}
+}
+
+// NewDefaultRegistry creates a Registry holding every message defined
+// in hardware-wallet-protob
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for kind, kindName := range messages.MessageType_name {
+		name := strings.TrimPrefix(kindName, kindPrefix)
+		t := proto.MessageType(name)
+		if t == nil {
+			continue
+		}
+		pm, ok := reflect.New(t.Elem()).Interface().(proto.Message)
+		if !ok {
+			continue
+		}
+		if err := r.Register(uint16(kind), name, pm); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register maps kind and name to the type of msg. Firmware forks can
+// use it to add their own messages, a kind, name or type can only be
+// registered once.
+func (r *Registry) Register(kind uint16, name string, msg proto.Message) error {
+	t := reflect.TypeOf(msg)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.types[kind]; ok {
+		return fmt.Errorf("%v: kind %d", ErrAlreadyRegistered, kind)
+	}
+	if _, ok := r.kinds[t]; ok {
+		return fmt.Errorf("%v: type %s", ErrAlreadyRegistered, t)
+	}
+	if _, ok := r.named[name]; ok {
+		return fmt.Errorf("%v: name %s", ErrAlreadyRegistered, name)
+	}
+	r.types[kind] = t
+	r.kinds[t] = kind
+	r.names[kind] = name
+	r.named[name] = kind
+	return nil
+}
+
+// Kind returns the kind registered for the type of msg
+func (r *Registry) Kind(msg proto.Message) (uint16, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kind, ok := r.kinds[reflect.TypeOf(msg)]
+	if !ok {
+		return 0, fmt.Errorf("%v: %T", ErrUnknownType, msg)
+	}
+	return kind, nil
+}
+
+// KindByName returns the kind registered with name, i.e Features
+func (r *Registry) KindByName(name string) (uint16, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kind, ok := r.named[strings.TrimPrefix(name, kindPrefix)]
+	if !ok {
+		return 0, fmt.Errorf("%v: %s", ErrUnknownName, name)
+	}
+	return kind, nil
+}
+
+// Name returns the name registered for kind, i.e Features for MessageType_Features
+func (r *Registry) Name(kind uint16) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	name, ok := r.names[kind]
+	if !ok {
+		return fmt.Sprintf("Unknown(%d)", kind)
+	}
+	return name
+}
+
+// New returns an empty message of the type registered for kind
+func (r *Registry) New(kind uint16) (proto.Message, error) {
+	r.mu.RLock()
+	t, ok := r.types[kind]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%v: %d", ErrUnknownKind, kind)
+	}
+	return reflect.New(t.Elem()).Interface().(proto.Message), nil
+}
+
+// Sensitive tells if the messages of kind carry a secret, a field whose name
+// redact.IsSensitive reports, directly or in a nested message
+func (r *Registry) Sensitive(kind uint16) bool {
+	r.mu.RLock()
+	t, ok := r.types[kind]
+	r.mu.RUnlock()
+	return ok && hasSensitiveField(t, make(map[reflect.Type]bool))
+}
+
+// hasSensitiveField looks for a sensitive protobuf field in the message type t
+func hasSensitiveField(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i, p := range proto.GetProperties(t).Prop {
+		// fields without a name are not part of the message, i.e XXX_unrecognized
+		if p.OrigName == "" {
+			continue
+		}
+		if redact.IsSensitive(p.OrigName) || hasSensitiveField(t.Field(i).Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// Encode marshals msg into the packets to be sent to the device,
+// the message kind is inferred from its type
+func (r *Registry) Encode(msg proto.Message) ([][64]byte, error) {
+	kind, err := r.Kind(msg)
+	if err != nil {
+		return nil, err
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	// the payload may hold a secret, only the chunks are kept
+	defer redact.Wipe(data)
+	return Chunk(kind, data)
+}
+
+// Decode unmarshals msg into the type registered for its kind
+func (r *Registry) Decode(msg wire.Message) (proto.Message, error) {
+	pm, err := r.New(msg.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(msg.Data, pm); err != nil {
+		return nil, err
+	}
+	return pm, nil
+}
+
+// Chunk splits a message payload into the packets to be sent to the device
+func Chunk(kind uint16, data []byte) ([][64]byte, error) {
+	if len(data) > wire.MaxMessageSize {
+		return nil, wire.ErrMessageTooLarge
+	}
+	// allocated once so no copy of the payload is left behind when the buffer grows
+	buf := bytes.NewBuffer(make([]byte, 0, packetLen*(len(data)/(packetLen-1)+2)))
+	m := wire.Message{
+		Kind: kind,
+		Data: data,
+	}
+	if _, err := m.WriteTo(buf); err != nil {
+		return nil, err
+	}
+	framed := buf.Bytes()
+	defer redact.Wipe(framed)
+	chunks := make([][64]byte, 0, len(framed)/packetLen)
+	for buf.Len() > 0 {
+		var chunk [64]byte
+		copy(chunk[:], buf.Next(packetLen))
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// Default is the registry used by the package level functions
+var Default = NewDefaultRegistry()
+
+// Register maps kind and name to the type of msg in the Default registry
+func Register(kind uint16, name string, msg proto.Message) error {
+	return Default.Register(kind, name, msg)
+}
+
+// Kind returns the kind of msg in the Default registry
+func Kind(msg proto.Message) (uint16, error) {
+	return Default.Kind(msg)
+}
+
+// KindByName returns the kind registered with name in the Default registry
+func KindByName(name string) (uint16, error) {
+	return Default.KindByName(name)
+}
+
+// Name returns the name of kind in the Default registry
+func Name(kind uint16) string {
+	return Default.Name(kind)
+}
+
+// New returns an empty message of the type registered for kind in the Default registry
+func New(kind uint16) (proto.Message, error) {
+	return Default.New(kind)
+}
+
+// Sensitive tells if the messages of kind carry a secret, using the Default registry
+func Sensitive(kind uint16) bool {
+	return Default.Sensitive(kind)
+}
+
+// Encode marshals msg using the Default registry
+func Encode(msg proto.Message) ([][64]byte, error) {
+	return Default.Encode(msg)
+}
+
+// Decode unmarshals msg using the Default registry
+func Decode(msg wire.Message) (proto.Message, error) {
+	return Default.Decode(msg)
+}
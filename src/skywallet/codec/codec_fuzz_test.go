@@ -0,0 +1,46 @@
+//go:build go1.18
+// +build go1.18
+
+package codec
+
+import (
+	"bytes"
+	"testing"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func FuzzChunk(f *testing.F) {
+	f.Add(uint16(messages.MessageType_MessageType_Initialize), []byte{})
+	f.Add(uint16(messages.MessageType_MessageType_GetRawEntropy), []byte{0x08, 0x80, 0x08})
+	f.Add(uint16(messages.MessageType_MessageType_ApplySettings), []byte{0x0a, 0x03, 'a', 'b', 'c'})
+
+	f.Fuzz(func(t *testing.T, kind uint16, data []byte) {
+		if len(data) > wire.MaxMessageSize {
+			return
+		}
+		chunks, err := Chunk(kind, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (len(data) + 8 + 62) / 63; len(chunks) != want {
+			t.Fatalf("expected %d chunks, got %d", want, len(chunks))
+		}
+
+		msg, err := readMessage(t, chunks)
+		if wire.Validate(data) != nil {
+			if err != wire.ErrMalformedProtobuf {
+				t.Fatalf("expected ErrMalformedProtobuf, got %v", err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Kind != kind || !bytes.Equal(msg.Data, data) {
+			t.Fatalf("round trip mismatch: kind %d data %x, want kind %d data %x", msg.Kind, msg.Data, kind, data)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package codec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func readMessage(t testing.TB, chunks [][64]byte) (*wire.Message, error) {
+	var buf bytes.Buffer
+	for _, chunk := range chunks {
+		buf.Write(chunk[:])
+	}
+	return wire.ReadFrom(&buf)
+}
+
+func TestDefaultRegistryHasEveryMessage(t *testing.T) {
+	for kind, name := range messages.MessageType_name {
+		pm, err := New(uint16(kind))
+		require.NoError(t, err, name)
+		require.Equal(t, strings.TrimPrefix(name, kindPrefix), proto.MessageName(pm))
+		require.Equal(t, proto.MessageName(pm), Name(uint16(kind)))
+
+		k, err := Kind(pm)
+		require.NoError(t, err)
+		require.Equal(t, uint16(kind), k)
+
+		k, err = KindByName(name)
+		require.NoError(t, err)
+		require.Equal(t, uint16(kind), k)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	msg := &messages.ApplySettings{
+		Label:         proto.String("my label"),
+		UsePassphrase: proto.Bool(true),
+	}
+	chunks, err := Encode(msg)
+	require.NoError(t, err)
+
+	wm, err := readMessage(t, chunks)
+	require.NoError(t, err)
+	require.Equal(t, uint16(messages.MessageType_MessageType_ApplySettings), wm.Kind)
+
+	pm, err := Decode(*wm)
+	require.NoError(t, err)
+	require.Equal(t, msg.String(), pm.(*messages.ApplySettings).String())
+}
+
+func TestUnknownMessages(t *testing.T) {
+	_, err := Encode(&messages.HDNodeType{})
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), ErrUnknownType.Error()))
+
+	_, err = Decode(wire.Message{Kind: 0xffff})
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), ErrUnknownKind.Error()))
+
+	_, err = KindByName("Nope")
+	require.Error(t, err)
+	require.Equal(t, "Unknown(65535)", Name(0xffff))
+}
+
+func TestRegister(t *testing.T) {
+	r := NewRegistry()
+	// NOTE: Giving
+	// a firmware fork defining its own message
+	require.NoError(t, r.Register(1000, "ForkCoin", &messages.CoinType{}))
+
+	// NOTE: When
+	chunks, err := r.Encode(&messages.CoinType{CoinName: proto.String("fork")})
+	require.NoError(t, err)
+	wm, err := readMessage(t, chunks)
+	require.NoError(t, err)
+	pm, err := r.Decode(*wm)
+
+	// NOTE: Assert
+	require.NoError(t, err)
+	require.Equal(t, uint16(1000), wm.Kind)
+	require.Equal(t, "fork", pm.(*messages.CoinType).GetCoinName())
+	require.Equal(t, "ForkCoin", r.Name(1000))
+
+	// kinds, names and types can only be registered once
+	require.Error(t, r.Register(1000, "Other", &messages.Ping{}))
+	require.Error(t, r.Register(1001, "ForkCoin", &messages.Ping{}))
+	require.Error(t, r.Register(1001, "Other", &messages.CoinType{}))
+	require.NoError(t, r.Register(1001, "Other", &messages.Ping{}))
+}
+
+func TestSensitive(t *testing.T) {
+	for _, kind := range []messages.MessageType{
+		messages.MessageType_MessageType_SetMnemonic,
+		messages.MessageType_MessageType_LoadDevice,
+		messages.MessageType_MessageType_PinMatrixAck,
+		messages.MessageType_MessageType_PassphraseAck,
+		messages.MessageType_MessageType_WordAck,
+	} {
+		require.True(t, Sensitive(uint16(kind)), kind.String())
+	}
+	for _, kind := range []messages.MessageType{
+		messages.MessageType_MessageType_Features,
+		messages.MessageType_MessageType_ApplySettings,
+		messages.MessageType_MessageType_PinMatrixRequest,
+	} {
+		require.False(t, Sensitive(uint16(kind)), kind.String())
+	}
+	require.False(t, Sensitive(0xffff))
+}
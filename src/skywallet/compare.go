@@ -0,0 +1,186 @@
+package skywallet
+
+import (
+	"errors"
+	"fmt"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/usb"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+// addressBatchSize is the number of addresses requested at once when the
+// progress of an address scan is reported
+const addressBatchSize = 10
+
+var (
+	// ErrDeviceNotFound is returned if no device has the path or the device id looked for
+	ErrDeviceNotFound = errors.New("no device has this path or device id")
+)
+
+// RequestHandler answers the button, PIN matrix and passphrase requests of
+// a device until it sends any other message
+type RequestHandler func(d Devicer, msg wire.Message) (wire.Message, error)
+
+// DeviceComparison holds the addresses returned by two devices for the same range
+type DeviceComparison struct {
+	StartIndex uint32
+	First      []string
+	Second     []string
+	// Mismatches are the indexes of the addresses that differ
+	Mismatches []uint32
+}
+
+// Match tells if both devices returned the same addresses
+func (c *DeviceComparison) Match() bool {
+	return len(c.Mismatches) == 0 && len(c.First) == len(c.Second)
+}
+
+// OpenDevice returns the device whose path or device id is selector. The
+// devices are enumerated and their features requested to find the device id,
+// emulators are looked for on EmulatorPort unless selector is an emulator path.
+func OpenDevice(deviceType DeviceType, selector string) (*Device, error) {
+	if _, ok := usb.EmulatorPort(selector); ok && deviceType == DeviceTypeEmulator {
+		return NewDeviceAtPath(deviceType, selector)
+	}
+
+	driver, err := NewDriver(deviceType)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := driver.DevicePaths()
+	driver.Close()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if path == selector {
+			return NewDeviceAtPath(deviceType, path)
+		}
+	}
+
+	for _, path := range paths {
+		var d *Device
+		d, err = NewDeviceAtPath(deviceType, path)
+		if err != nil {
+			return nil, err
+		}
+		var id string
+		id, err = deviceID(d)
+		if err != nil {
+			log.Errorf("failed to get the features of the device at %s: %v", path, err)
+		} else if id == selector {
+			return d, nil
+		}
+		d.Close()
+	}
+	return nil, fmt.Errorf("%v: %s", ErrDeviceNotFound, selector)
+}
+
+// deviceID returns the device id of d from its features
+func deviceID(d Devicer) (string, error) {
+	msg, err := d.GetFeatures()
+	if err != nil {
+		return "", err
+	}
+	features, err := DecodeFeatures(msg)
+	if err != nil {
+		return "", err
+	}
+	return features.GetDeviceId(), nil
+}
+
+// CompareDevices requests the same range of addresses from both devices and
+// compares them, the seeds never leave the devices. The PIN and passphrase
+// requests of each device are answered by handle.
+func CompareDevices(first, second Devicer, addressN, startIndex uint32, handle RequestHandler) (*DeviceComparison, error) {
+	return CompareDevicesWithProgress(first, second, addressN, startIndex, handle, NopProgressReporter{})
+}
+
+// CompareDevicesWithProgress is CompareDevices reporting the number of
+// addresses received from both devices, out of 2*addressN
+func CompareDevicesWithProgress(first, second Devicer, addressN, startIndex uint32, handle RequestHandler, reporter ProgressReporter) (*DeviceComparison, error) {
+	reporter.Start(2 * int(addressN))
+	defer reporter.Done()
+	firstAddresses, err := requestAddresses(first, addressN, startIndex, handle, reporter.Update)
+	if err != nil {
+		return nil, fmt.Errorf("first device: %v", err)
+	}
+	secondAddresses, err := requestAddresses(second, addressN, startIndex, handle, func(n int) {
+		reporter.Update(int(addressN) + n)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("second device: %v", err)
+	}
+
+	c := &DeviceComparison{
+		StartIndex: startIndex,
+		First:      firstAddresses,
+		Second:     secondAddresses,
+	}
+	for i := range firstAddresses {
+		if i >= len(secondAddresses) || firstAddresses[i] != secondAddresses[i] {
+			c.Mismatches = append(c.Mismatches, startIndex+uint32(i))
+		}
+	}
+	return c, nil
+}
+
+// RequestAddressesWithProgress returns the addressN addresses generated by d
+// from startIndex, requested in batches reporting the number of addresses received
+func RequestAddressesWithProgress(d Devicer, addressN, startIndex uint32, handle RequestHandler, reporter ProgressReporter) ([]string, error) {
+	reporter.Start(int(addressN))
+	defer reporter.Done()
+	return requestAddresses(d, addressN, startIndex, handle, reporter.Update)
+}
+
+// requestAddresses requests the addresses in batches of addressBatchSize,
+// update is called with the number of addresses received after every batch.
+// It stops at the first batch the device answers with fewer addresses.
+func requestAddresses(d Devicer, addressN, startIndex uint32, handle RequestHandler, update func(int)) ([]string, error) {
+	var addresses []string
+	for uint32(len(addresses)) < addressN {
+		n := addressN - uint32(len(addresses))
+		if n > addressBatchSize {
+			n = addressBatchSize
+		}
+		batch, err := RequestAddresses(d, n, startIndex+uint32(len(addresses)), handle)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, batch...)
+		update(len(addresses))
+		if uint32(len(batch)) < n {
+			break
+		}
+	}
+	return addresses, nil
+}
+
+// RequestAddresses returns the addressN addresses generated by d from startIndex,
+// handle answers the requests of the device
+func RequestAddresses(d Devicer, addressN, startIndex uint32, handle RequestHandler) ([]string, error) {
+	msg, err := d.AddressGen(addressN, startIndex, false)
+	if err != nil {
+		return nil, err
+	}
+	msg, err = handle(d, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch msg.Kind {
+	case uint16(messages.MessageType_MessageType_ResponseSkycoinAddress):
+		return DecodeResponseSkycoinAddress(msg)
+	case uint16(messages.MessageType_MessageType_Failure):
+		var failMsg string
+		failMsg, err = DecodeFailMsg(msg)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed with message: %s", failMsg)
+	default:
+		return nil, fmt.Errorf("received unexpected message type: %s", messages.MessageType(msg.Kind))
+	}
+}
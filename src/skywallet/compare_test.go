@@ -0,0 +1,206 @@
+package skywallet
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func addressesMsg(addresses ...string) wire.Message {
+	data, err := proto.Marshal(&messages.ResponseSkycoinAddress{Addresses: addresses})
+	if err != nil {
+		panic(err)
+	}
+	return wire.Message{Kind: uint16(messages.MessageType_MessageType_ResponseSkycoinAddress), Data: data}
+}
+
+func noRequests(d Devicer, msg wire.Message) (wire.Message, error) {
+	return msg, nil
+}
+
+func (suite *devicerSuit) TestCompareDevices() {
+	tt := []struct {
+		name       string
+		first      []string
+		second     []string
+		mismatches []uint32
+	}{
+		{
+			name:   "same seed",
+			first:  []string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"},
+			second: []string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"},
+		},
+		{
+			name:       "different seeds",
+			first:      []string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"},
+			second:     []string{"28L2fexvThTVz6e2dWUV4pSuCP8SAnCUVku", "2NckPkQRQFa5E7HtqDkZmV1TH4HCzR2N5J6"},
+			mismatches: []uint32{5, 6},
+		},
+		{
+			name:       "missing address",
+			first:      []string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"},
+			second:     []string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"},
+			mismatches: []uint32{6},
+		},
+	}
+
+	for _, tc := range tt {
+		// NOTE: Giving
+		first := &MockDevicer{}
+		first.On("AddressGen", uint32(2), uint32(5), false).Return(addressesMsg(tc.first...), nil)
+		second := &MockDevicer{}
+		second.On("AddressGen", uint32(2), uint32(5), false).Return(addressesMsg(tc.second...), nil)
+
+		// NOTE: When
+		c, err := CompareDevices(first, second, 2, 5, noRequests)
+
+		// NOTE: Assert
+		suite.Nil(err)
+		mock.AssertExpectationsForObjects(suite.T(), first, second)
+		require.Equal(suite.T(), tc.mismatches, c.Mismatches, tc.name)
+		require.Equal(suite.T(), len(tc.mismatches) == 0, c.Match(), tc.name)
+	}
+}
+
+func (suite *devicerSuit) TestCompareDevicesAnswersRequests() {
+	// NOTE: Giving
+	pinRequest := wire.Message{Kind: uint16(messages.MessageType_MessageType_PinMatrixRequest)}
+	first := &MockDevicer{}
+	first.On("AddressGen", uint32(1), uint32(0), false).Return(pinRequest, nil)
+	second := &MockDevicer{}
+	second.On("AddressGen", uint32(1), uint32(0), false).Return(pinRequest, nil)
+	var asked []Devicer
+	handle := func(d Devicer, msg wire.Message) (wire.Message, error) {
+		asked = append(asked, d)
+		return addressesMsg("2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"), nil
+	}
+
+	// NOTE: When
+	c, err := CompareDevices(first, second, 1, 0, handle)
+
+	// NOTE: Assert
+	suite.Nil(err)
+	suite.True(c.Match())
+	require.Equal(suite.T(), []Devicer{first, second}, asked)
+}
+
+func (suite *devicerSuit) TestCompareDevicesFailure() {
+	// NOTE: Giving
+	data, err := proto.Marshal(&messages.Failure{Message: proto.String("Mnemonic not set")})
+	suite.Nil(err)
+	first := &MockDevicer{}
+	first.On("AddressGen", uint32(1), uint32(0), false).Return(addressesMsg("2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"), nil)
+	second := &MockDevicer{}
+	second.On("AddressGen", uint32(1), uint32(0), false).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Failure), Data: data}, nil)
+
+	// NOTE: When
+	_, err = CompareDevices(first, second, 1, 0, noRequests)
+
+	// NOTE: Assert
+	suite.EqualError(err, "second device: failed with message: Mnemonic not set")
+}
+
+func (suite *devicerSuit) TestRequestAddressesWithProgress() {
+	// NOTE: Giving
+	batch := func(start, n int) wire.Message {
+		addresses := make([]string, n)
+		for i := range addresses {
+			addresses[i] = fmt.Sprintf("address%d", start+i)
+		}
+		return addressesMsg(addresses...)
+	}
+	device := &MockDevicer{}
+	device.On("AddressGen", uint32(10), uint32(3), false).Return(batch(3, 10), nil)
+	device.On("AddressGen", uint32(10), uint32(13), false).Return(batch(13, 10), nil)
+	device.On("AddressGen", uint32(5), uint32(23), false).Return(batch(23, 5), nil)
+	events := make(chan ProgressEvent, 16)
+
+	// NOTE: When
+	addresses, err := RequestAddressesWithProgress(device, 25, 3, noRequests, NewChanProgressReporter(events))
+	close(events)
+
+	// NOTE: Assert
+	suite.Nil(err)
+	mock.AssertExpectationsForObjects(suite.T(), device)
+	suite.Len(addresses, 25)
+	suite.Equal("address3", addresses[0])
+	suite.Equal("address27", addresses[24])
+	var got []ProgressEvent
+	for e := range events {
+		got = append(got, e)
+	}
+	suite.Equal([]ProgressEvent{
+		{Type: ProgressStart, Total: 25},
+		{Type: ProgressUpdate, Current: 10, Total: 25},
+		{Type: ProgressUpdate, Current: 20, Total: 25},
+		{Type: ProgressUpdate, Current: 25, Total: 25},
+		{Type: ProgressDone, Current: 25, Total: 25},
+	}, got)
+}
+
+func (suite *devicerSuit) TestCompareDevicesWithProgress() {
+	// NOTE: Giving
+	first := &MockDevicer{}
+	first.On("AddressGen", uint32(2), uint32(0), false).Return(addressesMsg("2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"), nil)
+	second := &MockDevicer{}
+	second.On("AddressGen", uint32(2), uint32(0), false).Return(addressesMsg("2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"), nil)
+	events := make(chan ProgressEvent, 16)
+
+	// NOTE: When
+	c, err := CompareDevicesWithProgress(first, second, 2, 0, noRequests, NewChanProgressReporter(events))
+	close(events)
+
+	// NOTE: Assert
+	suite.Nil(err)
+	suite.True(c.Match())
+	var got []ProgressEvent
+	for e := range events {
+		got = append(got, e)
+	}
+	suite.Equal([]ProgressEvent{
+		{Type: ProgressStart, Total: 4},
+		{Type: ProgressUpdate, Current: 2, Total: 4},
+		{Type: ProgressUpdate, Current: 4, Total: 4},
+		{Type: ProgressDone, Current: 4, Total: 4},
+	}, got)
+}
+
+func (suite *devicerSuit) TestCompareDevicesWithProgressFailure() {
+	// NOTE: Giving
+	first := &MockDevicer{}
+	first.On("AddressGen", uint32(2), uint32(0), false).Return(wire.Message{}, fmt.Errorf("device disconnected"))
+	events := make(chan ProgressEvent, 16)
+
+	// NOTE: When
+	_, err := CompareDevicesWithProgress(first, &MockDevicer{}, 2, 0, noRequests, NewChanProgressReporter(events))
+	close(events)
+
+	// NOTE: Assert
+	suite.EqualError(err, "first device: device disconnected")
+	var got []ProgressEvent
+	for e := range events {
+		got = append(got, e)
+	}
+	suite.Equal([]ProgressEvent{
+		{Type: ProgressStart, Total: 4},
+		{Type: ProgressDone, Current: 4, Total: 4},
+	}, got)
+}
+
+func (suite *devicerSuit) TestNewDeviceAtPath() {
+	device, err := NewDeviceAtPath(DeviceTypeEmulator, "emulator21325")
+	suite.Nil(err)
+	defer device.Close()
+	paths, err := device.Driver.(*Driver).DevicePaths()
+	suite.Nil(err)
+	suite.Equal([]string{"emulator21325"}, paths)
+
+	_, err = NewDeviceAtPath(DeviceTypeEmulator, "21325")
+	suite.NotNil(err)
+}
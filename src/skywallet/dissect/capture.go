@@ -0,0 +1,242 @@
+package dissect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/usb"
+)
+
+const (
+	packetLen = 64
+)
+
+// Format is the capture file format
+type Format string
+
+const (
+	// FormatAuto detect the format from the capture content
+	FormatAuto Format = "auto"
+	// FormatTrace trace file recorded by usb.TraceRecorder
+	FormatTrace Format = "trace"
+	// FormatHex hex dump, optionally prefixed by the packet direction
+	FormatHex Format = "hex"
+	// FormatUsbmon linux usbmon text log
+	FormatUsbmon Format = "usbmon"
+)
+
+var (
+	// ErrUnknownFormat is returned if the capture format is not supported
+	ErrUnknownFormat = errors.New("capture format must be auto, trace, hex or usbmon")
+)
+
+// ReadCapture parses the packets of a capture in the given format
+func ReadCapture(r io.Reader, format Format) ([]usb.TracePacket, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatAuto {
+		format = DetectFormat(data)
+	}
+
+	switch format {
+	case FormatTrace:
+		return usb.ReadTrace(bytes.NewReader(data))
+	case FormatHex:
+		return ParseHexDump(bytes.NewReader(data))
+	case FormatUsbmon:
+		return ParseUsbmon(bytes.NewReader(data))
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// DetectFormat guess the capture format from the first non empty line
+func DetectFormat(data []byte) Format {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "{") {
+			return FormatTrace
+		}
+		if _, ok := parseUsbmonAddress(strings.Fields(line)); ok {
+			return FormatUsbmon
+		}
+		return FormatHex
+	}
+	return FormatHex
+}
+
+// ParseHexDump parses a hex dump of the device traffic.
+// Lines can be prefixed by the direction of the data, ">" or "out" for
+// host to device and "<" or "in" for device to host, lines without prefix
+// keep the direction of the previous one. Offsets ending with ':' are ignored
+// as well as the ASCII column of xxd like dumps.
+// The bytes of consecutive lines in the same direction are split into 64 bytes packets.
+func ParseHexDump(r io.Reader) ([]usb.TracePacket, error) {
+	var packets []usb.TracePacket
+	var direction usb.TraceDirection
+	var pending []byte
+
+	flush := func() {
+		for len(pending) > 0 {
+			n := packetLen
+			if len(pending) < n {
+				n = len(pending)
+			}
+			packets = append(packets, usb.TracePacket{
+				Direction: direction,
+				Data:      append(usb.TraceData(nil), pending[:n]...),
+			})
+			pending = pending[n:]
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if dir, ok := parseDirection(fields[0]); ok {
+			if dir != direction {
+				flush()
+				direction = dir
+			}
+			fields = fields[1:]
+		}
+
+		for i, f := range fields {
+			if i == 0 && strings.HasSuffix(f, ":") {
+				// offset column
+				continue
+			}
+			b, err := hex.DecodeString(strings.Replace(f, ":", "", -1))
+			if err != nil {
+				if len(pending) == 0 && i == 0 {
+					return nil, fmt.Errorf("hex dump line %d: %v", line, err)
+				}
+				// ASCII column
+				break
+			}
+			pending = append(pending, b...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return packets, nil
+}
+
+func parseDirection(s string) (usb.TraceDirection, bool) {
+	switch strings.ToLower(s) {
+	case ">", "out":
+		return usb.TraceOut, true
+	case "<", "in":
+		return usb.TraceIn, true
+	default:
+		return "", false
+	}
+}
+
+// ParseUsbmon parses a linux usbmon text log as produced by
+// `cat /sys/kernel/debug/usb/usbmon/<bus>u`.
+// Host to device data is taken from submission events and device to host
+// data from callback events. Notice usbmon truncates the data of long
+// transfers, the packets exchanged with the wallet are 64 bytes long.
+func ParseUsbmon(r io.Reader) ([]usb.TracePacket, error) {
+	var packets []usb.TracePacket
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		direction, ok := parseUsbmonAddress(fields)
+		if !ok {
+			return nil, fmt.Errorf("usbmon line %d: malformed event", line)
+		}
+
+		event := fields[2]
+		if (direction == usb.TraceOut && event != "S") || (direction == usb.TraceIn && event != "C") {
+			continue
+		}
+
+		// data words follow the "=" tag
+		var data []byte
+		for i, f := range fields {
+			if f != "=" {
+				continue
+			}
+			for _, word := range fields[i+1:] {
+				b, err := hex.DecodeString(word)
+				if err != nil {
+					return nil, fmt.Errorf("usbmon line %d: %v", line, err)
+				}
+				data = append(data, b...)
+			}
+			break
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		var ts time.Time
+		if us, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			ts = time.Unix(0, us*int64(time.Microsecond)).UTC()
+		}
+		packets = append(packets, usb.TracePacket{
+			Time:      ts,
+			Direction: direction,
+			Data:      data,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return packets, nil
+}
+
+// parseUsbmonAddress returns the transfer direction of an usbmon event,
+// the event fields are: URB tag, timestamp, event type, address ...
+func parseUsbmonAddress(fields []string) (usb.TraceDirection, bool) {
+	if len(fields) < 4 {
+		return "", false
+	}
+	switch fields[2] {
+	case "S", "C", "E":
+	default:
+		return "", false
+	}
+	address := fields[3]
+	if len(address) < 3 || address[2] != ':' || !strings.ContainsRune("BICZ", rune(address[0])) {
+		return "", false
+	}
+	switch address[1] {
+	case 'o':
+		return usb.TraceOut, true
+	case 'i':
+		return usb.TraceIn, true
+	default:
+		return "", false
+	}
+}
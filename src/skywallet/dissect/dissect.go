@@ -0,0 +1,175 @@
+/*
+Package dissect decodes captures of the traffic exchanged with a skywallet
+into human readable protobuf messages.
+*/
+package dissect
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/codec"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/redact"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/usb"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+const (
+	headerLen = 9
+
+	// RedactedValue replaces the value of sensitive fields
+	RedactedValue = redact.Value
+)
+
+// ErrBlanked is reported for the secret messages whose payload was blanked
+// when the trace was recorded
+var ErrBlanked = errors.New("payload blanked when recorded")
+
+// Frame is a message reassembled from the packets of a capture
+type Frame struct {
+	Time      time.Time
+	Direction usb.TraceDirection
+	Message   wire.Message
+	// Err is set if the message could not be reassembled
+	Err error
+}
+
+// Reassemble groups the packets of each direction into messages.
+// Packets not belonging to a message are skipped, an incomplete message
+// is returned with Err set.
+func Reassemble(packets []usb.TracePacket) []Frame {
+	type pending struct {
+		frame Frame
+		size  uint32
+	}
+	var frames []Frame
+	// index in frames of the message being reassembled for each direction
+	inProgress := map[usb.TraceDirection]*pending{}
+	order := map[usb.TraceDirection]int{}
+
+	for _, p := range packets {
+		data := []byte(p.Data)
+		if len(data) == 0 || data[0] != '?' {
+			continue
+		}
+
+		cur := inProgress[p.Direction]
+		if len(data) >= headerLen && data[1] == '#' && data[2] == '#' {
+			if cur != nil {
+				frames[order[p.Direction]].Err = wire.ErrMalformedMessage
+			}
+			size := binary.BigEndian.Uint32(data[5:])
+			cur = &pending{
+				frame: Frame{
+					Time:      p.Time,
+					Direction: p.Direction,
+					Message: wire.Message{
+						Kind: binary.BigEndian.Uint16(data[3:]),
+					},
+				},
+				size: size,
+			}
+			data = data[headerLen:]
+			frames = append(frames, cur.frame)
+			order[p.Direction] = len(frames) - 1
+			inProgress[p.Direction] = cur
+		} else {
+			if cur == nil {
+				continue
+			}
+			data = data[1:]
+		}
+
+		f := &frames[order[p.Direction]]
+		missing := int(cur.size) - len(f.Message.Data)
+		if missing > len(data) {
+			missing = len(data)
+		}
+		f.Message.Data = append(f.Message.Data, data[:missing]...)
+		if uint32(len(f.Message.Data)) == cur.size {
+			delete(inProgress, p.Direction)
+		}
+	}
+
+	for dir := range inProgress {
+		frames[order[dir]].Err = fmt.Errorf("truncated message: %v", wire.ErrMalformedMessage)
+	}
+	return frames
+}
+
+// Redact returns the JSON representation of pm with the sensitive fields masked
+func Redact(pm proto.Message) (map[string]interface{}, error) {
+	b, err := json.Marshal(pm)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	redact.Map(fields)
+	return fields, nil
+}
+
+// Dissection is the human readable form of a Frame
+type Dissection struct {
+	Time      *time.Time         `json:"time,omitempty"`
+	Direction usb.TraceDirection `json:"direction,omitempty"`
+	Kind      string             `json:"kind"`
+	Size      int                `json:"size"`
+	Message   interface{}        `json:"message,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// Dissect decodes the frame, sensitive fields are masked unless showSecrets is set
+func Dissect(f Frame, showSecrets bool) Dissection {
+	d := Dissection{
+		Direction: f.Direction,
+		Kind:      codec.Name(f.Message.Kind),
+		Size:      len(f.Message.Data),
+	}
+	if !f.Time.IsZero() {
+		t := f.Time
+		d.Time = &t
+	}
+	if f.Err != nil {
+		d.Error = f.Err.Error()
+		return d
+	}
+
+	if codec.Sensitive(f.Message.Kind) && blanked(f.Message.Data) {
+		d.Error = ErrBlanked.Error()
+		return d
+	}
+	pm, err := codec.Decode(f.Message)
+	if err != nil {
+		d.Error = err.Error()
+		return d
+	}
+	if showSecrets {
+		d.Message = pm
+		return d
+	}
+	fields, err := Redact(pm)
+	if err != nil {
+		d.Error = err.Error()
+		return d
+	}
+	d.Message = fields
+	return d
+}
+
+// blanked tells if the payload is made of zeros only
+func blanked(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return len(data) > 0
+}
@@ -0,0 +1,142 @@
+package dissect
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/codec"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/usb"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func packetsHex(t *testing.T, kind messages.MessageType, pm proto.Message) []string {
+	data, err := proto.Marshal(pm)
+	require.NoError(t, err)
+	msg := wire.Message{Kind: uint16(kind), Data: data}
+	var buf bytes.Buffer
+	_, err = msg.WriteTo(&buf)
+	require.NoError(t, err)
+
+	var lines []string
+	b := buf.Bytes()
+	for i := 0; i < len(b); i += 64 {
+		lines = append(lines, hex.EncodeToString(b[i:i+64]))
+	}
+	return lines
+}
+
+func TestDissectHexDump(t *testing.T) {
+	mnemonic := "cloud flower upset remain green metal below cup stem infant art thank"
+	out := packetsHex(t, messages.MessageType_MessageType_SetMnemonic, &messages.SetMnemonic{Mnemonic: proto.String(mnemonic)})
+	in := packetsHex(t, messages.MessageType_MessageType_Success, &messages.Success{Message: proto.String("Mnemonic set")})
+	require.Len(t, out, 2)
+
+	dump := fmt.Sprintf("> %s\n%s\n< %s\n", out[0], out[1], in[0])
+	packets, err := ReadCapture(strings.NewReader(dump), FormatAuto)
+	require.NoError(t, err)
+	require.Len(t, packets, 3)
+
+	frames := Reassemble(packets)
+	require.Len(t, frames, 2)
+
+	d := Dissect(frames[0], false)
+	require.Empty(t, d.Error)
+	require.Equal(t, usb.TraceOut, d.Direction)
+	require.Equal(t, "SetMnemonic", d.Kind)
+	require.Equal(t, map[string]interface{}{"mnemonic": RedactedValue}, d.Message)
+
+	d = Dissect(frames[0], true)
+	require.Equal(t, mnemonic, d.Message.(*messages.SetMnemonic).GetMnemonic())
+
+	d = Dissect(frames[1], false)
+	require.Equal(t, usb.TraceIn, d.Direction)
+	require.Equal(t, "Success", d.Kind)
+	require.Equal(t, map[string]interface{}{"message": "Mnemonic set"}, d.Message)
+}
+
+func TestDissectUsbmon(t *testing.T) {
+	out := packetsHex(t, messages.MessageType_MessageType_PinMatrixAck, &messages.PinMatrixAck{Pin: proto.String("1234")})
+	words := func(s string) string {
+		var w []string
+		for i := 0; i < len(s); i += 8 {
+			w = append(w, s[i:i+8])
+		}
+		return strings.Join(w, " ")
+	}
+	log := fmt.Sprintf(`ffff8801a2b3c400 3575914555 S Io:1:002:1 -115:1 64 = %s
+ffff8801a2b3c400 3575914600 C Io:1:002:1 0:1 64 >
+ffff8801a2b3c500 3575914700 S Ii:1:002:1 -115:1 64 <
+`, words(out[0]))
+	require.Equal(t, FormatUsbmon, DetectFormat([]byte(log)))
+
+	packets, err := ReadCapture(strings.NewReader(log), FormatAuto)
+	require.NoError(t, err)
+	require.Len(t, packets, 1)
+
+	frames := Reassemble(packets)
+	require.Len(t, frames, 1)
+	d := Dissect(frames[0], false)
+	require.Equal(t, "PinMatrixAck", d.Kind)
+	require.Equal(t, map[string]interface{}{"pin": RedactedValue}, d.Message)
+}
+
+func TestReassembleTruncated(t *testing.T) {
+	out := packetsHex(t, messages.MessageType_MessageType_SetMnemonic, &messages.SetMnemonic{Mnemonic: proto.String(strings.Repeat("word ", 20))})
+	packets, err := ParseHexDump(strings.NewReader(out[0]))
+	require.NoError(t, err)
+
+	frames := Reassemble(packets)
+	require.Len(t, frames, 1)
+	require.Error(t, frames[0].Err)
+	require.NotEmpty(t, Dissect(frames[0], false).Error)
+}
+
+func TestDissectRedactedTrace(t *testing.T) {
+	mnemonic := "cloud flower upset remain green metal below cup stem infant art thank"
+	out := packetsHex(t, messages.MessageType_MessageType_SetMnemonic, &messages.SetMnemonic{Mnemonic: proto.String(mnemonic)})
+	in := packetsHex(t, messages.MessageType_MessageType_Success, &messages.Success{Message: proto.String("Mnemonic set")})
+
+	var session []usb.TracePacket
+	for _, h := range out {
+		b, err := hex.DecodeString(h)
+		require.NoError(t, err)
+		session = append(session, usb.TracePacket{Direction: usb.TraceOut, Data: b})
+	}
+	b, err := hex.DecodeString(in[0])
+	require.NoError(t, err)
+	session = append(session, usb.TracePacket{Direction: usb.TraceIn, Data: b})
+
+	// record the session with the secrets blanked
+	var trace bytes.Buffer
+	tw := usb.NewTraceWriter(&trace)
+	tw.RedactKinds(codec.Sensitive)
+	recorder := usb.NewTraceRecorder(usb.NewTraceReplayer(session, true), tw)
+	for _, p := range session[:len(out)] {
+		_, err = recorder.Write(p.Data)
+		require.NoError(t, err)
+	}
+	_, err = recorder.Read(make([]byte, 64))
+	require.NoError(t, err)
+	require.NotContains(t, trace.String(), hex.EncodeToString([]byte("cloud flower")))
+
+	packets, err := ReadCapture(&trace, FormatTrace)
+	require.NoError(t, err)
+	frames := Reassemble(packets)
+	require.Len(t, frames, 2)
+
+	d := Dissect(frames[0], true)
+	require.Equal(t, "SetMnemonic", d.Kind)
+	require.Equal(t, ErrBlanked.Error(), d.Error)
+	require.Nil(t, d.Message)
+
+	d = Dissect(frames[1], false)
+	require.Empty(t, d.Error)
+	require.Equal(t, map[string]interface{}{"message": "Mnemonic set"}, d.Message)
+}
@@ -0,0 +1,170 @@
+package skywallet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+const (
+	// maxEntropyRequestSize is the biggest amount of bytes requested to the device
+	// in a single GetRawEntropy / GetMixedEntropy message
+	maxEntropyRequestSize = 1024 * 1024
+)
+
+// EntropyType is the kind of entropy to be read from the device
+type EntropyType int32
+
+const (
+	// EntropyTypeRaw entropy generated by the device random buffer function
+	EntropyTypeRaw EntropyType = iota
+	// EntropyTypeMixed salted entropy mixed by the device from several sources
+	EntropyTypeMixed
+)
+
+func (et EntropyType) String() string {
+	switch et {
+	case EntropyTypeRaw:
+		return "raw"
+	case EntropyTypeMixed:
+		return "mixed"
+	default:
+		return "invalid"
+	}
+}
+
+var (
+	// ErrInvalidEntropyType is returned if entropy type is neither raw nor mixed
+	ErrInvalidEntropyType = errors.New("entropy type must be raw or mixed")
+)
+
+// entropyReader pulls entropy chunks from the device on demand
+type entropyReader struct {
+	device     *Device
+	msgBuilder func(entropyBytes uint32) ([][64]byte, error)
+	buf        []byte
+}
+
+// EntropyReader returns a reader streaming entropy from the device.
+// Entropy is requested to the device only when the internal buffer
+// has been consumed, there are no filesystem side effects.
+func (d *Device) EntropyReader(entropyType EntropyType) (io.Reader, error) {
+	r := &entropyReader{device: d}
+	switch entropyType {
+	case EntropyTypeRaw:
+		r.msgBuilder = MessageDeviceGetRawEntropy
+	case EntropyTypeMixed:
+		r.msgBuilder = MessageDeviceGetMixedEntropy
+	default:
+		return nil, ErrInvalidEntropyType
+	}
+	return r, nil
+}
+
+// SaveDeviceEntropyInFile Ask the device to generate entropy and save it in a file
+// if `outFile` is the "-" string, the output file is considered stdout.
+// The progress of the download is reported to the reporter set with SetProgressReporter.
+//
+// Deprecated: use EntropyReader, which leaves the destination and the encoding
+// of the entropy to the caller.
+func (d *Device) SaveDeviceEntropyInFile(outFile string, entropyBytes uint32, getEntropyMsgBuilder func(entropyBytes uint32) ([][64]byte, error)) error {
+	var out io.Writer = os.Stdout
+	if outFile != "-" {
+		file, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	r := NewProgressReader(&entropyReader{device: d, msgBuilder: getEntropyMsgBuilder}, int(entropyBytes), d.progress)
+	_, err := io.CopyN(out, r, int64(entropyBytes))
+	return err
+}
+
+// Read implements io.Reader
+func (r *entropyReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if len(r.buf) == 0 {
+		size := len(p)
+		if size > maxEntropyRequestSize {
+			size = maxEntropyRequestSize
+		}
+		entropy, err := r.device.getEntropy(uint32(size), r.msgBuilder)
+		if err != nil {
+			return 0, err
+		}
+		if len(entropy) == 0 {
+			return 0, io.ErrNoProgress
+		}
+		r.buf = entropy
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// getEntropy ask the device for entropyBytes bytes of entropy,
+// the device could answer with less bytes than requested
+func (d *Device) getEntropy(entropyBytes uint32, getEntropyMsgBuilder func(entropyBytes uint32) ([][64]byte, error)) ([]byte, error) {
+	if err := d.Connect(); err != nil {
+		return nil, err
+	}
+	defer d.Disconnect()
+
+	chunks, err := getEntropyMsgBuilder(entropyBytes)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := d.sendToDevice(chunks)
+	if err != nil {
+		return nil, err
+	}
+
+	for msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
+		// Send ButtonAck
+		chunks, err := MessageButtonAck()
+		if err != nil {
+			return nil, err
+		}
+		if err = d.Driver.SendToDeviceNoAnswer(d.dev, chunks); err != nil {
+			return nil, err
+		}
+		// simulate button press
+		if d.simulateButtonPress {
+			if err := d.SimulateButtonPress(); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := wire.ReadFrom(d.dev)
+		if err != nil {
+			return nil, err
+		}
+		msg = *resp
+	}
+
+	switch msg.Kind {
+	case uint16(messages.MessageType_MessageType_Entropy):
+		entropy, err := DecodeResponseEntropyMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		return entropy.GetEntropy(), nil
+	case uint16(messages.MessageType_MessageType_Failure):
+		msgStr, err := DecodeFailMsg(msg)
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New(msgStr)
+	default:
+		return nil, fmt.Errorf("received unexpected message type: %s", messages.MessageType(msg.Kind))
+	}
+}
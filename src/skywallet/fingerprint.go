@@ -0,0 +1,115 @@
+package skywallet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+)
+
+const (
+	// fingerprintLen is the number of bytes of the address hash shown to the user
+	fingerprintLen = 4
+)
+
+// FingerprintAddress returns the fingerprint of the wallet owning address,
+// the first address of a wallet identifies it without revealing the address itself
+func FingerprintAddress(address string) string {
+	h := sha256.Sum256([]byte(address))
+	fp := hex.EncodeToString(h[:fingerprintLen])
+	return fp[:fingerprintLen] + "-" + fp[fingerprintLen:]
+}
+
+// PassphraseFingerprint returns the fingerprint of the wallet opened by the
+// cached passphrase, a typo in the passphrase gives an unknown fingerprint
+func (d *Device) PassphraseFingerprint() (string, error) {
+	msg, err := d.AddressGen(1, 0, false)
+	if err != nil {
+		return "", err
+	}
+
+	switch msg.Kind {
+	case uint16(messages.MessageType_MessageType_ResponseSkycoinAddress):
+		addresses, err := DecodeResponseSkycoinAddress(msg)
+		if err != nil {
+			return "", err
+		}
+		if len(addresses) == 0 {
+			return "", fmt.Errorf("device returned no address")
+		}
+		return FingerprintAddress(addresses[0]), nil
+	case uint16(messages.MessageType_MessageType_Failure):
+		failMsg, err := DecodeFailMsg(msg)
+		if err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("failed with message: %s", failMsg)
+	default:
+		// the passphrase or the PIN are not cached, give up
+		if _, err := d.Cancel(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("received unexpected message type: %s", messages.MessageType(msg.Kind))
+	}
+}
+
+// FingerprintBook maps passphrase fingerprints to nicknames chosen by the user
+type FingerprintBook struct {
+	sync.RWMutex
+	nicknames map[string]string
+}
+
+// NewFingerprintBook creates an empty FingerprintBook
+func NewFingerprintBook() *FingerprintBook {
+	return &FingerprintBook{
+		nicknames: make(map[string]string),
+	}
+}
+
+// LoadFingerprintBook reads a FingerprintBook saved in a JSON file,
+// a missing file gives an empty book
+func LoadFingerprintBook(path string) (*FingerprintBook, error) {
+	b := NewFingerprintBook()
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &b.nicknames); err != nil {
+		return nil, fmt.Errorf("invalid fingerprint book %s: %v", path, err)
+	}
+	return b, nil
+}
+
+// Save writes the book to a JSON file
+func (b *FingerprintBook) Save(path string) error {
+	b.RLock()
+	data, err := json.MarshalIndent(b.nicknames, "", "    ")
+	b.RUnlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Nickname returns the nickname of a fingerprint, false if it is unknown
+func (b *FingerprintBook) Nickname(fingerprint string) (string, bool) {
+	b.RLock()
+	defer b.RUnlock()
+	nickname, ok := b.nicknames[fingerprint]
+	return nickname, ok
+}
+
+// Add sets the nickname of a fingerprint
+func (b *FingerprintBook) Add(fingerprint, nickname string) {
+	b.Lock()
+	defer b.Unlock()
+	b.nicknames[fingerprint] = nickname
+}
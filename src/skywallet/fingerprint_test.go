@@ -0,0 +1,90 @@
+package skywallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func (suite *devicerSuit) TestFingerprintAddress() {
+	fp := FingerprintAddress("2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw")
+	require.Regexp(suite.T(), regexp.MustCompile("^[0-9a-f]{4}-[0-9a-f]{4}$"), fp)
+	require.Equal(suite.T(), fp, FingerprintAddress("2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"))
+	require.NotEqual(suite.T(), fp, FingerprintAddress("zC8GAQGQBfwk7vtTxVoRG7iMperHNuyYPs"))
+}
+
+func (suite *devicerSuit) TestPassphraseFingerprint() {
+	// NOTE: Giving
+	address := "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"
+	data, err := proto.Marshal(&messages.ResponseSkycoinAddress{Addresses: []string{address}})
+	suite.Nil(err)
+	addressGenChunks, err := MessageAddressGen(1, 0, false)
+	suite.Nil(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, addressGenChunks).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_ResponseSkycoinAddress), Data: data}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	fp, err := device.PassphraseFingerprint()
+
+	// NOTE: Assert
+	suite.Nil(err)
+	mock.AssertExpectationsForObjects(suite.T(), driverMock)
+	require.Equal(suite.T(), FingerprintAddress(address), fp)
+}
+
+func (suite *devicerSuit) TestPassphraseFingerprintNotCached() {
+	// NOTE: Giving
+	cancelChunks, err := MessageCancel()
+	suite.Nil(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, cancelChunks).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Failure), Data: nil}, nil)
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_PassphraseRequest), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	_, err = device.PassphraseFingerprint()
+
+	// NOTE: Assert
+	suite.NotNil(err)
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 2)
+}
+
+func (suite *devicerSuit) TestFingerprintBook() {
+	dir, err := ioutil.TempDir("", "fingerprint-book")
+	suite.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "book.json")
+
+	// a missing file gives an empty book
+	book, err := LoadFingerprintBook(path)
+	suite.Nil(err)
+	_, ok := book.Nickname("abcd-0123")
+	suite.False(ok)
+
+	book.Add("abcd-0123", "savings")
+	suite.Nil(book.Save(path))
+
+	book, err = LoadFingerprintBook(path)
+	suite.Nil(err)
+	nickname, ok := book.Nickname("abcd-0123")
+	suite.True(ok)
+	suite.Equal("savings", nickname)
+
+	suite.Nil(ioutil.WriteFile(path, []byte("not json"), 0600))
+	_, err = LoadFingerprintBook(path)
+	suite.NotNil(err)
+}
@@ -1,8 +1,6 @@
 package skywallet
 
 import (
-	"bytes"
-	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
@@ -14,6 +12,7 @@ import (
 
 	messages "github.com/skycoin/hardware-wallet-protob/go"
 
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/codec"
 	"github.com/skycoin/hardware-wallet-go/src/skywallet/usb"
 	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
 )
@@ -68,6 +67,8 @@ type DeviceDriver interface {
 type Driver struct {
 	deviceType DeviceType
 	bus        usb.Bus
+	// path of the device to connect to, the first device found if empty
+	path string
 }
 
 func initUsb() []usb.Bus {
@@ -110,6 +111,57 @@ func NewDriver(deviceType DeviceType) (*Driver, error) {
 	return nil, fmt.Errorf("invalid device %s", deviceType)
 }
 
+// NewDriverAtPath create a driver connecting to the device at path, as
+// returned by GetDeviceInfos, or to the emulator at path, i.e emulator21324
+func NewDriverAtPath(deviceType DeviceType, path string) (*Driver, error) {
+	if deviceType == DeviceTypeEmulator {
+		port, ok := usb.EmulatorPort(path)
+		if !ok {
+			return nil, fmt.Errorf("invalid emulator path %s", path)
+		}
+		udpBus, err := usb.InitUDP([]int{port})
+		if err != nil {
+			return nil, err
+		}
+		return &Driver{
+			deviceType: deviceType,
+			bus:        usb.Init(udpBus),
+			path:       path,
+		}, nil
+	}
+
+	drv, err := NewDriver(deviceType)
+	if err != nil {
+		return nil, err
+	}
+	drv.path = path
+	return drv, nil
+}
+
+// NewReplayDriver create a driver connecting to a device that replays a trace recorded with RecordTrace
+func NewReplayDriver(deviceType DeviceType, replayer *usb.TraceReplayer) *Driver {
+	return &Driver{
+		deviceType: deviceType,
+		bus:        usb.Init(usb.InitReplay(replayer)),
+	}
+}
+
+// RecordTrace records all the packets exchanged with the devices connected from now on.
+// The payload of the messages carrying a secret, such as a mnemonic, a PIN or a
+// passphrase, is blanked.
+func (drv *Driver) RecordTrace(w io.Writer) {
+	tw := usb.NewTraceWriter(w)
+	tw.RedactKinds(codec.Sensitive)
+	drv.bus = usb.InitRecording(drv.bus, tw)
+}
+
+// RecordTraceWithSecrets records all the packets exchanged with the devices
+// connected from now on, the secrets they carry included. The trace can then
+// restore the wallet it was recorded with and must be kept private.
+func (drv *Driver) RecordTraceWithSecrets(w io.Writer) {
+	drv.bus = usb.InitRecording(drv.bus, usb.NewTraceWriter(w))
+}
+
 // Close closes the bus
 func (drv *Driver) Close() {
 	drv.bus.Close()
@@ -150,9 +202,22 @@ func (drv *Driver) GetDevice() (usb.Device, error) {
 		return nil, err
 	}
 
+	path := infos[0].Path
+	if drv.path != "" {
+		path = ""
+		for _, info := range infos {
+			if info.Path == drv.path {
+				path = info.Path
+			}
+		}
+		if path == "" {
+			return nil, fmt.Errorf("%v at path %s", ErrNoDeviceConnected, drv.path)
+		}
+	}
+
 	tries := 0
 	for tries < 3 {
-		dev, err := drv.bus.Connect(infos[0].Path)
+		dev, err := drv.bus.Connect(path)
 		if err != nil {
 			log.Print(err.Error())
 			tries++
@@ -177,6 +242,24 @@ func (drv *Driver) GetDeviceInfos() ([]usb.Info, error) {
 	return nil, errors.New("reading device info make sense for physical devices only")
 }
 
+// DevicePaths returns the paths of the devices the driver can connect to
+func (drv *Driver) DevicePaths() ([]string, error) {
+	var vendorID, productID uint16
+	if drv.deviceType == DeviceTypeUSB {
+		vendorID = SkycoinVendorID
+		productID = SkycoinHwProductID
+	}
+	infos, err := drv.bus.Enumerate(vendorID, productID)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(infos))
+	for i, info := range infos {
+		paths[i] = info.Path
+	}
+	return paths, nil
+}
+
 func sendToDeviceNoAnswer(dev usb.Device, chunks [][64]byte) error {
 	for _, element := range chunks {
 		_, err := dev.Write(element[:])
@@ -202,6 +285,32 @@ func sendToDevice(dev usb.Device, chunks [][64]byte) (wire.Message, error) {
 		return wire.Message{}, err
 	}
 
+	msg, err = ackEntropyRequests(dev, msg)
+	if err != nil {
+		return wire.Message{}, err
+	}
+
+	for msg.Kind == uint16(messages.MessageType_MessageType_Success) {
+		success, err := decodeSuccessMsgStruct(*msg)
+		if err != nil {
+			return wire.Message{}, err
+		}
+		if success.MsgType != nil && *success.MsgType == messages.MessageType(messages.MessageType_MessageType_EntropyAck) {
+			msg, err = wire.ReadFrom(dev)
+			if err != nil {
+				return wire.Message{}, err
+			}
+		} else {
+			break
+		}
+	}
+
+	return *msg, err
+}
+
+// ackEntropyRequests answers with host entropy while the device sends EntropyRequest messages
+func ackEntropyRequests(dev usb.Device, msg *wire.Message) (*wire.Message, error) {
+	var err error
 	for msg.Kind == uint16(messages.MessageType_MessageType_EntropyRequest) {
 		var wg sync.WaitGroup
 		wg.Add(1)
@@ -225,64 +334,19 @@ func sendToDevice(dev usb.Device, chunks [][64]byte) (wire.Message, error) {
 
 		msg, err = wire.ReadFrom(dev)
 		if err != nil {
-			return wire.Message{}, err
+			return nil, err
 		}
 		wg.Wait()
 	}
-	for msg.Kind == uint16(messages.MessageType_MessageType_Success) {
-		success, err := decodeSuccessMsgStruct(*msg)
-		if err != nil {
-			return wire.Message{}, err
-		}
-		if success.MsgType != nil && *success.MsgType == messages.MessageType(messages.MessageType_MessageType_EntropyAck) {
-			msg, err = wire.ReadFrom(dev)
-			if err != nil {
-				return wire.Message{}, err
-			}
-		} else {
-			break
-		}
-	}
 
-	return *msg, err
-}
-
-func binaryWrite(message io.Writer, data interface{}) {
-	err := binary.Write(message, binary.BigEndian, data)
-	if err != nil {
-		log.Panic(err)
-	}
-}
-
-func makeSkyWalletMessage(data []byte, msgID messages.MessageType) [][64]byte {
-	message := new(bytes.Buffer)
-	binaryWrite(message, []byte("##"))
-	binaryWrite(message, uint16(msgID))
-	binaryWrite(message, uint32(len(data)))
-	binaryWrite(message, []byte("\n"))
-	if len(data) > 0 {
-		binaryWrite(message, data[1:])
-	}
-
-	messageLen := message.Len()
-	var chunks [][64]byte
-	i := 0
-	for messageLen > 0 {
-		var chunk [64]byte
-		chunk[0] = '?'
-		copy(chunk[1:], message.Bytes()[63*i:63*(i+1)])
-		chunks = append(chunks, chunk)
-		messageLen -= 63
-		i = i + 1
-	}
-	return chunks
+	return msg, nil
 }
 
 // Initialize send an init request to the device
 func Initialize(dev usb.Device) error {
 	var chunks [][64]byte
 
-	chunks, err := MessageInitialize()
+	chunks, err := MessageInitialize(nil)
 	if err != nil {
 		return err
 	}
@@ -302,16 +366,20 @@ func DecodeSuccessOrFailMsg(msg wire.Message) (string, error) {
 	return "", fmt.Errorf("calling DecodeSuccessOrFailMsg on message kind %s", messages.MessageType(msg.Kind))
 }
 
+// decodeMsg decodes msg with the codec registry if it is of the expected kind
+func decodeMsg(msg wire.Message, kind messages.MessageType, caller string) (proto.Message, error) {
+	if msg.Kind != uint16(kind) {
+		return nil, fmt.Errorf("calling %s with wrong message type: %s", caller, messages.MessageType(msg.Kind))
+	}
+	return codec.Decode(msg)
+}
+
 func decodeSuccessMsgStruct(msg wire.Message) (messages.Success, error) {
-	if msg.Kind == uint16(messages.MessageType_MessageType_Success) {
-		success := messages.Success{}
-		err := proto.Unmarshal(msg.Data, &success)
-		if err != nil {
-			return messages.Success{}, err
-		}
-		return success, nil
+	pm, err := decodeMsg(msg, messages.MessageType_MessageType_Success, "DecodeSuccessMsg")
+	if err != nil {
+		return messages.Success{}, err
 	}
-	return messages.Success{}, fmt.Errorf("calling DecodeSuccessMsg with wrong message type: %s", messages.MessageType(msg.Kind))
+	return *pm.(*messages.Success), nil
 }
 
 // DecodeSuccessMsg convert byte data into string containing the success message returned by the device
@@ -325,69 +393,56 @@ func DecodeSuccessMsg(msg wire.Message) (string, error) {
 
 // DecodeFailMsg convert byte data into string containing the failure returned by the device
 func DecodeFailMsg(msg wire.Message) (string, error) {
-	if msg.Kind == uint16(messages.MessageType_MessageType_Failure) {
-		failure := &messages.Failure{}
-		err := proto.Unmarshal(msg.Data, failure)
-		if err != nil {
-			return "", err
-		}
-		return failure.GetMessage(), nil
+	pm, err := decodeMsg(msg, messages.MessageType_MessageType_Failure, "DecodeFailMsg")
+	if err != nil {
+		return "", err
 	}
-	return "", fmt.Errorf("calling DecodeFailMsg with wrong message type: %s", messages.MessageType(msg.Kind))
+	return pm.(*messages.Failure).GetMessage(), nil
+}
+
+// DecodeFeatures convert byte data into the features of the device
+func DecodeFeatures(msg wire.Message) (*messages.Features, error) {
+	pm, err := decodeMsg(msg, messages.MessageType_MessageType_Features, "DecodeFeatures")
+	if err != nil {
+		return nil, err
+	}
+	return pm.(*messages.Features), nil
 }
 
 // DecodeResponseSkycoinAddress convert byte data into list of addresses, meant to be used after DevicePinMatrixAck
 func DecodeResponseSkycoinAddress(msg wire.Message) ([]string, error) {
-	if msg.Kind == uint16(messages.MessageType_MessageType_ResponseSkycoinAddress) {
-		responseSkycoinAddress := &messages.ResponseSkycoinAddress{}
-		err := proto.Unmarshal(msg.Data, responseSkycoinAddress)
-		if err != nil {
-			return []string{}, err
-		}
-		return responseSkycoinAddress.GetAddresses(), nil
+	pm, err := decodeMsg(msg, messages.MessageType_MessageType_ResponseSkycoinAddress, "DecodeResponseSkycoinAddress")
+	if err != nil {
+		return []string{}, err
 	}
-
-	return []string{}, fmt.Errorf("calling DecodeResponseSkycoinAddress with wrong message type: %s", messages.MessageType(msg.Kind))
+	return pm.(*messages.ResponseSkycoinAddress).GetAddresses(), nil
 }
 
 // DecodeResponseTransactionSign convert byte data into list of signatures
 func DecodeResponseTransactionSign(msg wire.Message) ([]string, error) {
-	if msg.Kind == uint16(messages.MessageType_MessageType_ResponseTransactionSign) {
-		responseSkycoinTransactionSign := &messages.ResponseTransactionSign{}
-		err := proto.Unmarshal(msg.Data, responseSkycoinTransactionSign)
-		if err != nil {
-			return make([]string, 0), err
-		}
-		return responseSkycoinTransactionSign.GetSignatures(), nil
+	pm, err := decodeMsg(msg, messages.MessageType_MessageType_ResponseTransactionSign, "DecodeResponseTransactionSign")
+	if err != nil {
+		return []string{}, err
 	}
-
-	return []string{}, fmt.Errorf("calling DecodeResponseeSkycoinSignMessage with wrong message type: %s", messages.MessageType(msg.Kind))
+	return pm.(*messages.ResponseTransactionSign).GetSignatures(), nil
 }
 
 // DecodeResponseSkycoinSignMessage convert byte data into signed message, meant to be used after DevicePinMatrixAck
 func DecodeResponseSkycoinSignMessage(msg wire.Message) (string, error) {
-	if msg.Kind == uint16(messages.MessageType_MessageType_ResponseSkycoinSignMessage) {
-		responseSkycoinSignMessage := &messages.ResponseSkycoinSignMessage{}
-		err := proto.Unmarshal(msg.Data, responseSkycoinSignMessage)
-		if err != nil {
-			return "", err
-		}
-		return responseSkycoinSignMessage.GetSignedMessage(), nil
+	pm, err := decodeMsg(msg, messages.MessageType_MessageType_ResponseSkycoinSignMessage, "DecodeResponseSkycoinSignMessage")
+	if err != nil {
+		return "", err
 	}
-	return "", fmt.Errorf("calling DecodeResponseeSkycoinSignMessage with wrong message type: %s", messages.MessageType(msg.Kind))
+	return pm.(*messages.ResponseSkycoinSignMessage).GetSignedMessage(), nil
 }
 
 // DecodeResponseEntropyMessage convert byte data into entropy message, meant to be used after GetEntropy
 func DecodeResponseEntropyMessage(msg wire.Message) (*messages.Entropy, error) {
-	if msg.Kind == uint16(messages.MessageType_MessageType_Entropy) {
-		responseEntropyMessage := &messages.Entropy{}
-		err := proto.Unmarshal(msg.Data, responseEntropyMessage)
-		if err != nil {
-			return nil, err
-		}
-		return responseEntropyMessage, nil
+	pm, err := decodeMsg(msg, messages.MessageType_MessageType_Entropy, "DecodeResponseEntropyMessage")
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("calling DecodeResponseEntropyMessage with wrong message type: %s", messages.MessageType(msg.Kind))
+	return pm.(*messages.Entropy), nil
 }
 
 // Does OS allow sync canceling via our custom libusb patches?
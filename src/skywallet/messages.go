@@ -8,28 +8,20 @@ import (
 	"github.com/skycoin/skycoin/src/cipher"
 
 	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/codec"
 )
 
 // MessageCancel prepare Cancel request
 func MessageCancel() ([][64]byte, error) {
 	msg := &messages.Cancel{}
-	data, err := proto.Marshal(msg)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_Cancel)
-	return chunks, nil
+	return codec.Encode(msg)
 }
 
 // MessageButtonAck send this message (before user action) when the device expects the user to push a button
 func MessageButtonAck() ([][64]byte, error) {
 	buttonAck := &messages.ButtonAck{}
-	data, err := proto.Marshal(buttonAck)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_ButtonAck)
-	return chunks, nil
+	return codec.Encode(buttonAck)
 }
 
 // MessagePassphraseAck send this message when the device expects receiving a Passphrase
@@ -37,12 +29,7 @@ func MessagePassphraseAck(passphrase string) ([][64]byte, error) {
 	msg := &messages.PassphraseAck{
 		Passphrase: proto.String(passphrase),
 	}
-	data, err := proto.Marshal(msg)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_PassphraseAck)
-	return chunks, nil
+	return codec.Encode(msg)
 }
 
 // MessageWordAck send this message between each word of the seed (before user action) during device backup
@@ -50,12 +37,7 @@ func MessageWordAck(word string) ([][64]byte, error) {
 	wordAck := &messages.WordAck{
 		Word: proto.String(word),
 	}
-	data, err := proto.Marshal(wordAck)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_WordAck)
-	return chunks, nil
+	return codec.Encode(wordAck)
 }
 
 // MessageCheckMessageSignature prepare CheckMessageSignature request
@@ -65,13 +47,7 @@ func MessageCheckMessageSignature(message, signature, address string) ([][64]byt
 		Message:   proto.String(message),
 		Signature: proto.String(signature),
 	}
-
-	data, err := proto.Marshal(msg)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_SkycoinCheckMessageSignature)
-	return chunks, nil
+	return codec.Encode(msg)
 }
 
 // MessageAddressGen prepare MessageAddressGen request
@@ -81,14 +57,7 @@ func MessageAddressGen(addressN, startIndex uint32, confirmAddress bool) ([][64]
 		ConfirmAddress: proto.Bool(confirmAddress),
 		StartIndex:     proto.Uint32(startIndex),
 	}
-
-	data, err := proto.Marshal(skycoinAddress)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_SkycoinAddress)
-	return chunks, nil
+	return codec.Encode(skycoinAddress)
 }
 
 // MessageDeviceGetRawEntropy prepare GetEntropy request
@@ -96,14 +65,7 @@ func MessageDeviceGetRawEntropy(entropyBytes uint32) ([][64]byte, error) {
 	getEntropy := &messages.GetRawEntropy{
 		Size_: &entropyBytes,
 	}
-
-	data, err := proto.Marshal(getEntropy)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_GetRawEntropy)
-	return chunks, nil
+	return codec.Encode(getEntropy)
 }
 
 // MessageDeviceGetMixedEntropy prepare GetMixedEntropy request
@@ -111,14 +73,7 @@ func MessageDeviceGetMixedEntropy(entropyBytes uint32) ([][64]byte, error) {
 	getEntropy := &messages.GetMixedEntropy{
 		Size_: &entropyBytes,
 	}
-
-	data, err := proto.Marshal(getEntropy)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_GetMixedEntropy)
-	return chunks, nil
+	return codec.Encode(getEntropy)
 }
 
 // MessageApplySettings prepare MessageApplySettings request
@@ -131,24 +86,13 @@ func MessageApplySettings(usePassphrase *bool, label string, language string) ([
 		applySettings.UsePassphrase = proto.Bool(*usePassphrase)
 	}
 	log.Println(applySettings)
-	data, err := proto.Marshal(applySettings)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_ApplySettings)
-	return chunks, nil
+	return codec.Encode(applySettings)
 }
 
 // MessageBackup prepare MessageBackup request
 func MessageBackup() ([][64]byte, error) {
 	backupDevice := &messages.BackupDevice{}
-	data, err := proto.Marshal(backupDevice)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_BackupDevice)
-	return chunks, nil
+	return codec.Encode(backupDevice)
 }
 
 // MessageChangePin prepare MessageChangePin request
@@ -157,23 +101,13 @@ func MessageChangePin(remove *bool) ([][64]byte, error) {
 	if remove != nil {
 		changePin.Remove = proto.Bool(*remove)
 	}
-	data, err := proto.Marshal(changePin)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_ChangePin)
-	return chunks, nil
+	return codec.Encode(changePin)
 }
 
 // MessageConnected prepare MessageConnected request
 func MessageConnected() ([][64]byte, error) {
 	msgRaw := &messages.Ping{}
-	data, err := proto.Marshal(msgRaw)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_Ping)
-	return chunks, nil
+	return codec.Encode(msgRaw)
 }
 
 // MessageFirmwareErase prepare MessageFirmwareErase request
@@ -181,14 +115,7 @@ func MessageFirmwareErase(payload []byte) ([][64]byte, error) {
 	deviceFirmwareErase := &messages.FirmwareErase{
 		Length: proto.Uint32(uint32(len(payload))),
 	}
-
-	erasedata, err := proto.Marshal(deviceFirmwareErase)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(erasedata, messages.MessageType_MessageType_FirmwareErase)
-	return chunks, nil
+	return codec.Encode(deviceFirmwareErase)
 }
 
 // MessageFirmwareUpload prepare MessageFirmwareUpload request
@@ -197,26 +124,13 @@ func MessageFirmwareUpload(payload []byte, hash [32]byte) ([][64]byte, error) {
 		Payload: payload,
 		Hash:    hash[:],
 	}
-
-	uploaddata, err := proto.Marshal(deviceFirmwareUpload)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(uploaddata, messages.MessageType_MessageType_FirmwareUpload)
-	return chunks, nil
+	return codec.Encode(deviceFirmwareUpload)
 }
 
 // MessageGetFeatures prepare MessageGetFeatures request
 func MessageGetFeatures() ([][64]byte, error) {
 	featureMsg := &messages.GetFeatures{}
-	data, err := proto.Marshal(featureMsg)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_GetFeatures)
-	return chunks, nil
+	return codec.Encode(featureMsg)
 }
 
 // MessageGenerateMnemonic prepare MessageGenerateMnemonic request
@@ -225,14 +139,7 @@ func MessageGenerateMnemonic(wordCount uint32, usePassphrase bool) ([][64]byte,
 		PassphraseProtection: proto.Bool(usePassphrase),
 		WordCount:            proto.Uint32(wordCount),
 	}
-
-	data, err := proto.Marshal(skycoinGenerateMnemonic)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_GenerateMnemonic)
-	return chunks, nil
+	return codec.Encode(skycoinGenerateMnemonic)
 }
 
 // MessageRecovery prepare MessageRecovery request
@@ -244,14 +151,7 @@ func MessageRecovery(wordCount uint32, usePassphrase *bool, dryRun bool) ([][64]
 	if usePassphrase != nil {
 		recoveryDevice.PassphraseProtection = proto.Bool(*usePassphrase)
 	}
-	data, err := proto.Marshal(recoveryDevice)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_RecoveryDevice)
-
-	return chunks, nil
+	return codec.Encode(recoveryDevice)
 }
 
 // MessageSetMnemonic prepare MessageSetMnemonic request
@@ -259,14 +159,7 @@ func MessageSetMnemonic(mnemonic string) ([][64]byte, error) {
 	skycoinSetMnemonic := &messages.SetMnemonic{
 		Mnemonic: proto.String(mnemonic),
 	}
-
-	data, err := proto.Marshal(skycoinSetMnemonic)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_SetMnemonic)
-	return chunks, nil
+	return codec.Encode(skycoinSetMnemonic)
 }
 
 // MessageSignMessage prepare MessageSignMessage request
@@ -275,14 +168,7 @@ func MessageSignMessage(addressIndex int, message string) ([][64]byte, error) {
 		AddressN: proto.Uint32(uint32(addressIndex)),
 		Message:  proto.String(message),
 	}
-
-	data, err := proto.Marshal(skycoinSignMessage)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_SkycoinSignMessage)
-	return chunks, nil
+	return codec.Encode(skycoinSignMessage)
 }
 
 // MessageTransactionSign prepare MessageTransactionSign request
@@ -294,26 +180,13 @@ func MessageTransactionSign(inputs []*messages.SkycoinTransactionInput, outputs
 		TransactionOut: outputs,
 	}
 	log.Println(skycoinTransactionSignMessage)
-
-	data, err := proto.Marshal(skycoinTransactionSignMessage)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_TransactionSign)
-	return chunks, nil
+	return codec.Encode(skycoinTransactionSignMessage)
 }
 
 // MessageWipe prepare MessageWipe request
 func MessageWipe() ([][64]byte, error) {
 	wipeDevice := &messages.WipeDevice{}
-	data, err := proto.Marshal(wipeDevice)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_WipeDevice)
-	return chunks, nil
+	return codec.Encode(wipeDevice)
 }
 
 // MessagePinMatrixAck prepare MessagePinMatrixAck request
@@ -321,13 +194,7 @@ func MessagePinMatrixAck(p string) ([][64]byte, error) {
 	pinAck := &messages.PinMatrixAck{
 		Pin: proto.String(p),
 	}
-	data, err := proto.Marshal(pinAck)
-	if err != nil {
-		return [][64]byte{}, err
-	}
-
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_PinMatrixAck)
-	return chunks, nil
+	return codec.Encode(pinAck)
 }
 
 // MessageEntropyAck prepare MessageEntropyAck request
@@ -339,24 +206,67 @@ func MessageEntropyAck(bufferSize int) ([][64]byte, error) {
 	entropyAck := &messages.EntropyAck{
 		Entropy: buffer,
 	}
-	data, err := proto.Marshal(entropyAck)
-	if err != nil {
-		return nil, err
+	return codec.Encode(entropyAck)
+}
+
+// MessageInitialize prepare MessageInitialize request, state resumes
+// the session of a previously used passphrase if it is still cached
+func MessageInitialize(state []byte) ([][64]byte, error) {
+	initialize := &messages.Initialize{
+		State: state,
+	}
+	return codec.Encode(initialize)
+}
+
+// MessageResetDevice prepare MessageResetDevice request
+func MessageResetDevice(strength uint32, usePassphrase, usePin, displayRandom, skipBackup bool, label string) ([][64]byte, error) {
+	resetDevice := &messages.ResetDevice{
+		Strength:             proto.Uint32(strength),
+		PassphraseProtection: proto.Bool(usePassphrase),
+		PinProtection:        proto.Bool(usePin),
+		DisplayRandom:        proto.Bool(displayRandom),
+		SkipBackup:           proto.Bool(skipBackup),
 	}
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_EntropyAck)
-	return chunks, nil
+	if label != "" {
+		resetDevice.Label = proto.String(label)
+	}
+	return codec.Encode(resetDevice)
 }
 
-// MessageInitialize prepare MessageInitialize request
-func MessageInitialize() ([][64]byte, error) {
-	initialize := &messages.Initialize{}
-	data, err := proto.Marshal(initialize)
-	if err != nil {
-		return nil, err
+// MessageLoadDevice prepare MessageLoadDevice request, either mnemonic or node should be set
+func MessageLoadDevice(mnemonic string, node *messages.HDNodeType, pin, label string, usePassphrase, skipChecksum bool) ([][64]byte, error) {
+	loadDevice := &messages.LoadDevice{
+		Node:                 node,
+		PassphraseProtection: proto.Bool(usePassphrase),
+		SkipChecksum:         proto.Bool(skipChecksum),
 	}
+	if mnemonic != "" {
+		loadDevice.Mnemonic = proto.String(mnemonic)
+	}
+	if pin != "" {
+		loadDevice.Pin = proto.String(pin)
+	}
+	if label != "" {
+		loadDevice.Label = proto.String(label)
+	}
+	return codec.Encode(loadDevice)
+}
+
+// MessagePing prepare MessagePing request
+func MessagePing(message string, buttonProtection, pinProtection, passphraseProtection bool) ([][64]byte, error) {
+	ping := &messages.Ping{
+		Message:              proto.String(message),
+		ButtonProtection:     proto.Bool(buttonProtection),
+		PinProtection:        proto.Bool(pinProtection),
+		PassphraseProtection: proto.Bool(passphraseProtection),
+	}
+	return codec.Encode(ping)
+}
 
-	chunks := makeSkyWalletMessage(data, messages.MessageType_MessageType_Initialize)
-	return chunks, nil
+// MessagePassphraseStateAck prepare MessagePassphraseStateAck request
+func MessagePassphraseStateAck() ([][64]byte, error) {
+	passphraseStateAck := &messages.PassphraseStateAck{}
+	return codec.Encode(passphraseStateAck)
 }
 
 // MessageSimulateButtonPress prespares a emulator button press simulation button
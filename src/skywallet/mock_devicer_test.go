@@ -4,6 +4,7 @@ package skywallet
 
 import messages "github.com/skycoin/hardware-wallet-protob/go"
 import mock "github.com/stretchr/testify/mock"
+import proto "github.com/gogo/protobuf/proto"
 import wire "github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
 
 // MockDevicer is an autogenerated mock type for the Devicer type
@@ -109,6 +110,27 @@ func (_m *MockDevicer) ButtonAck() (wire.Message, error) {
 	return r0, r1
 }
 
+// Call provides a mock function with given fields: kind, msg
+func (_m *MockDevicer) Call(kind uint16, msg proto.Message) (wire.Message, error) {
+	ret := _m.Called(kind, msg)
+
+	var r0 wire.Message
+	if rf, ok := ret.Get(0).(func(uint16, proto.Message) wire.Message); ok {
+		r0 = rf(kind, msg)
+	} else {
+		r0 = ret.Get(0).(wire.Message)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint16, proto.Message) error); ok {
+		r1 = rf(kind, msg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Cancel provides a mock function with given fields:
 func (_m *MockDevicer) Cancel() (wire.Message, error) {
 	ret := _m.Called()
@@ -177,6 +199,20 @@ func (_m *MockDevicer) Close() {
 	_m.Called()
 }
 
+// Connect provides a mock function with given fields:
+func (_m *MockDevicer) Connect() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Connected provides a mock function with given fields:
 func (_m *MockDevicer) Connected() bool {
 	ret := _m.Called()
@@ -191,6 +227,20 @@ func (_m *MockDevicer) Connected() bool {
 	return r0
 }
 
+// Disconnect provides a mock function with given fields:
+func (_m *MockDevicer) Disconnect() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FirmwareUpload provides a mock function with given fields: payload, hash
 func (_m *MockDevicer) FirmwareUpload(payload []byte, hash [32]byte) error {
 	ret := _m.Called(payload, hash)
@@ -247,6 +297,48 @@ func (_m *MockDevicer) GetFeatures() (wire.Message, error) {
 	return r0, r1
 }
 
+// Initialize provides a mock function with given fields:
+func (_m *MockDevicer) Initialize() (wire.Message, error) {
+	ret := _m.Called()
+
+	var r0 wire.Message
+	if rf, ok := ret.Get(0).(func() wire.Message); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(wire.Message)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadDevice provides a mock function with given fields: mnemonic, node, pin, label, usePassphrase, skipChecksum
+func (_m *MockDevicer) LoadDevice(mnemonic string, node *messages.HDNodeType, pin string, label string, usePassphrase bool, skipChecksum bool) (wire.Message, error) {
+	ret := _m.Called(mnemonic, node, pin, label, usePassphrase, skipChecksum)
+
+	var r0 wire.Message
+	if rf, ok := ret.Get(0).(func(string, *messages.HDNodeType, string, string, bool, bool) wire.Message); ok {
+		r0 = rf(mnemonic, node, pin, label, usePassphrase, skipChecksum)
+	} else {
+		r0 = ret.Get(0).(wire.Message)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *messages.HDNodeType, string, string, bool, bool) error); ok {
+		r1 = rf(mnemonic, node, pin, label, usePassphrase, skipChecksum)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PassphraseAck provides a mock function with given fields: passphrase
 func (_m *MockDevicer) PassphraseAck(passphrase string) (wire.Message, error) {
 	ret := _m.Called(passphrase)
@@ -268,6 +360,43 @@ func (_m *MockDevicer) PassphraseAck(passphrase string) (wire.Message, error) {
 	return r0, r1
 }
 
+// PassphraseFingerprint provides a mock function with given fields:
+func (_m *MockDevicer) PassphraseFingerprint() (string, error) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PassphraseState provides a mock function with given fields:
+func (_m *MockDevicer) PassphraseState() []byte {
+	ret := _m.Called()
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func() []byte); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	return r0
+}
+
 // PinMatrixAck provides a mock function with given fields: p
 func (_m *MockDevicer) PinMatrixAck(p string) (wire.Message, error) {
 	ret := _m.Called(p)
@@ -289,19 +418,40 @@ func (_m *MockDevicer) PinMatrixAck(p string) (wire.Message, error) {
 	return r0, r1
 }
 
+// Ping provides a mock function with given fields: message, buttonProtection, pinProtection, passphraseProtection
+func (_m *MockDevicer) Ping(message string, buttonProtection bool, pinProtection bool, passphraseProtection bool) (wire.Message, error) {
+	ret := _m.Called(message, buttonProtection, pinProtection, passphraseProtection)
+
+	var r0 wire.Message
+	if rf, ok := ret.Get(0).(func(string, bool, bool, bool) wire.Message); ok {
+		r0 = rf(message, buttonProtection, pinProtection, passphraseProtection)
+	} else {
+		r0 = ret.Get(0).(wire.Message)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool, bool, bool) error); ok {
+		r1 = rf(message, buttonProtection, pinProtection, passphraseProtection)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Recovery provides a mock function with given fields: wordCount, usePassphrase, dryRun
-func (_m *MockDevicer) Recovery(wordCount uint32, usePassphrase bool, dryRun bool) (wire.Message, error) {
+func (_m *MockDevicer) Recovery(wordCount uint32, usePassphrase *bool, dryRun bool) (wire.Message, error) {
 	ret := _m.Called(wordCount, usePassphrase, dryRun)
 
 	var r0 wire.Message
-	if rf, ok := ret.Get(0).(func(uint32, bool, bool) wire.Message); ok {
+	if rf, ok := ret.Get(0).(func(uint32, *bool, bool) wire.Message); ok {
 		r0 = rf(wordCount, usePassphrase, dryRun)
 	} else {
 		r0 = ret.Get(0).(wire.Message)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint32, bool, bool) error); ok {
+	if rf, ok := ret.Get(1).(func(uint32, *bool, bool) error); ok {
 		r1 = rf(wordCount, usePassphrase, dryRun)
 	} else {
 		r1 = ret.Error(1)
@@ -310,6 +460,27 @@ func (_m *MockDevicer) Recovery(wordCount uint32, usePassphrase bool, dryRun boo
 	return r0, r1
 }
 
+// ResetDevice provides a mock function with given fields: wordCount, usePassphrase, usePin, displayRandom, skipBackup, label
+func (_m *MockDevicer) ResetDevice(wordCount uint32, usePassphrase bool, usePin bool, displayRandom bool, skipBackup bool, label string) (wire.Message, error) {
+	ret := _m.Called(wordCount, usePassphrase, usePin, displayRandom, skipBackup, label)
+
+	var r0 wire.Message
+	if rf, ok := ret.Get(0).(func(uint32, bool, bool, bool, bool, string) wire.Message); ok {
+		r0 = rf(wordCount, usePassphrase, usePin, displayRandom, skipBackup, label)
+	} else {
+		r0 = ret.Get(0).(wire.Message)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint32, bool, bool, bool, bool, string) error); ok {
+		r1 = rf(wordCount, usePassphrase, usePin, displayRandom, skipBackup, label)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetAutoPressButton provides a mock function with given fields: simulateButtonPress, simulateButtonType
 func (_m *MockDevicer) SetAutoPressButton(simulateButtonPress bool, simulateButtonType ButtonType) error {
 	ret := _m.Called(simulateButtonPress, simulateButtonType)
@@ -324,6 +495,11 @@ func (_m *MockDevicer) SetAutoPressButton(simulateButtonPress bool, simulateButt
 	return r0
 }
 
+// SetChangeVerification provides a mock function with given fields: handle
+func (_m *MockDevicer) SetChangeVerification(handle RequestHandler) {
+	_m.Called(handle)
+}
+
 // SetMnemonic provides a mock function with given fields: mnemonic
 func (_m *MockDevicer) SetMnemonic(mnemonic string) (wire.Message, error) {
 	ret := _m.Called(mnemonic)
@@ -345,6 +521,11 @@ func (_m *MockDevicer) SetMnemonic(mnemonic string) (wire.Message, error) {
 	return r0, r1
 }
 
+// SetPassphraseState provides a mock function with given fields: state
+func (_m *MockDevicer) SetPassphraseState(state []byte) {
+	_m.Called(state)
+}
+
 // SignMessage provides a mock function with given fields: addressIndex, message
 func (_m *MockDevicer) SignMessage(addressIndex int, message string) (wire.Message, error) {
 	ret := _m.Called(addressIndex, message)
@@ -0,0 +1,172 @@
+/*
+Package node is a client of the REST API of a Skycoin node, to look up the
+unspent outputs of the addresses of a device and to inject the transactions it
+signs.
+*/
+package node
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	skyWallet "github.com/skycoin/hardware-wallet-go/src/skywallet"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/builder"
+)
+
+// DefaultAddr is the address of the REST API of a local node
+const DefaultAddr = "http://127.0.0.1:6420"
+
+// csrfHeader is the header carrying the CSRF token of the nodes that require it
+const csrfHeader = "X-CSRF-Token"
+
+// Client is the part of the API of a node used to send coins
+type Client interface {
+	// UnspentOutputs returns the outputs of addresses that can be spent, the
+	// outputs spent by unconfirmed transactions are left out
+	UnspentOutputs(addresses []string) ([]builder.UTXO, error)
+	// InjectTransaction broadcasts the serialized signed transaction and
+	// returns its id
+	InjectTransaction(raw []byte) (string, error)
+}
+
+// APIError is returned if the node answers a request with an error status
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("node answered %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// HTTPClient is a Client of a node over HTTP
+type HTTPClient struct {
+	addr string
+	http *http.Client
+}
+
+// NewHTTPClient returns a client of the node at addr, such as DefaultAddr
+func NewHTTPClient(addr string) *HTTPClient {
+	return &HTTPClient{
+		addr: strings.TrimRight(addr, "/"),
+		http: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// output is an unspent output listed by /api/v1/outputs
+type output struct {
+	Hash            string `json:"hash"`
+	Address         string `json:"address"`
+	Coins           string `json:"coins"`
+	CalculatedHours uint64 `json:"calculated_hours"`
+}
+
+// outputs is the response of /api/v1/outputs
+type outputs struct {
+	HeadOutputs     []output `json:"head_outputs"`
+	OutgoingOutputs []output `json:"outgoing_outputs"`
+}
+
+// UnspentOutputs returns the outputs of addresses that can be spent, with the
+// hours they hold now
+func (c *HTTPClient) UnspentOutputs(addresses []string) ([]builder.UTXO, error) {
+	var resp outputs
+	query := url.Values{"addrs": {strings.Join(addresses, ",")}}
+	if err := c.get("/api/v1/outputs?"+query.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	outgoing := make(map[string]bool, len(resp.OutgoingOutputs))
+	for _, o := range resp.OutgoingOutputs {
+		outgoing[o.Hash] = true
+	}
+	var utxos []builder.UTXO
+	for _, o := range resp.HeadOutputs {
+		if outgoing[o.Hash] {
+			continue
+		}
+		coins, err := skyWallet.ParseDroplets(o.Coins)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %v", o.Hash, err)
+		}
+		utxos = append(utxos, builder.UTXO{
+			Hash:    o.Hash,
+			Address: o.Address,
+			Coins:   coins,
+			Hours:   o.CalculatedHours,
+		})
+	}
+	return utxos, nil
+}
+
+// InjectTransaction broadcasts the serialized signed transaction and returns its id
+func (c *HTTPClient) InjectTransaction(raw []byte) (string, error) {
+	body, err := json.Marshal(map[string]string{"rawtx": hex.EncodeToString(raw)})
+	if err != nil {
+		return "", err
+	}
+	token, err := c.csrfToken()
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.addr+"/api/v1/injectTransaction", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set(csrfHeader, token)
+	}
+	var txid string
+	if err = c.do(req, &txid); err != nil {
+		return "", err
+	}
+	return txid, nil
+}
+
+// csrfToken returns the CSRF token of the node, empty if the node does not use them
+func (c *HTTPClient) csrfToken() (string, error) {
+	var resp struct {
+		Token string `json:"csrf_token"`
+	}
+	err := c.get("/api/v1/csrf", &resp)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	return resp.Token, err
+}
+
+func (c *HTTPClient) get(path string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.addr+path, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, v)
+}
+
+// do sends req and decodes the JSON response in v
+func (c *HTTPClient) do(req *http.Request, v interface{}) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		if err != nil {
+			return err
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(b))}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,118 @@
+package node
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/builder"
+)
+
+const csrfToken = "c2t5d2FsbGV0"
+
+// standInNode serves the part of the API of a node used by HTTPClient
+type standInNode struct {
+	t        *testing.T
+	csrf     bool
+	addrs    []string
+	injected []string
+}
+
+func (n *standInNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/v1/csrf":
+		if !n.csrf {
+			http.Error(w, "404 Not Found", http.StatusNotFound)
+			return
+		}
+		n.write(w, map[string]string{"csrf_token": csrfToken})
+	case "/api/v1/outputs":
+		n.addrs = append(n.addrs, r.URL.Query().Get("addrs"))
+		n.write(w, json.RawMessage(`{
+			"head_outputs": [
+				{"hash": "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", "address": "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "coins": "2.000000", "hours": 1, "calculated_hours": 10},
+				{"hash": "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", "address": "K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", "coins": "5.5", "hours": 1, "calculated_hours": 2},
+				{"hash": "d11c62b1e0e9abf629b1f5f4699cef9fbc504b45ceedf0047ead686979498218", "address": "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "coins": "1.000000", "hours": 1, "calculated_hours": 40}
+			],
+			"outgoing_outputs": [
+				{"hash": "d11c62b1e0e9abf629b1f5f4699cef9fbc504b45ceedf0047ead686979498218", "address": "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "coins": "1.000000", "hours": 1, "calculated_hours": 40}
+			],
+			"incoming_outputs": []
+		}`))
+	case "/api/v1/injectTransaction":
+		if r.Method != http.MethodPost || (n.csrf && r.Header.Get(csrfHeader) != csrfToken) {
+			http.Error(w, "403 Forbidden", http.StatusForbidden)
+			return
+		}
+		var req struct {
+			RawTx string `json:"rawtx"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "400 Bad Request - "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := hex.DecodeString(req.RawTx); err != nil || req.RawTx == "" {
+			http.Error(w, "400 Bad Request - invalid rawtx", http.StatusBadRequest)
+			return
+		}
+		n.injected = append(n.injected, req.RawTx)
+		n.write(w, "b5e5b4e1d6f2f0aebe7c4b2b3a7ea3c5b37f6e3e6e0a0d6d1c24a6c4a2b7c5d9")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// write writes v as JSON
+func (n *standInNode) write(w http.ResponseWriter, v interface{}) {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		n.t.Error(err)
+	}
+}
+
+func TestUnspentOutputs(t *testing.T) {
+	n := &standInNode{t: t}
+	server := httptest.NewServer(n)
+	defer server.Close()
+
+	var client Client = NewHTTPClient(server.URL + "/")
+	utxos, err := client.UnspentOutputs([]string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", "K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw,K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot"}, n.addrs)
+	// the output spent by an unconfirmed transaction is left out
+	require.Equal(t, []builder.UTXO{
+		{Hash: "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", Address: "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", Coins: 2000000, Hours: 10},
+		{Hash: "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", Address: "K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", Coins: 5500000, Hours: 2},
+	}, utxos)
+}
+
+func TestInjectTransaction(t *testing.T) {
+	for _, csrf := range []bool{false, true} {
+		n := &standInNode{t: t, csrf: csrf}
+		server := httptest.NewServer(n)
+
+		client := NewHTTPClient(server.URL)
+		txid, err := client.InjectTransaction([]byte{0x18, 0x01})
+		require.NoError(t, err)
+		require.Equal(t, "b5e5b4e1d6f2f0aebe7c4b2b3a7ea3c5b37f6e3e6e0a0d6d1c24a6c4a2b7c5d9", txid)
+		require.Equal(t, []string{"1801"}, n.injected)
+
+		_, err = client.InjectTransaction(nil)
+		require.Equal(t, &APIError{StatusCode: http.StatusBadRequest, Message: "400 Bad Request - invalid rawtx"}, err)
+		server.Close()
+	}
+}
+
+func TestUnavailableNode(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	client := NewHTTPClient(server.URL)
+	_, err := client.UnspentOutputs([]string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"})
+	require.Equal(t, &APIError{StatusCode: http.StatusNotFound, Message: "404 page not found"}, err)
+
+	server.Close()
+	_, err = client.UnspentOutputs([]string{"2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"})
+	require.Error(t, err)
+}
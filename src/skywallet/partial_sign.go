@@ -0,0 +1,79 @@
+package skywallet
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/psbt"
+)
+
+// PartialTransactionMessages returns the inputs and outputs of t to send to the
+// device, the inputs are signed with the key at their AddressIndex
+func PartialTransactionMessages(t *psbt.Transaction) ([]*messages.SkycoinTransactionInput, []*messages.SkycoinTransactionOutput) {
+	inputs := make([]*messages.SkycoinTransactionInput, len(t.Inputs))
+	for i, in := range t.Inputs {
+		inputs[i] = &messages.SkycoinTransactionInput{
+			HashIn: proto.String(in.Hash),
+			Index:  proto.Uint32(in.AddressIndex),
+		}
+	}
+	outputs := make([]*messages.SkycoinTransactionOutput, len(t.Outputs))
+	for i, out := range t.Outputs {
+		outputs[i] = &messages.SkycoinTransactionOutput{
+			Address:      proto.String(out.Address),
+			Coin:         proto.Uint64(out.Coins),
+			Hour:         proto.Uint64(out.Hours),
+			AddressIndex: out.AddressIndex,
+		}
+	}
+	return inputs, outputs
+}
+
+// SignPartialTransaction asks d to sign the transaction and adds the
+// signatures of the unsigned inputs owned by d. The device signs every input,
+// as its signatures commit to all of them, but only the signatures matching
+// the input addresses are kept: the inputs of other wallets stay unsigned.
+// It returns the indexes of the inputs signed, the requests of the device are
+// answered by handle.
+func SignPartialTransaction(d Devicer, t *psbt.Transaction, handle RequestHandler) ([]int, error) {
+	inputs, outputs := PartialTransactionMessages(t)
+	msg, err := d.TransactionSign(inputs, outputs)
+	if err != nil {
+		return nil, err
+	}
+	msg, err = handle(d, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []string
+	switch msg.Kind {
+	case uint16(messages.MessageType_MessageType_ResponseTransactionSign):
+		signatures, err = DecodeResponseTransactionSign(msg)
+		if err != nil {
+			return nil, err
+		}
+	case uint16(messages.MessageType_MessageType_Failure):
+		var failMsg string
+		failMsg, err = DecodeFailMsg(msg)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed with message: %s", failMsg)
+	default:
+		return nil, fmt.Errorf("received unexpected message type: %s", messages.MessageType(msg.Kind))
+	}
+	if len(signatures) != len(t.Inputs) {
+		return nil, fmt.Errorf("device returned %d signatures for %d inputs", len(signatures), len(t.Inputs))
+	}
+
+	var signed []int
+	for _, i := range t.Unsigned() {
+		if t.AddSignature(i, signatures[i]) == nil {
+			signed = append(signed, i)
+		}
+	}
+	return signed, nil
+}
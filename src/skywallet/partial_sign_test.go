@@ -0,0 +1,48 @@
+package skywallet
+
+import (
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/psbt"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func (suite *devicerSuit) TestSignPartialTransaction() {
+	// NOTE: Giving
+	keys, err := cipher.GenerateDeterministicKeyPairs([]byte("device seed"), 1)
+	suite.Nil(err)
+	owned := cipher.MustAddressFromSecKey(keys[0]).String()
+	tx := psbt.New([]psbt.Input{
+		{Hash: "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", Address: "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw", AddressIndex: 0},
+		{Hash: "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", Address: owned, AddressIndex: 0},
+	}, []psbt.Output{
+		{Address: "K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", Coins: 2000000, Hours: 4},
+	})
+	// the device signs both inputs with its own key
+	var signatures []string
+	for i := range tx.Inputs {
+		hash, hashErr := tx.SignedHash(i)
+		suite.Nil(hashErr)
+		signatures = append(signatures, cipher.MustSignHash(hash, keys[0]).Hex())
+	}
+	data, err := proto.Marshal(&messages.ResponseTransactionSign{Signatures: signatures, Padding: proto.Bool(true)})
+	suite.Nil(err)
+	inputs, outputs := PartialTransactionMessages(tx)
+	device := &MockDevicer{}
+	device.On("TransactionSign", inputs, outputs).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_ResponseTransactionSign), Data: data}, nil)
+
+	// NOTE: When
+	signed, err := SignPartialTransaction(device, tx, noRequests)
+
+	// NOTE: Assert
+	suite.Nil(err)
+	mock.AssertExpectationsForObjects(suite.T(), device)
+	require.Equal(suite.T(), []int{1}, signed)
+	require.Equal(suite.T(), []int{0}, tx.Unsigned())
+	require.Equal(suite.T(), signatures[1], tx.Inputs[1].Signature)
+}
@@ -0,0 +1,117 @@
+package skywallet
+
+import (
+	"bytes"
+	"errors"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/redact"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+var (
+	// ErrPassphraseStateMismatch is returned if the device reports a passphrase state
+	// different from the one of the previous session, meaning a different passphrase,
+	// and therefore a different hidden wallet, is in use
+	ErrPassphraseStateMismatch = errors.New("passphrase state does not match the previous session, a different passphrase is in use")
+)
+
+// PassphraseState returns the state blob reported by the device for the passphrase
+// in use or the one set with SetPassphraseState, nil if there is none yet
+func (d *Device) PassphraseState() []byte {
+	d.Lock()
+	defer d.Unlock()
+	return append([]byte(nil), d.passphraseState...)
+}
+
+// SetPassphraseState sets the state blob of a previous session, Initialize uses it
+// to resume the session and a device reporting a different state is refused with
+// ErrPassphraseStateMismatch. A nil state accepts any passphrase.
+func (d *Device) SetPassphraseState(state []byte) {
+	d.Lock()
+	defer d.Unlock()
+	d.passphraseState = append([]byte(nil), state...)
+	d.passphraseStateSet = len(state) != 0
+	if len(state) == 0 {
+		d.passphraseState = nil
+	}
+}
+
+// Initialize starts a new session returning the device Features. If a passphrase
+// state is set the device keeps the cached passphrase when it matches the state,
+// so the user is not prompted again for the same hidden wallet.
+func (d *Device) Initialize() (wire.Message, error) {
+	if err := d.Connect(); err != nil {
+		return wire.Message{}, err
+	}
+	defer d.Disconnect()
+
+	initializeChunks, err := MessageInitialize(d.PassphraseState())
+	if err != nil {
+		return wire.Message{}, err
+	}
+
+	return d.sendToDevice(initializeChunks)
+}
+
+// sendToDevice sends chunks to the device handling the passphrase state requests,
+// every Device method should use it instead of Driver.SendToDevice
+func (d *Device) sendToDevice(chunks [][64]byte) (wire.Message, error) {
+	msg, err := d.Driver.SendToDevice(d.dev, chunks)
+	if err != nil {
+		return wire.Message{}, err
+	}
+	return d.ackPassphraseState(msg)
+}
+
+// sendSecretToDevice is sendToDevice for messages carrying a mnemonic, a PIN,
+// a passphrase or a recovery word, the chunks are zeroed once sent
+func (d *Device) sendSecretToDevice(chunks [][64]byte) (wire.Message, error) {
+	defer func() {
+		for i := range chunks {
+			redact.Wipe(chunks[i][:])
+		}
+	}()
+	return d.sendToDevice(chunks)
+}
+
+// ackPassphraseState records the state of a PassphraseStateRequest and acknowledges it,
+// other messages are returned untouched. The state is only checked against the one
+// given to SetPassphraseState, the states reported before are not enforced.
+func (d *Device) ackPassphraseState(msg wire.Message) (wire.Message, error) {
+	if msg.Kind != uint16(messages.MessageType_MessageType_PassphraseStateRequest) {
+		return msg, nil
+	}
+
+	pm, err := decodeMsg(msg, messages.MessageType_MessageType_PassphraseStateRequest, "ackPassphraseState")
+	if err != nil {
+		return wire.Message{}, err
+	}
+	state := pm.(*messages.PassphraseStateRequest).GetState()
+
+	d.Lock()
+	mismatch := d.passphraseStateSet && !bytes.Equal(d.passphraseState, state)
+	if !mismatch {
+		d.passphraseState = append([]byte(nil), state...)
+	}
+	d.Unlock()
+
+	if mismatch {
+		// abort the ongoing operation, the device answers with a Failure
+		cancelChunks, err := MessageCancel()
+		if err != nil {
+			return wire.Message{}, err
+		}
+		if _, err := d.Driver.SendToDevice(d.dev, cancelChunks); err != nil {
+			return wire.Message{}, err
+		}
+		return wire.Message{}, ErrPassphraseStateMismatch
+	}
+
+	ackChunks, err := MessagePassphraseStateAck()
+	if err != nil {
+		return wire.Message{}, err
+	}
+	return d.sendToDevice(ackChunks)
+}
@@ -0,0 +1,136 @@
+package skywallet
+
+import (
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func passphraseStateRequest(state []byte) wire.Message {
+	data, err := proto.Marshal(&messages.PassphraseStateRequest{State: state})
+	if err != nil {
+		panic(err)
+	}
+	return wire.Message{Kind: uint16(messages.MessageType_MessageType_PassphraseStateRequest), Data: data}
+}
+
+func (suite *devicerSuit) TestPassphraseStateIsAcknowledged() {
+	// NOTE: Giving
+	state := []byte{1, 2, 3, 4}
+	passphraseChunks, err := MessagePassphraseAck("secret")
+	suite.Nil(err)
+	ackChunks, err := MessagePassphraseStateAck()
+	suite.Nil(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, passphraseChunks).Return(passphraseStateRequest(state), nil)
+	driverMock.On("SendToDevice", mock.Anything, ackChunks).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Success), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	msg, err := device.PassphraseAck("secret")
+
+	// NOTE: Assert
+	suite.Nil(err)
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 2)
+	mock.AssertExpectationsForObjects(suite.T(), driverMock)
+	require.Equal(suite.T(), uint16(messages.MessageType_MessageType_Success), msg.Kind)
+	require.Equal(suite.T(), state, device.PassphraseState())
+}
+
+func (suite *devicerSuit) TestReportedPassphraseStateIsNotEnforced() {
+	// NOTE: Giving
+	passphraseChunks, err := MessagePassphraseAck("secret")
+	suite.Nil(err)
+	otherChunks, err := MessagePassphraseAck("other secret")
+	suite.Nil(err)
+	ackChunks, err := MessagePassphraseStateAck()
+	suite.Nil(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, passphraseChunks).Return(passphraseStateRequest([]byte{1, 2, 3, 4}), nil)
+	driverMock.On("SendToDevice", mock.Anything, otherChunks).Return(passphraseStateRequest([]byte{5, 6}), nil)
+	driverMock.On("SendToDevice", mock.Anything, ackChunks).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Success), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	_, err = device.PassphraseAck("secret")
+	suite.Nil(err)
+	msg, err := device.PassphraseAck("other secret")
+
+	// NOTE: Assert
+	suite.Nil(err)
+	require.Equal(suite.T(), uint16(messages.MessageType_MessageType_Success), msg.Kind)
+	require.Equal(suite.T(), []byte{5, 6}, device.PassphraseState())
+}
+
+func (suite *devicerSuit) TestPassphraseStateMismatch() {
+	// NOTE: Giving
+	passphraseChunks, err := MessagePassphraseAck("other secret")
+	suite.Nil(err)
+	cancelChunks, err := MessageCancel()
+	suite.Nil(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, passphraseChunks).Return(passphraseStateRequest([]byte{5, 6}), nil)
+	driverMock.On("SendToDevice", mock.Anything, cancelChunks).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Failure), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+	device.SetPassphraseState([]byte{1, 2, 3, 4})
+
+	// NOTE: When
+	_, err = device.PassphraseAck("other secret")
+
+	// NOTE: Assert
+	require.Equal(suite.T(), ErrPassphraseStateMismatch, err)
+	mock.AssertExpectationsForObjects(suite.T(), driverMock)
+	require.Equal(suite.T(), []byte{1, 2, 3, 4}, device.PassphraseState())
+}
+
+func (suite *devicerSuit) TestInitializeResumesPassphraseState() {
+	// NOTE: Giving
+	state := []byte{1, 2, 3, 4}
+	initializeChunks, err := MessageInitialize(state)
+	suite.Nil(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, initializeChunks).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Features), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+	device.SetPassphraseState(state)
+
+	// NOTE: When
+	msg, err := device.Initialize()
+
+	// NOTE: Assert
+	suite.Nil(err)
+	mock.AssertExpectationsForObjects(suite.T(), driverMock)
+	require.Equal(suite.T(), uint16(messages.MessageType_MessageType_Features), msg.Kind)
+}
+
+func (suite *devicerSuit) TestSecretChunksAreWiped() {
+	// NOTE: Giving
+	var sent [][64]byte
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(1).([][64]byte)
+		require.NotEqual(suite.T(), [64]byte{}, sent[0])
+	}).Return(wire.Message{Kind: uint16(messages.MessageType_MessageType_Success), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	_, err := device.SetMnemonic("cloud flower upset remain green metal below cup stem infant art thank")
+
+	// NOTE: Assert
+	suite.Nil(err)
+	require.NotEmpty(suite.T(), sent)
+	for _, chunk := range sent {
+		require.Equal(suite.T(), [64]byte{}, chunk)
+	}
+}
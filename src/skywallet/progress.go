@@ -0,0 +1,111 @@
+package skywallet
+
+import "io"
+
+// ProgressReporter receives the progress of long running operations
+// like entropy download, firmware upload or address scanning
+type ProgressReporter interface {
+	// Start is called once the total amount of work is known
+	Start(total int)
+	// Update is called every time current units of work are completed
+	Update(current int)
+	// Done is called when the operation finishes
+	Done()
+}
+
+// NopProgressReporter discards all progress updates
+type NopProgressReporter struct{}
+
+// Start implements ProgressReporter
+func (NopProgressReporter) Start(total int) {}
+
+// Update implements ProgressReporter
+func (NopProgressReporter) Update(current int) {}
+
+// Done implements ProgressReporter
+func (NopProgressReporter) Done() {}
+
+// ProgressEventType is the kind of a ProgressEvent
+type ProgressEventType int32
+
+const (
+	// ProgressStart operation started
+	ProgressStart ProgressEventType = iota
+	// ProgressUpdate operation progressed
+	ProgressUpdate
+	// ProgressDone operation finished
+	ProgressDone
+)
+
+// ProgressEvent is sent by ChanProgressReporter for every progress notification
+type ProgressEvent struct {
+	Type    ProgressEventType
+	Current int
+	Total   int
+}
+
+// ChanProgressReporter sends progress updates over a channel,
+// useful for GUIs and daemons running the operation in another goroutine
+type ChanProgressReporter struct {
+	ch    chan<- ProgressEvent
+	total int
+}
+
+// NewChanProgressReporter creates a reporter sending events to ch,
+// sending blocks until the event is received so ch should be consumed or buffered
+func NewChanProgressReporter(ch chan<- ProgressEvent) *ChanProgressReporter {
+	return &ChanProgressReporter{ch: ch}
+}
+
+// Start implements ProgressReporter
+func (r *ChanProgressReporter) Start(total int) {
+	r.total = total
+	r.ch <- ProgressEvent{Type: ProgressStart, Total: total}
+}
+
+// Update implements ProgressReporter
+func (r *ChanProgressReporter) Update(current int) {
+	r.ch <- ProgressEvent{Type: ProgressUpdate, Current: current, Total: r.total}
+}
+
+// Done implements ProgressReporter
+func (r *ChanProgressReporter) Done() {
+	r.ch <- ProgressEvent{Type: ProgressDone, Current: r.total, Total: r.total}
+}
+
+// progressReader reports the bytes read from the underlying reader
+type progressReader struct {
+	r        io.Reader
+	reporter ProgressReporter
+	total    int
+	read     int
+	done     bool
+}
+
+// NewProgressReader wraps r reporting every read until total bytes have been read,
+// use it to track streaming operations like the one returned by EntropyReader
+func NewProgressReader(r io.Reader, total int, reporter ProgressReporter) io.Reader {
+	if reporter == nil {
+		reporter = NopProgressReporter{}
+	}
+	reporter.Start(total)
+	return &progressReader{
+		r:        r,
+		reporter: reporter,
+		total:    total,
+	}
+}
+
+// Read implements io.Reader
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		pr.read += n
+		pr.reporter.Update(pr.read)
+	}
+	if !pr.done && (pr.read >= pr.total || err != nil) {
+		pr.done = true
+		pr.reporter.Done()
+	}
+	return n, err
+}
@@ -1,41 +1,78 @@
 package skywallet
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
 
 const (
 	maxbars int = 100
 )
 
 // Progbar progress bar for cli command in the style:
+// [=================>                  ]  48.00% (48/100)
+// The zero value prints to os.Stderr.
 type Progbar struct {
+	w     io.Writer
 	total int
 }
 
+// NewProgbar creates a progress bar printing to w, usually os.Stderr
+// to avoid mixing the bar with the command output
+func NewProgbar(w io.Writer) *Progbar {
+	return &Progbar{w: w}
+}
+
+// Start implements ProgressReporter
+func (p *Progbar) Start(total int) {
+	p.total = total
+	p.PrintProg(0)
+}
+
+// Update implements ProgressReporter
+func (p *Progbar) Update(current int) {
+	p.PrintProg(current)
+}
+
+// Done implements ProgressReporter
+func (p *Progbar) Done() {
+	p.PrintComplete()
+}
+
 // PrintProg print the progress var for the portion value
 func (p *Progbar) PrintProg(portion int) {
 	bars := p.calcBars(portion)
 	spaces := maxbars - bars - 1
-	percent := 100 * (float32(portion) / float32(p.total))
-	fmt.Print("\r[")
-	for i := 0; i < bars; i++ {
-		fmt.Print("=")
+	percent := float32(100)
+	if p.total > 0 {
+		percent = 100 * (float32(portion) / float32(p.total))
 	}
-	fmt.Print(">")
-	for i := 0; i <= spaces; i++ {
-		fmt.Print(" ")
-	}
-	fmt.Printf(" ] %3.2f%% (%d/%d)", percent, portion, p.total)
+	fmt.Fprintf(p.writer(), "\r[%s>%s ] %3.2f%% (%d/%d)",
+		strings.Repeat("=", bars), strings.Repeat(" ", spaces+1), percent, portion, p.total)
 }
 
 // PrintComplete print the progress bar as completed
 func (p *Progbar) PrintComplete() {
 	p.PrintProg(p.total)
-	fmt.Print("\n")
+	fmt.Fprint(p.writer(), "\n")
+}
+
+// writer returns the writer the bar is printed to, os.Stderr if none was given
+func (p *Progbar) writer() io.Writer {
+	if p.w == nil {
+		return os.Stderr
+	}
+	return p.w
 }
 
 func (p *Progbar) calcBars(portion int) int {
-	if portion == 0 {
-		return portion
+	if portion <= 0 || p.total <= 0 {
+		return 0
+	}
+	if portion >= p.total {
+		return maxbars
 	}
 	return int(float32(maxbars) / (float32(p.total) / float32(portion)))
 }
@@ -0,0 +1,76 @@
+package skywallet
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type progressSuit struct {
+	suite.Suite
+}
+
+func TestProgressSuit(t *testing.T) {
+	suite.Run(t, new(progressSuit))
+}
+
+func (suite *progressSuit) TestProgressReaderReportsToChannel() {
+	// NOTE: Giving
+	events := make(chan ProgressEvent, 16)
+	reporter := NewChanProgressReporter(events)
+	r := NewProgressReader(strings.NewReader("0123456789"), 10, reporter)
+
+	// NOTE: When
+	buf := make([]byte, 4)
+	var err error
+	for err == nil {
+		_, err = r.Read(buf)
+	}
+	close(events)
+
+	// NOTE: Assert
+	suite.Equal(io.EOF, err)
+	var got []ProgressEvent
+	for e := range events {
+		got = append(got, e)
+	}
+	suite.Equal([]ProgressEvent{
+		{Type: ProgressStart, Total: 10},
+		{Type: ProgressUpdate, Current: 4, Total: 10},
+		{Type: ProgressUpdate, Current: 8, Total: 10},
+		{Type: ProgressUpdate, Current: 10, Total: 10},
+		{Type: ProgressDone, Current: 10, Total: 10},
+	}, got)
+}
+
+func (suite *progressSuit) TestProgbarWritesToWriter() {
+	// NOTE: Giving
+	var buf bytes.Buffer
+	pb := NewProgbar(&buf)
+
+	// NOTE: When
+	pb.Start(4)
+	pb.Update(2)
+	pb.Done()
+
+	// NOTE: Assert
+	out := buf.String()
+	suite.Contains(out, " 50.00% (2/4)")
+	suite.Contains(out, "100.00% (4/4)")
+	suite.True(strings.HasSuffix(out, "\n"))
+}
+
+func (suite *progressSuit) TestZeroProgbar() {
+	// NOTE: Giving
+	var pb Progbar
+
+	// NOTE: When
+	w := pb.writer()
+
+	// NOTE: Assert
+	suite.Equal(os.Stderr, w)
+}
@@ -0,0 +1,318 @@
+/*
+Package psbt holds partially signed Skycoin transactions, so that the inputs
+of a transaction owned by different wallets, for example two skywallets held
+by two officers, are signed one wallet at a time.
+
+A Transaction is saved as JSON. It records the inputs with the address owning
+them and its index in the owning wallet, the outputs and the signatures found
+so far. Merge combines the signatures of several copies and Encode serializes
+the transaction once all the inputs are signed.
+*/
+package psbt
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/base58"
+)
+
+// Version is the version of the file format written by this package
+const Version = 1
+
+var (
+	// ErrUnsupportedVersion is returned if a file was written by a newer version of this package
+	ErrUnsupportedVersion = fmt.Errorf("unsupported partially signed transaction version, at most %d is supported", Version)
+	// ErrDifferentTransactions is returned if the transactions merged are not the same
+	ErrDifferentTransactions = errors.New("partially signed transactions have different inputs or outputs")
+	// ErrNotSigned is returned if a transaction with unsigned inputs is encoded
+	ErrNotSigned = errors.New("transaction has unsigned inputs")
+	// ErrInvalidInputIndex is returned if an input index is out of range
+	ErrInvalidInputIndex = errors.New("input index out of range")
+)
+
+// Input is an unspent output spent by the transaction
+type Input struct {
+	Hash string `json:"hash"`
+	// Address owns the unspent output, the signature is checked against it
+	Address string `json:"address"`
+	// AddressIndex is the index of Address in the wallet owning it
+	AddressIndex uint32 `json:"address_index"`
+	// Signature is empty until the input is signed
+	Signature string `json:"signature,omitempty"`
+}
+
+// Output is an output of the transaction
+type Output struct {
+	Address string `json:"address"`
+	Coins   uint64 `json:"coins"`
+	Hours   uint64 `json:"hours"`
+	// AddressIndex is set if the output goes back to the wallet signing it
+	AddressIndex *uint32 `json:"address_index,omitempty"`
+}
+
+// Transaction is a partially signed transaction
+type Transaction struct {
+	Version int      `json:"version"`
+	Inputs  []Input  `json:"inputs"`
+	Outputs []Output `json:"outputs"`
+}
+
+// New returns a transaction without signatures
+func New(inputs []Input, outputs []Output) *Transaction {
+	t := &Transaction{
+		Version: Version,
+		Inputs:  append([]Input(nil), inputs...),
+		Outputs: append([]Output(nil), outputs...),
+	}
+	for i := range t.Inputs {
+		t.Inputs[i].Signature = ""
+	}
+	return t
+}
+
+// Decode reads a transaction written by Write
+func Decode(r io.Reader) (*Transaction, error) {
+	var t Transaction
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, err
+	}
+	if t.Version > Version {
+		return nil, ErrUnsupportedVersion
+	}
+	if _, err := t.InnerHash(); err != nil {
+		return nil, err
+	}
+	for i, in := range t.Inputs {
+		if in.Signature == "" {
+			continue
+		}
+		if err := t.verifySignature(i, in.Signature); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+// Write writes the transaction as indented JSON
+func (t *Transaction) Write(w io.Writer) error {
+	b, err := json.MarshalIndent(t, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// Load reads the transaction saved in the file at path
+func Load(path string) (*Transaction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f)
+}
+
+// Save writes the transaction to the file at path, replacing it
+func (t *Transaction) Save(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err = t.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// InnerHash returns the hash of the inputs and outputs the signatures commit to
+func (t *Transaction) InnerHash() (cipher.SHA256, error) {
+	b, err := t.encodeBody()
+	if err != nil {
+		return cipher.SHA256{}, err
+	}
+	return cipher.SumSHA256(b), nil
+}
+
+// SignedHash returns the hash signed for the input at index i
+func (t *Transaction) SignedHash(i int) (cipher.SHA256, error) {
+	if i < 0 || i >= len(t.Inputs) {
+		return cipher.SHA256{}, ErrInvalidInputIndex
+	}
+	inner, err := t.InnerHash()
+	if err != nil {
+		return cipher.SHA256{}, err
+	}
+	hash, err := cipher.SHA256FromHex(t.Inputs[i].Hash)
+	if err != nil {
+		return cipher.SHA256{}, err
+	}
+	return cipher.AddSHA256(inner, hash), nil
+}
+
+// AddSignature sets the signature of the input at index i if it is the
+// signature of the input address, see ParseSignature for its formats
+func (t *Transaction) AddSignature(i int, signature string) error {
+	sig, err := ParseSignature(signature)
+	if err != nil {
+		return fmt.Errorf("input %d: %v", i+1, err)
+	}
+	if err = t.verifySignature(i, sig.Hex()); err != nil {
+		return err
+	}
+	t.Inputs[i].Signature = sig.Hex()
+	return nil
+}
+
+// ParseSignature decodes a signature written in hex or in base58, as the device returns it
+func ParseSignature(signature string) (cipher.Sig, error) {
+	if sig, err := cipher.SigFromHex(signature); err == nil {
+		return sig, nil
+	}
+	b, err := base58.Decode(signature)
+	if err != nil {
+		return cipher.Sig{}, fmt.Errorf("signature is neither hex nor base58: %v", err)
+	}
+	return cipher.NewSig(b)
+}
+
+// verifySignature checks signature is the signature of input i by its address
+func (t *Transaction) verifySignature(i int, signature string) error {
+	hash, err := t.SignedHash(i)
+	if err != nil {
+		return err
+	}
+	sig, err := cipher.SigFromHex(signature)
+	if err != nil {
+		return fmt.Errorf("input %d: %v", i+1, err)
+	}
+	address, err := cipher.DecodeBase58Address(t.Inputs[i].Address)
+	if err != nil {
+		return fmt.Errorf("input %d: %v", i+1, err)
+	}
+	if err = cipher.VerifyAddressSignedHash(address, sig, hash); err != nil {
+		return fmt.Errorf("input %d: %v", i+1, err)
+	}
+	return nil
+}
+
+// Unsigned returns the indexes of the inputs without signature
+func (t *Transaction) Unsigned() []int {
+	var unsigned []int
+	for i, in := range t.Inputs {
+		if in.Signature == "" {
+			unsigned = append(unsigned, i)
+		}
+	}
+	return unsigned
+}
+
+// Complete tells if all the inputs are signed
+func (t *Transaction) Complete() bool {
+	return len(t.Unsigned()) == 0
+}
+
+// Merge copies the signatures of other, which must have the same inputs and outputs
+func (t *Transaction) Merge(other *Transaction) error {
+	hash, err := t.InnerHash()
+	if err != nil {
+		return err
+	}
+	otherHash, err := other.InnerHash()
+	if err != nil {
+		return err
+	}
+	if hash != otherHash || len(t.Inputs) != len(other.Inputs) {
+		return ErrDifferentTransactions
+	}
+	for i, in := range other.Inputs {
+		if in.Signature == "" || t.Inputs[i].Signature != "" {
+			continue
+		}
+		if err = t.AddSignature(i, in.Signature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encode serializes the signed transaction as the Skycoin nodes expect it
+func (t *Transaction) Encode() ([]byte, error) {
+	if !t.Complete() {
+		return nil, ErrNotSigned
+	}
+	body, err := t.encodeBody()
+	if err != nil {
+		return nil, err
+	}
+	inner := cipher.SumSHA256(body)
+
+	// length, type, inner hash, signatures then the body
+	length := 4 + 1 + len(inner) + 4 + len(t.Inputs)*len(cipher.Sig{}) + len(body)
+	b := make([]byte, 0, length)
+	b = appendUint32(b, uint32(length))
+	b = append(b, 0)
+	b = append(b, inner[:]...)
+	b = appendUint32(b, uint32(len(t.Inputs)))
+	for i, in := range t.Inputs {
+		var sig cipher.Sig
+		sig, err = cipher.SigFromHex(in.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %v", i+1, err)
+		}
+		b = append(b, sig[:]...)
+	}
+	return append(b, body...), nil
+}
+
+// ID returns the id of the signed transaction, the hash of its encoding
+func (t *Transaction) ID() (cipher.SHA256, error) {
+	b, err := t.Encode()
+	if err != nil {
+		return cipher.SHA256{}, err
+	}
+	return cipher.SumSHA256(b), nil
+}
+
+// encodeBody serializes the inputs and the outputs
+func (t *Transaction) encodeBody() ([]byte, error) {
+	b := appendUint32(nil, uint32(len(t.Inputs)))
+	for i, in := range t.Inputs {
+		hash, err := cipher.SHA256FromHex(in.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %v", i+1, err)
+		}
+		b = append(b, hash[:]...)
+	}
+	b = appendUint32(b, uint32(len(t.Outputs)))
+	for i, out := range t.Outputs {
+		address, err := cipher.DecodeBase58Address(out.Address)
+		if err != nil {
+			return nil, fmt.Errorf("output %d: %v", i+1, err)
+		}
+		b = append(b, address.Version)
+		b = append(b, address.Key[:]...)
+		b = appendUint64(b, out.Coins)
+		b = appendUint64(b, out.Hours)
+	}
+	return b, nil
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
@@ -0,0 +1,111 @@
+package psbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/base58"
+	"github.com/stretchr/testify/require"
+)
+
+// officer returns the address and secret key of a wallet of seed
+func officer(t *testing.T, seed string) (string, cipher.SecKey) {
+	keys, err := cipher.GenerateDeterministicKeyPairs([]byte(seed), 1)
+	require.NoError(t, err)
+	return cipher.MustAddressFromSecKey(keys[0]).String(), keys[0]
+}
+
+func sign(t *testing.T, tx *Transaction, i int, key cipher.SecKey) string {
+	hash, err := tx.SignedHash(i)
+	require.NoError(t, err)
+	sig, err := cipher.SignHash(hash, key)
+	require.NoError(t, err)
+	return sig.Hex()
+}
+
+func twoOfficers(t *testing.T) (*Transaction, cipher.SecKey, cipher.SecKey) {
+	first, firstKey := officer(t, "first officer")
+	second, secondKey := officer(t, "second officer")
+	tx := New([]Input{
+		{Hash: "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59", Address: first, AddressIndex: 0},
+		{Hash: "ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da", Address: second, AddressIndex: 3},
+	}, []Output{
+		{Address: "K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", Coins: 2000000, Hours: 4},
+	})
+	return tx, firstKey, secondKey
+}
+
+func TestSignedHash(t *testing.T) {
+	// hash signed by the emulator for this transaction in the cli integration tests
+	tx := New([]Input{{Hash: "181bd5656115172fe81451fae4fb56498a97744d89702e73da75ba91ed5200f9", Address: "2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"}},
+		[]Output{{Address: "K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot", Coins: 100000, Hours: 2}})
+	hash, err := tx.SignedHash(0)
+	require.NoError(t, err)
+	require.Equal(t, "d11c62b1e0e9abf629b1f5f4699cef9fbc504b45ceedf0047ead686979498218", hash.Hex())
+
+	_, err = tx.SignedHash(1)
+	require.Equal(t, ErrInvalidInputIndex, err)
+}
+
+func TestAddSignature(t *testing.T) {
+	tx, firstKey, secondKey := twoOfficers(t)
+
+	// the key of the second officer does not own the first input
+	require.Error(t, tx.AddSignature(0, sign(t, tx, 0, secondKey)))
+	require.Equal(t, []int{0, 1}, tx.Unsigned())
+
+	sig, err := cipher.SigFromHex(sign(t, tx, 0, firstKey))
+	require.NoError(t, err)
+	require.NoError(t, tx.AddSignature(0, base58.Encode(sig[:])))
+	require.Equal(t, sig.Hex(), tx.Inputs[0].Signature)
+	require.Equal(t, []int{1}, tx.Unsigned())
+	require.False(t, tx.Complete())
+
+	_, err = tx.Encode()
+	require.Equal(t, ErrNotSigned, err)
+}
+
+func TestMergeAndEncode(t *testing.T) {
+	first, firstKey, secondKey := twoOfficers(t)
+	var file bytes.Buffer
+	require.NoError(t, first.Write(&file))
+	second, err := Decode(bytes.NewReader(file.Bytes()))
+	require.NoError(t, err)
+
+	require.NoError(t, first.AddSignature(0, sign(t, first, 0, firstKey)))
+	require.NoError(t, second.AddSignature(1, sign(t, second, 1, secondKey)))
+	require.NoError(t, first.Merge(second))
+	require.True(t, first.Complete())
+
+	b, err := first.Encode()
+	require.NoError(t, err)
+	require.Equal(t, uint32(len(b)), binary.LittleEndian.Uint32(b))
+	inner, err := first.InnerHash()
+	require.NoError(t, err)
+	require.Equal(t, inner[:], b[5:37])
+	id, err := first.ID()
+	require.NoError(t, err)
+	require.Equal(t, cipher.SumSHA256(b), id)
+
+	other, _, _ := twoOfficers(t)
+	other.Outputs[0].Hours = 3
+	require.Equal(t, ErrDifferentTransactions, first.Merge(other))
+}
+
+func TestDecode(t *testing.T) {
+	tx, firstKey, secondKey := twoOfficers(t)
+	tx.Inputs[0].Signature = sign(t, tx, 0, secondKey)
+	var file bytes.Buffer
+	require.NoError(t, tx.Write(&file))
+	_, err := Decode(&file)
+	require.Error(t, err)
+
+	tx.Inputs[0].Signature = sign(t, tx, 0, firstKey)
+	tx.Version = Version + 1
+	file.Reset()
+	require.NoError(t, tx.Write(&file))
+	_, err = Decode(&file)
+	require.Equal(t, ErrUnsupportedVersion, err)
+}
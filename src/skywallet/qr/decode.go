@@ -0,0 +1,130 @@
+package qr
+
+import (
+	"math/bits"
+)
+
+// FromModules returns the code made of modules, indexed by row then column,
+// true when dark
+func FromModules(modules [][]bool) (*Code, error) {
+	size := len(modules)
+	version := (size - 17) / 4
+	if version < 1 || version > MaxVersion || version*4+17 != size {
+		return nil, ErrInvalidSize
+	}
+	for _, row := range modules {
+		if len(row) != size {
+			return nil, ErrInvalidSize
+		}
+	}
+
+	c := newCode(version)
+	for y, row := range modules {
+		copy(c.modules[y], row)
+	}
+	mask, err := c.readMask()
+	if err != nil {
+		return nil, err
+	}
+	c.Mask = mask
+	return c, nil
+}
+
+// readMask returns the mask of the format information, the closest valid
+// format among both copies, so that one of them may be damaged
+func (c *Code) readMask() (int, error) {
+	first, second := c.formatPositions()
+	best, bestDistance := -1, 0
+	for _, positions := range [][15][2]int{first, second} {
+		read := 0
+		for i, p := range positions {
+			if c.modules[p[1]][p[0]] {
+				read |= 1 << uint(i)
+			}
+		}
+		for mask := 0; mask < 8; mask++ {
+			distance := bits.OnesCount(uint(read ^ formatBits(mask)))
+			if best < 0 || distance < bestDistance {
+				best, bestDistance = mask, distance
+			}
+		}
+	}
+	// the format bits of two masks differ by at least 7 bits
+	if bestDistance > 3 {
+		return 0, ErrFormat
+	}
+	return best, nil
+}
+
+// Decode returns the bytes held by the code, correcting the damaged codewords
+func Decode(c *Code) ([]byte, error) {
+	b := levelM[c.Version]
+	positions := c.dataPositions()
+	codewords := make([]byte, b.dataCodewords()+(b.short+b.long)*b.ecLen)
+	for i, p := range positions {
+		if i/8 >= len(codewords) {
+			break
+		}
+		x, y := p[0], p[1]
+		if c.modules[y][x] != masked(c.Mask, x, y) {
+			codewords[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+
+	// deinterleave the blocks, the data then the error correction codewords
+	count := b.short + b.long
+	blocks := make([][]byte, count)
+	next := 0
+	for i := 0; i <= b.dataLen; i++ {
+		for j := range blocks {
+			if i < b.dataLen || j >= b.short {
+				blocks[j] = append(blocks[j], codewords[next])
+				next++
+			}
+		}
+	}
+	for i := 0; i < b.ecLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], codewords[next])
+			next++
+		}
+	}
+
+	var data []byte
+	for _, block := range blocks {
+		if _, err := correct(block, b.ecLen); err != nil {
+			return nil, err
+		}
+		data = append(data, block[:len(block)-b.ecLen]...)
+	}
+	return parseSegment(c.Version, data)
+}
+
+// parseSegment returns the bytes of the byte mode segment starting data
+func parseSegment(version int, data []byte) ([]byte, error) {
+	pos := 0
+	readBits := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v <<= 1
+			if pos/8 < len(data) && data[pos/8]>>uint(7-pos%8)&1 != 0 {
+				v |= 1
+			}
+			pos++
+		}
+		return v
+	}
+
+	if readBits(4) != 0x4 {
+		return nil, ErrUnsupportedMode
+	}
+	n := readBits(countBits(version))
+	if pos+n*8 > len(data)*8 {
+		return nil, ErrTooManyErrors
+	}
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = byte(readBits(8))
+	}
+	return out, nil
+}
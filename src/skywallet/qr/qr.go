@@ -0,0 +1,461 @@
+/*
+Package qr encodes and decodes QR codes in byte mode, so that payloads can be
+shown on a terminal and read back without a network connection.
+
+Only the versions 1 to MaxVersion with the error correction level M are
+supported, about 15% of the codewords can be damaged. Decode reads the
+modules of a code, such as a code parsed from its terminal rendering by
+ParseTerminal, no camera is needed to test a round trip.
+*/
+package qr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxVersion is the highest version supported, a code of 57x57 modules
+const MaxVersion = 10
+
+// MaxBytes is the number of bytes a code of MaxVersion holds
+var MaxBytes = capacity(MaxVersion)
+
+var (
+	// ErrTooLong is returned if the data does not fit in a code of MaxVersion
+	ErrTooLong = fmt.Errorf("qr: data longer than %d bytes", MaxBytes)
+	// ErrInvalidSize is returned if the size of a code does not match a supported version
+	ErrInvalidSize = errors.New("qr: size does not match a supported version")
+	// ErrFormat is returned if the format information of a code cannot be read
+	ErrFormat = errors.New("qr: unreadable format information")
+	// ErrUnsupportedMode is returned if a code does not hold bytes
+	ErrUnsupportedMode = errors.New("qr: only the byte mode is supported")
+)
+
+// formatLevelM are the format bits of the error correction level M
+const formatLevelM = 0
+
+// blocks describes the error correction blocks of a version with level M
+type blocks struct {
+	// ecLen is the number of error correction codewords of each block
+	ecLen int
+	// short and long are the numbers of blocks with dataLen and dataLen+1 data codewords
+	short, long int
+	dataLen     int
+}
+
+// levelM are the blocks of the versions 1 to MaxVersion, from ISO/IEC 18004 table 9
+var levelM = [MaxVersion + 1]blocks{
+	1:  {10, 1, 0, 16},
+	2:  {16, 1, 0, 28},
+	3:  {26, 1, 0, 44},
+	4:  {18, 2, 0, 32},
+	5:  {24, 2, 0, 43},
+	6:  {16, 4, 0, 27},
+	7:  {18, 4, 0, 31},
+	8:  {22, 2, 2, 38},
+	9:  {22, 3, 2, 36},
+	10: {26, 4, 1, 43},
+}
+
+// alignments are the centers of the alignment patterns of the versions 2 to MaxVersion
+var alignments = [MaxVersion + 1][]int{
+	2:  {6, 18},
+	3:  {6, 22},
+	4:  {6, 26},
+	5:  {6, 30},
+	6:  {6, 34},
+	7:  {6, 22, 38},
+	8:  {6, 24, 42},
+	9:  {6, 26, 46},
+	10: {6, 28, 50},
+}
+
+func (b blocks) dataCodewords() int {
+	return b.short*b.dataLen + b.long*(b.dataLen+1)
+}
+
+// capacity returns the number of bytes held by a code of version
+func capacity(version int) int {
+	bits := levelM[version].dataCodewords()*8 - 4 - countBits(version)
+	return bits / 8
+}
+
+// countBits returns the length of the byte count of version
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// Code is a QR code, the modules are true when dark
+type Code struct {
+	Version int
+	Size    int
+	Mask    int
+	modules [][]bool
+	// function marks the modules of the patterns, not holding data
+	function [][]bool
+}
+
+// Dark tells if the module at column x and row y is dark
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// newCode returns a code of version with its function patterns drawn
+func newCode(version int) *Code {
+	size := version*4 + 17
+	c := &Code{
+		Version:  version,
+		Size:     size,
+		modules:  make([][]bool, size),
+		function: make([][]bool, size),
+	}
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.function[i] = make([]bool, size)
+	}
+
+	for i := 0; i < size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+	c.drawFinder(3, 3)
+	c.drawFinder(size-4, 3)
+	c.drawFinder(3, size-4)
+	positions := alignments[version]
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// the corners overlap the finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignment(x, y)
+		}
+	}
+	// reserve the format modules, drawn once the mask is chosen
+	c.drawFormat(0)
+	c.drawVersion()
+	return c
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= c.Size || yy < 0 || yy >= c.Size {
+				continue
+			}
+			dist := maxInt(absInt(dx), absInt(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, maxInt(absInt(dx), absInt(dy)) != 1)
+		}
+	}
+}
+
+// formatBits returns the 15 bits of format information of mask with level M
+func formatBits(mask int) int {
+	data := formatLevelM<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// formatPositions returns the positions of the 15 format bits in both copies
+func (c *Code) formatPositions() (first, second [15][2]int) {
+	for i := 0; i <= 5; i++ {
+		first[i] = [2]int{8, i}
+	}
+	first[6] = [2]int{8, 7}
+	first[7] = [2]int{8, 8}
+	first[8] = [2]int{7, 8}
+	for i := 9; i < 15; i++ {
+		first[i] = [2]int{14 - i, 8}
+	}
+	for i := 0; i < 8; i++ {
+		second[i] = [2]int{c.Size - 1 - i, 8}
+	}
+	for i := 8; i < 15; i++ {
+		second[i] = [2]int{8, c.Size - 15 + i}
+	}
+	return first, second
+}
+
+func (c *Code) drawFormat(mask int) {
+	bits := formatBits(mask)
+	first, second := c.formatPositions()
+	for i := 0; i < 15; i++ {
+		dark := bits>>uint(i)&1 != 0
+		c.setFunction(first[i][0], first[i][1], dark)
+		c.setFunction(second[i][0], second[i][1], dark)
+	}
+	c.setFunction(8, c.Size-8, true)
+}
+
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	rem := c.Version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1f25
+	}
+	bits := c.Version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := bits>>uint(i)&1 != 0
+		a := c.Size - 11 + i%3
+		b := i / 3
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+// dataPositions returns the positions of the data modules in the order the
+// codeword bits are placed, in columns of two from the bottom right corner
+func (c *Code) dataPositions() [][2]int {
+	var positions [][2]int
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// skip the vertical timing pattern
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				upward := (right+1)&2 == 0
+				y := vert
+				if upward {
+					y = c.Size - 1 - vert
+				}
+				if !c.function[y][x] {
+					positions = append(positions, [2]int{x, y})
+				}
+			}
+		}
+	}
+	return positions
+}
+
+// masked tells if the module at x, y is inverted by mask
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.function[y][x] && masked(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// Encode returns the smallest code holding data
+func Encode(data []byte) (*Code, error) {
+	version := 1
+	for version <= MaxVersion && capacity(version) < len(data) {
+		version++
+	}
+	if version > MaxVersion {
+		return nil, ErrTooLong
+	}
+
+	codewords := interleave(version, dataCodewords(version, data))
+	c := newCode(version)
+	for i, p := range c.dataPositions() {
+		if i/8 < len(codewords) {
+			c.modules[p[1]][p[0]] = codewords[i/8]>>uint(7-i%8)&1 != 0
+		}
+	}
+
+	best := -1
+	bestPenalty := 0
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormat(mask)
+		if p := c.penalty(); best < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		// masks are their own inverse
+		c.applyMask(mask)
+	}
+	c.Mask = best
+	c.applyMask(best)
+	c.drawFormat(best)
+	return c, nil
+}
+
+// dataCodewords returns the byte mode segment of data padded to the data
+// codewords of version
+func dataCodewords(version int, data []byte) []byte {
+	var bits []bool
+	appendBits := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, v>>uint(i)&1 != 0)
+		}
+	}
+	appendBits(0x4, 4)
+	appendBits(len(data), countBits(version))
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+
+	total := levelM[version].dataCodewords() * 8
+	terminator := total - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	appendBits(0, terminator)
+	appendBits(0, (8-len(bits)%8)%8)
+	for pad := 0xec; len(bits) < total; pad ^= 0xec ^ 0x11 {
+		appendBits(pad, 8)
+	}
+
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+	return codewords
+}
+
+// splitBlocks splits the data codewords of version into its blocks
+func splitBlocks(version int, data []byte) [][]byte {
+	b := levelM[version]
+	split := make([][]byte, 0, b.short+b.long)
+	for i := 0; i < b.short+b.long; i++ {
+		n := b.dataLen
+		if i >= b.short {
+			n++
+		}
+		split = append(split, data[:n])
+		data = data[n:]
+	}
+	return split
+}
+
+// interleave adds the error correction codewords to the blocks and
+// interleaves the codewords of all the blocks
+func interleave(version int, data []byte) []byte {
+	b := levelM[version]
+	split := splitBlocks(version, data)
+	var codewords []byte
+	for i := 0; i <= b.dataLen; i++ {
+		for _, block := range split {
+			if i < len(block) {
+				codewords = append(codewords, block[i])
+			}
+		}
+	}
+	ec := make([][]byte, len(split))
+	for i, block := range split {
+		ec[i] = ecCodewords(block, b.ecLen)
+	}
+	for i := 0; i < b.ecLen; i++ {
+		for _, block := range ec {
+			codewords = append(codewords, block[i])
+		}
+	}
+	return codewords
+}
+
+// penalty scores how hard the code is to scan, lower is better
+func (c *Code) penalty() int {
+	p := 0
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			// blocks of 2x2 modules of the same color
+			if x+1 < c.Size && y+1 < c.Size {
+				m := c.modules[y][x]
+				if m == c.modules[y][x+1] && m == c.modules[y+1][x] && m == c.modules[y+1][x+1] {
+					p += 3
+				}
+			}
+		}
+	}
+	for i := 0; i < c.Size; i++ {
+		p += c.runPenalty(func(j int) bool { return c.modules[i][j] })
+		p += c.runPenalty(func(j int) bool { return c.modules[j][i] })
+	}
+	// distance of the dark share from 50%, by steps of 5%
+	total := c.Size * c.Size
+	p += absInt(dark*20-total*10) / total * 10
+	return p
+}
+
+// runPenalty scores the runs of 5 modules or more of the same color and the
+// patterns looking like a finder along a row or a column
+func (c *Code) runPenalty(module func(int) bool) int {
+	p := 0
+	run := 1
+	for j := 1; j <= c.Size; j++ {
+		if j < c.Size && module(j) == module(j-1) {
+			run++
+			continue
+		}
+		if run >= 5 {
+			p += run - 2
+		}
+		run = 1
+	}
+	finder := []bool{true, false, true, true, true, false, true}
+	for j := 0; j+len(finder) <= c.Size; j++ {
+		match := true
+		for k, f := range finder {
+			if module(j+k) != f {
+				match = false
+				break
+			}
+		}
+		if match {
+			p += 40
+		}
+	}
+	return p
+}
+
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,112 @@
+package qr
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for version := 1; version <= MaxVersion; version++ {
+		data := make([]byte, capacity(version))
+		r.Read(data)
+
+		c, err := Encode(data)
+		require.NoError(t, err)
+		require.Equal(t, version, c.Version)
+		require.Equal(t, version*4+17, c.Size)
+
+		decoded, err := Decode(c)
+		require.NoError(t, err)
+		require.Equal(t, data, decoded)
+	}
+
+	_, err := Encode(make([]byte, MaxBytes+1))
+	require.Equal(t, ErrTooLong, err)
+}
+
+func TestCodewordCount(t *testing.T) {
+	// the data modules hold exactly the codewords, up to 7 remainder bits
+	for version := 1; version <= MaxVersion; version++ {
+		b := levelM[version]
+		codewords := b.dataCodewords() + (b.short+b.long)*b.ecLen
+		modules := len(newCode(version).dataPositions())
+		require.True(t, modules-codewords*8 >= 0 && modules-codewords*8 < 8, "version %d", version)
+	}
+}
+
+func TestKnownCode(t *testing.T) {
+	// format information of the level M with the mask 5, from ISO/IEC 18004 table C.1
+	require.Equal(t, 0x40ce, formatBits(5))
+	// error correction codewords of "01234567" in numeric mode, version 1-M,
+	// from ISO/IEC 18004 annex I
+	data := []byte{0x10, 0x20, 0x0c, 0x56, 0x61, 0x80, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11}
+	ec := []byte{0xa5, 0x24, 0xd4, 0xc1, 0xed, 0x36, 0xc7, 0x87, 0x2c, 0x55}
+	require.Equal(t, ec, ecCodewords(data, 10))
+}
+
+func TestCorrect(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	data := make([]byte, 43)
+	r.Read(data)
+	block := append(append([]byte(nil), data...), ecCodewords(data, 26)...)
+
+	for errs := 0; errs <= 13; errs++ {
+		damaged := append([]byte(nil), block...)
+		for _, i := range r.Perm(len(block))[:errs] {
+			damaged[i] ^= byte(r.Intn(255) + 1)
+		}
+		n, err := correct(damaged, 26)
+		require.NoError(t, err, "%d errors", errs)
+		require.Equal(t, errs, n)
+		require.Equal(t, block, damaged)
+	}
+
+	damaged := append([]byte(nil), block...)
+	for _, i := range r.Perm(len(block))[:20] {
+		damaged[i] ^= 0xff
+	}
+	_, err := correct(damaged, 26)
+	require.Error(t, err)
+}
+
+func TestDecodeDamaged(t *testing.T) {
+	data := bytes.Repeat([]byte("skywallet"), 20)
+	c, err := Encode(data)
+	require.NoError(t, err)
+
+	// flip a few data modules and the first copy of the format information
+	positions := c.dataPositions()
+	for _, i := range []int{0, 9, 100, 500, 1000} {
+		p := positions[i]
+		c.modules[p[1]][p[0]] = !c.modules[p[1]][p[0]]
+	}
+	for i := 0; i < 6; i++ {
+		c.modules[i][8] = !c.modules[i][8]
+	}
+	read, err := FromModules(c.modules)
+	require.NoError(t, err)
+	require.Equal(t, c.Mask, read.Mask)
+	decoded, err := Decode(read)
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+}
+
+func TestTerminal(t *testing.T) {
+	for _, data := range [][]byte{[]byte("SKY"), bytes.Repeat([]byte{0, 0xff}, 100)} {
+		c, err := Encode(data)
+		require.NoError(t, err)
+
+		read, err := ParseTerminal(c.Terminal())
+		require.NoError(t, err)
+		decoded, err := Decode(read)
+		require.NoError(t, err)
+		require.Equal(t, data, decoded)
+	}
+
+	_, err := ParseTerminal("█████\n█████\n")
+	require.Equal(t, ErrInvalidSize, err)
+}
@@ -0,0 +1,225 @@
+package qr
+
+import (
+	"errors"
+)
+
+// ErrTooManyErrors is returned if a block has more errors than its error correction codewords can fix
+var ErrTooManyErrors = errors.New("qr: too many errors to correct")
+
+// exp and log tables of GF(2^8) with the QR polynomial x^8 + x^4 + x^3 + x^2 + 1
+// and the generator 2
+var (
+	expTable [255]byte
+	logTable [256]byte
+)
+
+func init() {
+	x := 1
+	for i := range expTable {
+		expTable[i] = byte(x)
+		logTable[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+}
+
+// mul multiplies a and b in GF(2^8)
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])+int(logTable[b]))%255]
+}
+
+// div divides a by b in GF(2^8), b must not be 0
+func div(a, b byte) byte {
+	if b == 0 {
+		panic("qr: division by zero")
+	}
+	if a == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])-int(logTable[b])+255)%255]
+}
+
+// pow returns the generator raised to the power e
+func pow(e int) byte {
+	return expTable[((e%255)+255)%255]
+}
+
+// generator returns the coefficients of (x - a^0)(x - a^1)...(x - a^(degree-1))
+// from the highest degree, without the leading 1
+func generator(degree int) []byte {
+	g := make([]byte, degree)
+	g[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		// multiply by (x - root)
+		for j := range g {
+			g[j] = mul(g[j], root)
+			if j+1 < len(g) {
+				g[j] ^= g[j+1]
+			}
+		}
+		root = mul(root, 2)
+	}
+	return g
+}
+
+// ecCodewords returns the error correction codewords of data, the remainder
+// of data * x^n divided by the generator polynomial of degree n
+func ecCodewords(data []byte, n int) []byte {
+	g := generator(n)
+	rem := make([]byte, n)
+	for _, b := range data {
+		factor := b ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for i := range rem {
+			rem[i] ^= mul(g[i], factor)
+		}
+	}
+	return rem
+}
+
+// evaluate returns the value at x of the polynomial p, coefficients from the highest degree
+func evaluate(p []byte, x byte) byte {
+	y := byte(0)
+	for _, c := range p {
+		y = mul(y, x) ^ c
+	}
+	return y
+}
+
+// correct fixes in place the errors of block, data followed by n error
+// correction codewords, and returns the number of errors corrected
+func correct(block []byte, n int) (int, error) {
+	// syndromes S_i = block(a^i)
+	syndromes := make([]byte, n)
+	clean := true
+	for i := range syndromes {
+		syndromes[i] = evaluate(block, pow(i))
+		if syndromes[i] != 0 {
+			clean = false
+		}
+	}
+	if clean {
+		return 0, nil
+	}
+
+	locator := berlekampMassey(syndromes)
+	errs := len(locator) - 1
+	if errs*2 > n {
+		return 0, ErrTooManyErrors
+	}
+
+	// Chien search: the roots of the locator are the inverses of the error locations
+	var positions []int
+	for j := range block {
+		// the codeword j is the coefficient of x^(len-1-j)
+		e := len(block) - 1 - j
+		if evaluateLow(locator, pow(-e)) == 0 {
+			positions = append(positions, j)
+		}
+	}
+	if len(positions) != errs {
+		return 0, ErrTooManyErrors
+	}
+
+	// Forney: the error evaluator is S(x) * locator(x) mod x^n
+	evaluator := make([]byte, n)
+	for i := 0; i < n; i++ {
+		for k := 0; k <= i && k < len(locator); k++ {
+			evaluator[i] ^= mul(syndromes[i-k], locator[k])
+		}
+	}
+	for _, j := range positions {
+		e := len(block) - 1 - j
+		xInv := pow(-e)
+		// formal derivative of the locator, only the odd terms remain
+		derivative := byte(0)
+		for k := 1; k < len(locator); k += 2 {
+			derivative ^= mul(locator[k], powOf(xInv, k-1))
+		}
+		if derivative == 0 {
+			return 0, ErrTooManyErrors
+		}
+		magnitude := mul(pow(e), div(evaluateLow(evaluator, xInv), derivative))
+		block[j] ^= magnitude
+	}
+
+	for i := range syndromes {
+		if evaluate(block, pow(i)) != 0 {
+			return 0, ErrTooManyErrors
+		}
+	}
+	return errs, nil
+}
+
+// berlekampMassey returns the error locator polynomial of the syndromes,
+// coefficients from the lowest degree
+func berlekampMassey(syndromes []byte) []byte {
+	locator := []byte{1}
+	previous := []byte{1}
+	l := 0
+	m := 1
+	b := byte(1)
+	for i := range syndromes {
+		// discrepancy
+		d := syndromes[i]
+		for k := 1; k <= l && k < len(locator); k++ {
+			d ^= mul(locator[k], syndromes[i-k])
+		}
+		if d == 0 {
+			m++
+			continue
+		}
+		coef := div(d, b)
+		next := make([]byte, maxInt(len(locator), len(previous)+m))
+		copy(next, locator)
+		for k, p := range previous {
+			next[k+m] ^= mul(coef, p)
+		}
+		if 2*l <= i {
+			previous = locator
+			l = i + 1 - l
+			b = d
+			m = 1
+		} else {
+			m++
+		}
+		locator = next
+	}
+	for len(locator) < l+1 {
+		locator = append(locator, 0)
+	}
+	return locator[:l+1]
+}
+
+// evaluateLow returns the value at x of the polynomial p, coefficients from the lowest degree
+func evaluateLow(p []byte, x byte) byte {
+	y := byte(0)
+	for i := len(p) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ p[i]
+	}
+	return y
+}
+
+// powOf returns x raised to the power e
+func powOf(x byte, e int) byte {
+	y := byte(1)
+	for i := 0; i < e; i++ {
+		y = mul(y, x)
+	}
+	return y
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
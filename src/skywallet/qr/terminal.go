@@ -0,0 +1,97 @@
+package qr
+
+import (
+	"strings"
+)
+
+// quietZone is the number of light modules around a code on a terminal
+const quietZone = 2
+
+// the light modules are drawn, so that the code reads on the dark background
+// of most terminals, two rows of modules per line of text
+const (
+	bothLight   = '█'
+	topLight    = '▀'
+	bottomLight = '▄'
+	bothDark    = ' '
+)
+
+// Terminal renders the code with half block characters
+func (c *Code) Terminal() string {
+	light := func(x, y int) bool {
+		x -= quietZone
+		y -= quietZone
+		if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+			return true
+		}
+		return !c.modules[y][x]
+	}
+
+	var b strings.Builder
+	width := c.Size + 2*quietZone
+	for y := 0; y < width; y += 2 {
+		for x := 0; x < width; x++ {
+			top := light(x, y)
+			bottom := y+1 >= width || light(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteRune(bothLight)
+			case top:
+				b.WriteRune(topLight)
+			case bottom:
+				b.WriteRune(bottomLight)
+			default:
+				b.WriteRune(bothDark)
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// ParseTerminal returns the code rendered by Terminal, the quiet zone around
+// the code may have any width
+func ParseTerminal(text string) (*Code, error) {
+	var rows [][]bool
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		var top, bottom []bool
+		for _, r := range line {
+			top = append(top, r == bothDark || r == bottomLight)
+			bottom = append(bottom, r == bothDark || r == topLight)
+		}
+		rows = append(rows, top, bottom)
+	}
+
+	// the dark modules are bounded by the finder patterns
+	minX, minY, maxX, maxY := -1, -1, -1, -1
+	for y, row := range rows {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			if minX < 0 || x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if minY < 0 {
+				minY = y
+			}
+			maxY = y
+		}
+	}
+	if minX < 0 || maxX-minX != maxY-minY {
+		return nil, ErrInvalidSize
+	}
+
+	modules := make([][]bool, maxY-minY+1)
+	for y := range modules {
+		modules[y] = make([]bool, maxX-minX+1)
+		row := rows[minY+y]
+		for x := range modules[y] {
+			modules[y][x] = minX+x < len(row) && row[minX+x]
+		}
+	}
+	return FromModules(modules)
+}
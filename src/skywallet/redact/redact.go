@@ -0,0 +1,110 @@
+/*
+Package redact masks the secrets exchanged with a skywallet, such as
+mnemonics, PINs, passphrases and recovery words, before they are logged
+or displayed.
+*/
+package redact
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Value replaces the value of sensitive fields
+const Value = "[REDACTED]"
+
+// sensitiveFields are the names of the fields holding secrets
+var sensitiveFields = map[string]struct{}{
+	"mnemonic":    {},
+	"pin":         {},
+	"passphrase":  {},
+	"word":        {},
+	"private_key": {},
+}
+
+// assignment matches a sensitive field followed by its value, as written by
+// fmt, the protobuf text format or JSON: pin: 1234, mnemonic:"a b", "word":"c"
+var assignment = regexp.MustCompile(`(?i)\b(mnemonic|pin|passphrase|word|private_key)(["']?\s*[:=]\s*)("(?:[^"\\]|\\.)*"|'[^']*'|[^\s,}\]]+)`)
+
+// IsSensitive tells if the field name holds a secret, the name is case insensitive
+func IsSensitive(name string) bool {
+	_, ok := sensitiveFields[strings.ToLower(name)]
+	return ok
+}
+
+// String masks the values assigned to sensitive fields in s
+func String(s string) string {
+	return assignment.ReplaceAllString(s, "${1}${2}"+Value)
+}
+
+// Map masks the sensitive fields of a decoded JSON object, recursively
+func Map(fields map[string]interface{}) {
+	redact(fields)
+}
+
+func redact(v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range t {
+			if IsSensitive(k) {
+				t[k] = Value
+				continue
+			}
+			redact(fv)
+		}
+	case []interface{}:
+		for _, e := range t {
+			redact(e)
+		}
+	}
+}
+
+// Hook is a logrus hook masking the secrets of log entries, in their
+// message and in their fields
+type Hook struct{}
+
+// Levels implements logrus.Hook, all the levels are redacted
+func (Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook. The fields map may be shared with the
+// logger the entry comes from, so it is copied before being modified.
+func (Hook) Fire(entry *logrus.Entry) error {
+	entry.Message = String(entry.Message)
+
+	var data logrus.Fields
+	set := func(k string, v interface{}) {
+		if data == nil {
+			data = make(logrus.Fields, len(entry.Data))
+			for k, v := range entry.Data {
+				data[k] = v
+			}
+		}
+		data[k] = v
+	}
+	for k, v := range entry.Data {
+		if IsSensitive(k) {
+			set(k, Value)
+			continue
+		}
+		if s, ok := v.(string); ok {
+			if r := String(s); r != s {
+				set(k, r)
+			}
+		}
+	}
+	if data != nil {
+		entry.Data = data
+	}
+	return nil
+}
+
+// Wipe zeroes b, it is used to clear the buffers holding secrets once they are sent
+func Wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
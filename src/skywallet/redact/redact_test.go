@@ -0,0 +1,74 @@
+package redact
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	tt := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"fmt", "Setting pin: 1234", "Setting pin: [REDACTED]"},
+		{"upper case", "PIN=1234 sent", "PIN=[REDACTED] sent"},
+		{"proto text", `mnemonic:"cloud flower upset" label:"foo"`, `mnemonic:[REDACTED] label:"foo"`},
+		{"json", `{"passphrase":"s3cr\"et","word":"zoo"}`, `{"passphrase":[REDACTED],"word":[REDACTED]}`},
+		{"single quotes", "private_key='abc def'", "private_key=[REDACTED]"},
+		{"not a field", "pin code changed, PinMatrixAck response: ok", "pin code changed, PinMatrixAck response: ok"},
+		{"longer field name", "usePassphrase: true", "usePassphrase: true"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, String(tc.input))
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	fields := map[string]interface{}{
+		"label": "foo",
+		"pin":   "1234",
+		"nested": []interface{}{
+			map[string]interface{}{"mnemonic": "cloud flower"},
+		},
+	}
+	Map(fields)
+	require.Equal(t, map[string]interface{}{
+		"label": "foo",
+		"pin":   Value,
+		"nested": []interface{}{
+			map[string]interface{}{"mnemonic": Value},
+		},
+	}, fields)
+}
+
+func TestHook(t *testing.T) {
+	var out bytes.Buffer
+	logger := logrus.New()
+	logger.Out = &out
+	logger.Formatter = &logrus.JSONFormatter{}
+	logger.AddHook(Hook{})
+
+	moduleLogger := logger.WithField("_module", "test")
+	moduleLogger.WithField("Passphrase", "s3cret").WithField("msg", `word:"zoo"`).Infof("Setting pin: %s", "1234")
+	moduleLogger.Info("done")
+
+	require.NotContains(t, out.String(), "s3cret")
+	require.NotContains(t, out.String(), "zoo")
+	require.NotContains(t, out.String(), "1234")
+	require.Contains(t, out.String(), "Setting pin: [REDACTED]")
+	require.Contains(t, out.String(), `"Passphrase":"[REDACTED]"`)
+	require.Contains(t, out.String(), `"_module":"test"`)
+}
+
+func TestWipe(t *testing.T) {
+	b := []byte("cloud flower upset")
+	Wipe(b)
+	require.Equal(t, make([]byte, len(b)), b)
+}
@@ -0,0 +1,46 @@
+package skywallet
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/usb"
+)
+
+// getReplayDevice returns a device replaying the trace file testdata/name
+func getReplayDevice(t *testing.T, name string) (*Device, *usb.TraceReplayer) {
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	defer f.Close()
+
+	trace, err := usb.ReadTrace(f)
+	require.NoError(t, err)
+
+	replayer := usb.NewTraceReplayer(trace, true)
+	driver := NewReplayDriver(DeviceTypeUSB, replayer)
+	return &Device{driver, sync.Mutex{}, nil, false, false, ButtonType(-1), NopProgressReporter{}, nil, false, nil, 0}, replayer
+}
+
+func TestReplayGetFeatures(t *testing.T) {
+	device, replayer := getReplayDevice(t, "get_features.trace")
+
+	msg, err := device.GetFeatures()
+	require.NoError(t, err)
+	require.Equal(t, uint16(messages.MessageType_MessageType_Features), msg.Kind)
+
+	features := &messages.Features{}
+	require.NoError(t, proto.Unmarshal(msg.Data, features))
+	require.Equal(t, "Skycoin Foundation", features.GetVendor())
+	require.Equal(t, "regression", features.GetLabel())
+	require.True(t, features.GetInitialized())
+
+	in, out := replayer.Remaining()
+	require.Zero(t, in)
+	require.Zero(t, out)
+}
@@ -0,0 +1,41 @@
+package shamir
+
+// exp and log tables of GF(2^8) with the AES polynomial x^8 + x^4 + x^3 + x + 1
+// and the generator 3
+var (
+	expTable [255]byte
+	logTable [256]byte
+)
+
+func init() {
+	x := byte(1)
+	for i := range expTable {
+		expTable[i] = x
+		logTable[x] = byte(i)
+		// multiply by the generator, x*3 = x*2 ^ x
+		double := x << 1
+		if x&0x80 != 0 {
+			double ^= 0x1b
+		}
+		x ^= double
+	}
+}
+
+// mul multiplies a and b in GF(2^8)
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])+int(logTable[b]))%255]
+}
+
+// div divides a by b in GF(2^8), b must not be 0
+func div(a, b byte) byte {
+	if b == 0 {
+		panic("shamir: division by zero")
+	}
+	if a == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])-int(logTable[b])+255)%255]
+}
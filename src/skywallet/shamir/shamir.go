@@ -0,0 +1,138 @@
+/*
+Package shamir splits a mnemonic into M-of-N shares with Shamir's secret
+sharing, so that no single person holds the full seed, and recombines a
+quorum of shares into the mnemonic.
+
+The entropy of the mnemonic is split byte by byte over GF(2^8): each byte is
+the constant term of a random polynomial of degree M-1 and the shares are the
+values of the polynomials at x = 1 to N. Any M shares give the polynomials
+back by Lagrange interpolation, fewer shares tell nothing about the entropy.
+
+Shares are written as words of the BIP39 english wordlist, see Share.Words.
+The format is specific to this package, it is not compatible with SLIP-39.
+*/
+package shamir
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/redact"
+)
+
+const (
+	// MinThreshold is the minimum number of shares needed to recombine a secret
+	MinThreshold = 2
+	// MaxShares is the maximum number of shares of a secret
+	MaxShares = 16
+)
+
+var (
+	// ErrInvalidThreshold is returned if the threshold is not between MinThreshold and the number of shares
+	ErrInvalidThreshold = fmt.Errorf("threshold must be between %d and the number of shares", MinThreshold)
+	// ErrInvalidShareCount is returned if the number of shares is not between MinThreshold and MaxShares
+	ErrInvalidShareCount = fmt.Errorf("number of shares must be between %d and %d", MinThreshold, MaxShares)
+	// ErrEmptySecret is returned if the secret to split is empty
+	ErrEmptySecret = errors.New("secret is empty")
+	// ErrDuplicateShare is returned if a share is given twice to Combine
+	ErrDuplicateShare = errors.New("the same share was given twice")
+	// ErrMixedShares is returned if the shares given to Combine do not come from the same split
+	ErrMixedShares = errors.New("shares come from different splits")
+)
+
+// Point is the value of the polynomials of a split at X
+type Point struct {
+	// X is between 1 and MaxShares
+	X byte
+	// Y holds the value of the polynomial of each byte of the secret
+	Y []byte
+}
+
+// NotEnoughSharesError is returned if fewer shares than the threshold are given to Combine
+type NotEnoughSharesError struct {
+	Have      int
+	Threshold int
+}
+
+func (e *NotEnoughSharesError) Error() string {
+	return fmt.Sprintf("%d shares given, %d are needed", e.Have, e.Threshold)
+}
+
+// Split returns n points of random polynomials of degree threshold-1 whose
+// constant terms are the bytes of secret. The coefficients are read from rand.
+func Split(secret []byte, threshold, n int, rand io.Reader) ([]Point, error) {
+	if len(secret) == 0 {
+		return nil, ErrEmptySecret
+	}
+	if n < MinThreshold || n > MaxShares {
+		return nil, ErrInvalidShareCount
+	}
+	if threshold < MinThreshold || threshold > n {
+		return nil, ErrInvalidThreshold
+	}
+
+	coefficients := make([]byte, threshold-1)
+	defer redact.Wipe(coefficients)
+
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{
+			X: byte(i + 1),
+			Y: make([]byte, len(secret)),
+		}
+	}
+	for b, s := range secret {
+		if _, err := io.ReadFull(rand, coefficients); err != nil {
+			return nil, err
+		}
+		for i := range points {
+			points[i].Y[b] = evaluate(s, coefficients, points[i].X)
+		}
+	}
+	return points, nil
+}
+
+// Combine interpolates the polynomials going through points and returns their
+// constant terms. The caller checks there are at least threshold points.
+func Combine(points []Point) ([]byte, error) {
+	if len(points) == 0 {
+		return nil, ErrEmptySecret
+	}
+	seen := make(map[byte]bool, len(points))
+	for _, p := range points {
+		if seen[p.X] {
+			return nil, ErrDuplicateShare
+		}
+		if len(p.Y) != len(points[0].Y) {
+			return nil, ErrMixedShares
+		}
+		seen[p.X] = true
+	}
+
+	secret := make([]byte, len(points[0].Y))
+	for i, p := range points {
+		// Lagrange basis polynomial of p evaluated at 0, subtraction is xor in GF(2^8)
+		basis := byte(1)
+		for j, q := range points {
+			if i != j {
+				basis = mul(basis, div(q.X, q.X^p.X))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= mul(p.Y[b], basis)
+		}
+	}
+	return secret, nil
+}
+
+// evaluate returns the value at x of the polynomial with constant term s and
+// the other coefficients in increasing degree order
+func evaluate(s byte, coefficients []byte, x byte) byte {
+	// Horner's method, starting from the highest degree
+	y := byte(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coefficients[i]
+	}
+	return mul(y, x) ^ s
+}
@@ -0,0 +1,110 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGF256(t *testing.T) {
+	// multiplication example of FIPS-197 section 4.2
+	require.Equal(t, byte(0xc1), mul(0x57, 0x83))
+	for a := 1; a < 256; a++ {
+		require.Equal(t, byte(a), mul(byte(a), 1))
+		require.Equal(t, byte(1), mul(byte(a), div(1, byte(a))))
+		require.Equal(t, byte(0), mul(byte(a), 0))
+	}
+}
+
+// subsets returns the subsets of k elements of {0, ..., n-1}
+func subsets(n, k int) [][]int {
+	if k == 0 {
+		return [][]int{{}}
+	}
+	var result [][]int
+	for first := 0; first <= n-k; first++ {
+		for _, rest := range subsets(n-first-1, k-1) {
+			s := []int{first}
+			for _, r := range rest {
+				s = append(s, first+1+r)
+			}
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("0123456789abcdef")
+	points, err := Split(secret, 3, 5, rand.Reader)
+	require.NoError(t, err)
+	require.Len(t, points, 5)
+
+	for k := 3; k <= 5; k++ {
+		for _, subset := range subsets(5, k) {
+			var quorum []Point
+			for _, i := range subset {
+				quorum = append(quorum, points[i])
+			}
+			combined, err := Combine(quorum)
+			require.NoError(t, err)
+			require.Equal(t, secret, combined)
+		}
+	}
+
+	// two points of a polynomial of degree 2 give another secret
+	combined, err := Combine(points[:2])
+	require.NoError(t, err)
+	require.NotEqual(t, secret, combined)
+}
+
+func TestSplitPolynomial(t *testing.T) {
+	secret := []byte{0x42}
+	coefficients := bytes.NewReader([]byte{0x01, 0x02})
+	points, err := Split(secret, 3, 3, coefficients)
+	require.NoError(t, err)
+	// f(x) = 0x42 + x + 2x^2
+	for _, p := range points {
+		expected := 0x42 ^ p.X ^ mul(2, mul(p.X, p.X))
+		require.Equal(t, []byte{expected}, p.Y)
+	}
+
+	_, err = Split([]byte{0x42, 0x43}, 3, 3, bytes.NewReader([]byte{0x01, 0x02}))
+	require.Error(t, err)
+}
+
+func TestSplitInvalid(t *testing.T) {
+	tt := []struct {
+		name      string
+		secret    []byte
+		threshold int
+		n         int
+		err       error
+	}{
+		{"empty secret", nil, 2, 3, ErrEmptySecret},
+		{"one share", []byte{1}, 1, 1, ErrInvalidShareCount},
+		{"too many shares", []byte{1}, 2, MaxShares + 1, ErrInvalidShareCount},
+		{"threshold of one", []byte{1}, 1, 3, ErrInvalidThreshold},
+		{"threshold above shares", []byte{1}, 4, 3, ErrInvalidThreshold},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Split(tc.secret, tc.threshold, tc.n, rand.Reader)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestCombineInvalid(t *testing.T) {
+	points, err := Split([]byte{1, 2}, 2, 3, rand.Reader)
+	require.NoError(t, err)
+
+	_, err = Combine([]Point{points[0], points[0]})
+	require.Equal(t, ErrDuplicateShare, err)
+
+	_, err = Combine([]Point{points[0], {X: 2, Y: []byte{1}}})
+	require.Equal(t, ErrMixedShares, err)
+}
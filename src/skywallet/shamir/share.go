@@ -0,0 +1,171 @@
+package shamir
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/bip39"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/redact"
+)
+
+const (
+	// headerLen is the number of bytes of the split id, the threshold and the x of a share
+	headerLen = 3
+	// minChecksumBits is the minimum number of checksum bits of a share, the
+	// last word is filled with more checksum bits
+	minChecksumBits = 16
+	// bitsPerWord is the number of bits encoded by a BIP39 word
+	bitsPerWord = 11
+)
+
+var (
+	// ErrInvalidShareLength is returned if a share does not have 16 or 27 words
+	ErrInvalidShareLength = errors.New("share must have 16 or 27 words")
+	// ErrShareChecksum is returned if the checksum of a share does not match its words
+	ErrShareChecksum = errors.New("invalid share checksum, a word was not written or typed correctly")
+)
+
+// entropyLens are the lengths in bytes of the entropy of mnemonics of 12 and 24 words
+var entropyLens = []int{16, 32}
+
+// Share is a share of the entropy of a mnemonic. Each word encodes 11 bits of:
+// the 16 bits id of the split, 4 bits for the threshold minus one, 4 bits for
+// X minus one, the bytes of Y and the first bits of the sha256 of the previous
+// bytes as checksum.
+type Share struct {
+	// ID identifies the split the share comes from, it is random
+	ID        uint16
+	Threshold int
+	Point
+}
+
+// ShareWordCount returns the number of words of a share of an entropy of entropyLen bytes
+func ShareWordCount(entropyLen int) int {
+	bits := (headerLen+entropyLen)*8 + minChecksumBits
+	return (bits + bitsPerWord - 1) / bitsPerWord
+}
+
+// Words encodes the share into words of the BIP39 english wordlist
+func (s Share) Words() []string {
+	data := make([]byte, headerLen+len(s.Y), headerLen+len(s.Y)+sha256.Size)
+	defer redact.Wipe(data)
+	binary.BigEndian.PutUint16(data, s.ID)
+	data[2] = byte(s.Threshold-1)<<4 | (s.X-1)&0x0f
+	copy(data[headerLen:], s.Y)
+	hash := sha256.Sum256(data)
+	data = append(data, hash[:]...)
+	return bip39.Encode(data, ShareWordCount(len(s.Y)))
+}
+
+// String returns the words of the share separated by spaces
+func (s Share) String() string {
+	return strings.Join(s.Words(), " ")
+}
+
+// ParseShare decodes the words of a share. It returns ErrInvalidShareLength,
+// a *bip39.UnknownWordsError with suggestions for each unknown word or
+// ErrShareChecksum.
+func ParseShare(share string) (Share, error) {
+	words := strings.Fields(bip39.NormalizeMnemonic(share))
+	entropyLen := 0
+	for _, n := range entropyLens {
+		if ShareWordCount(n) == len(words) {
+			entropyLen = n
+		}
+	}
+	if entropyLen == 0 {
+		return Share{}, ErrInvalidShareLength
+	}
+
+	data, err := bip39.Decode(words)
+	if err != nil {
+		return Share{}, err
+	}
+	defer redact.Wipe(data)
+
+	payloadLen := headerLen + entropyLen
+	hash := sha256.Sum256(data[:payloadLen])
+	checksumBits := len(words)*bitsPerWord - payloadLen*8
+	for b := 0; b < checksumBits; b++ {
+		mask := byte(0x80 >> uint(b%8))
+		if hash[b/8]&mask != data[payloadLen+b/8]&mask {
+			return Share{}, ErrShareChecksum
+		}
+	}
+
+	return Share{
+		ID:        binary.BigEndian.Uint16(data),
+		Threshold: int(data[2]>>4) + 1,
+		Point: Point{
+			X: data[2]&0x0f + 1,
+			Y: append([]byte(nil), data[headerLen:payloadLen]...),
+		},
+	}, nil
+}
+
+// SplitMnemonic splits the entropy of mnemonic into n shares, threshold of
+// them are needed to recombine the mnemonic. The id of the split and the
+// coefficients of the polynomials are read from rand.
+func SplitMnemonic(mnemonic string, threshold, n int, rand io.Reader) ([]Share, error) {
+	entropy, err := bip39.MnemonicEntropy(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	defer redact.Wipe(entropy)
+
+	var id [2]byte
+	if _, err = io.ReadFull(rand, id[:]); err != nil {
+		return nil, err
+	}
+	points, err := Split(entropy, threshold, n, rand)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]Share, len(points))
+	for i, p := range points {
+		shares[i] = Share{
+			ID:        binary.BigEndian.Uint16(id[:]),
+			Threshold: threshold,
+			Point:     p,
+		}
+	}
+	return shares, nil
+}
+
+// CombineMnemonic recombines the mnemonic split into shares. The shares
+// must come from the same split and at least threshold of them are needed,
+// it returns ErrMixedShares, ErrDuplicateShare or a *NotEnoughSharesError
+// otherwise.
+func CombineMnemonic(shares []Share) (string, error) {
+	if len(shares) == 0 {
+		return "", &NotEnoughSharesError{Threshold: MinThreshold}
+	}
+	threshold := shares[0].Threshold
+	for _, s := range shares {
+		if s.ID != shares[0].ID || s.Threshold != threshold {
+			return "", ErrMixedShares
+		}
+	}
+	if len(shares) < threshold {
+		return "", &NotEnoughSharesError{
+			Have:      len(shares),
+			Threshold: threshold,
+		}
+	}
+
+	points := make([]Point, len(shares))
+	for i, s := range shares {
+		points[i] = s.Point
+	}
+	entropy, err := Combine(points)
+	if err != nil {
+		return "", err
+	}
+	defer redact.Wipe(entropy)
+
+	return bip39.NewMnemonic(entropy)
+}
@@ -0,0 +1,109 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/bip39"
+)
+
+const (
+	mnemonic12 = "cloud flower upset remain green metal below cup stem infant art thank"
+	mnemonic24 = "dress fee animal silly multiply demand casino gold pipe matrix latin badge umbrella orbit safe cover glove one dash chicken play obey employ post"
+)
+
+func TestShareWordCount(t *testing.T) {
+	expected := map[int]int{16: 16, 20: 19, 24: 22, 28: 24, 32: 27}
+	for entropyLen, words := range expected {
+		require.Equal(t, words, ShareWordCount(entropyLen))
+	}
+}
+
+func TestShareWords(t *testing.T) {
+	for _, entropyLen := range entropyLens {
+		share := Share{
+			ID:        0xbeef,
+			Threshold: 3,
+			Point: Point{
+				X: 5,
+				Y: bytes.Repeat([]byte{0xa5}, entropyLen),
+			},
+		}
+		words := share.Words()
+		require.Len(t, words, ShareWordCount(entropyLen))
+
+		parsed, err := ParseShare(strings.ToUpper(share.String()))
+		require.NoError(t, err)
+		require.Equal(t, share, parsed)
+	}
+}
+
+func TestParseShareInvalid(t *testing.T) {
+	shares, err := SplitMnemonic(mnemonic12, 2, 3, rand.Reader)
+	require.NoError(t, err)
+	words := shares[0].Words()
+
+	_, err = ParseShare(strings.Join(words[1:], " "))
+	require.Equal(t, ErrInvalidShareLength, err)
+
+	// a word replaced by its neighbour in the wordlist
+	index, ok := bip39.WordIndex(words[3])
+	require.True(t, ok)
+	typo := append([]string(nil), words...)
+	typo[3] = bip39.English[(index+1)%len(bip39.English)]
+	_, err = ParseShare(strings.Join(typo, " "))
+	require.Equal(t, ErrShareChecksum, err)
+
+	unknown := append([]string(nil), words...)
+	unknown[7] = "foobar"
+	_, err = ParseShare(strings.Join(unknown, " "))
+	require.IsType(t, &bip39.UnknownWordsError{}, err)
+	require.Equal(t, 8, err.(*bip39.UnknownWordsError).Words[0].Position)
+}
+
+func TestSplitCombineMnemonic(t *testing.T) {
+	for _, mnemonic := range []string{mnemonic12, mnemonic24} {
+		shares, err := SplitMnemonic(mnemonic, 3, 5, rand.Reader)
+		require.NoError(t, err)
+		require.Len(t, shares, 5)
+
+		for _, subset := range subsets(5, 3) {
+			var quorum []Share
+			for _, i := range subset {
+				parsed, err := ParseShare(shares[i].String())
+				require.NoError(t, err)
+				quorum = append(quorum, parsed)
+			}
+			combined, err := CombineMnemonic(quorum)
+			require.NoError(t, err)
+			require.Equal(t, mnemonic, combined)
+		}
+
+		_, err = CombineMnemonic(shares[:2])
+		require.Equal(t, &NotEnoughSharesError{Have: 2, Threshold: 3}, err)
+	}
+}
+
+func TestCombineMnemonicInvalid(t *testing.T) {
+	shares, err := SplitMnemonic(mnemonic12, 2, 3, rand.Reader)
+	require.NoError(t, err)
+	other, err := SplitMnemonic(mnemonic12, 2, 3, rand.Reader)
+	require.NoError(t, err)
+	other[1].ID = shares[0].ID + 1
+
+	_, err = CombineMnemonic([]Share{shares[0], other[1]})
+	require.Equal(t, ErrMixedShares, err)
+
+	_, err = CombineMnemonic([]Share{shares[0], shares[0]})
+	require.Equal(t, ErrDuplicateShare, err)
+
+	_, err = CombineMnemonic(nil)
+	require.IsType(t, &NotEnoughSharesError{}, err)
+
+	_, err = SplitMnemonic("cloud flower upset", 2, 3, rand.Reader)
+	require.Equal(t, bip39.ErrInvalidWordCount, err)
+}
@@ -3,23 +3,37 @@ package skywallet
 import (
 	"errors"
 	"fmt"
-	"os"
 	"sync"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+
 	"github.com/skycoin/hardware-wallet-go/src/skywallet/usb"
 
 	"github.com/skycoin/skycoin/src/util/logging"
 
 	messages "github.com/skycoin/hardware-wallet-protob/go"
 
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/codec"
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/redact"
 	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
 )
 
 var (
 	log = logging.MustGetLogger("skywallet")
+
+	logRedaction sync.Once
 )
 
+// InstallLogRedaction adds redact.Hook to the logging of the process, masking
+// the secrets of the entries of every logger created by logging.MustGetLogger.
+// The library does not install it by itself, programs opt in once at startup.
+func InstallLogRedaction() {
+	logRedaction.Do(func() {
+		logging.AddHook(redact.Hook{})
+	})
+}
+
 const (
 	entropyBufferSize int = 32
 )
@@ -46,6 +60,8 @@ var (
 	ErrDeviceTypeEmulator = errors.New("device type cannot be emulator")
 	// ErrInvalidWordCount is returned if word count is not valid mnemonic word length
 	ErrInvalidWordCount = errors.New("word count must be 12 or 24")
+	// ErrNoSeed is returned if neither a mnemonic nor a node is given to LoadDevice
+	ErrNoSeed = errors.New("either mnemonic or node must be set")
 	// ErrNoDeviceConnected is returned if no device is connected to the system
 	ErrNoDeviceConnected = errors.New("no device connected")
 )
@@ -57,6 +73,7 @@ type Devicer interface {
 	AddressGen(addressN, startIndex uint32, confirmAddress bool) (wire.Message, error)
 	ApplySettings(usePassphrase *bool, label string, language string) (wire.Message, error)
 	Backup() (wire.Message, error)
+	Call(kind uint16, msg proto.Message) (wire.Message, error)
 	Cancel() (wire.Message, error)
 	CheckMessageSignature(message, signature, address string) (wire.Message, error)
 	ChangePin(removePin *bool) (wire.Message, error)
@@ -64,8 +81,12 @@ type Devicer interface {
 	Available() bool
 	FirmwareUpload(payload []byte, hash [32]byte) error
 	GetFeatures() (wire.Message, error)
+	Initialize() (wire.Message, error)
 	GenerateMnemonic(wordCount uint32, usePassphrase bool) (wire.Message, error)
+	LoadDevice(mnemonic string, node *messages.HDNodeType, pin, label string, usePassphrase, skipChecksum bool) (wire.Message, error)
+	Ping(message string, buttonProtection, pinProtection, passphraseProtection bool) (wire.Message, error)
 	Recovery(wordCount uint32, usePassphrase *bool, dryRun bool) (wire.Message, error)
+	ResetDevice(wordCount uint32, usePassphrase, usePin, displayRandom, skipBackup bool, label string) (wire.Message, error)
 	SetMnemonic(mnemonic string) (wire.Message, error)
 	TransactionSign(inputs []*messages.SkycoinTransactionInput, outputs []*messages.SkycoinTransactionOutput) (wire.Message, error)
 	SignMessage(addressIndex int, message string) (wire.Message, error)
@@ -73,6 +94,10 @@ type Devicer interface {
 	PinMatrixAck(p string) (wire.Message, error)
 	WordAck(word string) (wire.Message, error)
 	PassphraseAck(passphrase string) (wire.Message, error)
+	PassphraseFingerprint() (string, error)
+	PassphraseState() []byte
+	SetPassphraseState(state []byte)
+	SetChangeVerification(handle RequestHandler)
 	ButtonAck() (wire.Message, error)
 	SetAutoPressButton(simulateButtonPress bool, simulateButtonType ButtonType) error
 	Close()
@@ -90,6 +115,14 @@ type Device struct {
 	connected           bool
 	simulateButtonPress bool
 	simulateButtonType  ButtonType
+	progress            ProgressReporter
+	passphraseState     []byte
+	// passphraseStateSet tells if passphraseState was given to SetPassphraseState
+	// and must match the state reported by the device
+	passphraseStateSet bool
+	changeVerification RequestHandler
+	// transactionSignLimit is 0 for DefaultTransactionSignLimit
+	transactionSignLimit int
 }
 
 // DeviceTypeFromString returns device type from string
@@ -125,6 +158,11 @@ func newDevice(deviceType DeviceType) *Device {
 		false,
 		false,
 		ButtonType(-1),
+		NopProgressReporter{},
+		nil,
+		false,
+		nil,
+		0,
 	}
 }
 
@@ -138,6 +176,30 @@ func NewDevice(deviceType DeviceType) *Device {
 	return devSingleInstance
 }
 
+// NewDeviceAtPath returns a device connecting to the device at path, see
+// NewDriverAtPath. Unlike NewDevice it is not a singleton, several devices
+// can be used at the same time.
+func NewDeviceAtPath(deviceType DeviceType, path string) (*Device, error) {
+	driver, err := NewDriverAtPath(deviceType, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Device{
+		driver,
+		sync.Mutex{},
+		nil,
+		false,
+		false,
+		ButtonType(-1),
+		NopProgressReporter{},
+		nil,
+		false,
+		nil,
+		0,
+	}, nil
+}
+
 // Close closes the usb bus
 // Device should be closed before shutdown to avoid running out of open file descriptors
 func (d *Device) Close() {
@@ -207,179 +269,7 @@ func (d *Device) AddressGen(addressN, startIndex uint32, confirmAddress bool) (w
 		return wire.Message{}, err
 	}
 
-	return d.Driver.SendToDevice(d.dev, addressGenChunks)
-}
-
-// SaveDeviceEntropyInFile Ask the device to generate entropy and save it in a file
-// if `outFile` is the "-" string, the output file is considered stdout
-func (d *Device) SaveDeviceEntropyInFile(outFile string, entropyBytes uint32, getEntropyMsgBuilder func(entropyBytes uint32) ([][64]byte, error)) error {
-	usingStdout := false
-	if outFile == "-" {
-		usingStdout = true
-	}
-	if !usingStdout {
-		log.Infoln("Saving entropy to", outFile)
-	}
-	var receivedEntropyBytes uint32
-	var processBytes func(buf []byte) error
-	var err error
-	var processGetEntropyResponse func(msg wire.Message) (*messages.Entropy, error)
-	processGetEntropyResponse = func(msg wire.Message) (*messages.Entropy, error) {
-		if err != nil || msg.Kind != uint16(messages.MessageType_MessageType_Entropy) {
-			if err != nil {
-				return &messages.Entropy{}, err
-			}
-
-			if msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
-				// Send ButtonAck
-				chunks, err := MessageButtonAck()
-				if err != nil {
-					return &messages.Entropy{}, err
-				}
-				if err = sendToDeviceNoAnswer(d.dev, chunks); err != nil {
-					return &messages.Entropy{}, err
-				}
-				// simulate button press
-				if d.simulateButtonPress {
-					if err := d.SimulateButtonPress(); err != nil {
-						return &messages.Entropy{}, err
-					}
-				}
-
-				msg, err := wire.ReadFrom(d.dev)
-				if err != nil {
-					return nil, err
-				}
-				return processGetEntropyResponse(*msg)
-			}
-			var msgStr string
-			msgStr, err = DecodeFailMsg(msg)
-			if err != nil {
-				log.Errorf("Error decoding device response as fails msg %s", err)
-				return &messages.Entropy{}, err
-			}
-			err = errors.New(msgStr)
-			log.Errorf("Error getting entropy from device %s", err)
-			return &messages.Entropy{}, err
-		}
-		entropy, err := DecodeResponseEntropyMessage(msg)
-		if err != nil {
-			log.Errorf("Error decoding device response %s", err)
-			return &messages.Entropy{}, err
-		}
-		return entropy, nil
-	}
-
-	getEntropy := func(bytes uint32) (*messages.Entropy, error) {
-		chunks, err := getEntropyMsgBuilder(bytes)
-		if err != nil {
-			return &messages.Entropy{}, err
-		}
-		resp, err := d.Driver.SendToDevice(d.dev, chunks)
-		if err != nil {
-			return &messages.Entropy{}, err
-		}
-		return processGetEntropyResponse(resp)
-	}
-
-	checkProducedFile := func() error {
-		if !usingStdout {
-			fileInfo, err := os.Stat(outFile)
-			if err != nil {
-				log.Error(err)
-				return err
-			}
-			if fileInfo.Size() != int64(entropyBytes) {
-				return fmt.Errorf(
-					"no engout bytes saved in the file %s\n current: %d\nrequired: %d",
-					outFile, fileInfo.Size(), entropyBytes)
-			}
-		}
-		return nil
-	}
-
-	if usingStdout {
-		processBytes = func(buf []byte) error {
-			fmt.Print(buf)
-			return nil
-		}
-	} else {
-		pb := Progbar{total: int(entropyBytes)}
-		defer func() {
-			if checkProducedFile() == nil {
-				pb.PrintComplete()
-			}
-		}()
-		if _, err := os.Stat(outFile); err == nil {
-			// nolint: gosec
-			if err = os.Chmod(outFile, 0777); err != nil {
-				log.Errorf("error with %s %s", outFile, err)
-			}
-		}
-		file, err := os.Create(outFile)
-		if err != nil {
-			log.Errorf("error creating output file %s", err)
-			return err
-		}
-		defer func() {
-			if err := os.Chmod(outFile, 0444); err != nil {
-				log.Error(err)
-			}
-		}()
-		defer file.Close()
-
-		processBytes = func(buf []byte) error {
-			var wroteBytes = 0
-			for wroteBytes < len(buf) {
-				var res int
-				if res, err = file.Write(buf[wroteBytes:]); err != nil {
-					return err
-				}
-				wroteBytes += res
-			}
-			if wroteBytes != len(buf) {
-				return errors.New("invalid bytes amount wrote")
-			}
-			pb.PrintProg(int(receivedEntropyBytes))
-			return nil
-		}
-	}
-
-	if err := d.Connect(); err != nil {
-		return err
-	}
-
-	defer func() {
-		if err := d.Disconnect(); err != nil {
-			log.Error(err)
-		}
-	}()
-
-	entropy, err := getEntropy(entropyBytes)
-	if err != nil {
-		log.Error(err)
-		return err
-	}
-
-	receivedEntropyBytes = uint32(len(entropy.GetEntropy()))
-	if err := processBytes(entropy.GetEntropy()); err != nil {
-		log.Errorf("error writing file %s.\n %s", outFile, err.Error())
-		return err
-	}
-
-	for receivedEntropyBytes < entropyBytes {
-		entropy, err := getEntropy(entropyBytes - receivedEntropyBytes)
-		if err != nil {
-			log.Error(err)
-			return err
-		}
-		receivedEntropyBytes += uint32(len(entropy.GetEntropy()))
-		if err := processBytes(entropy.GetEntropy()); err != nil {
-			log.Errorf("error writing file %s.\n %s", outFile, err.Error())
-			return err
-		}
-	}
-	return checkProducedFile()
+	return d.sendToDevice(addressGenChunks)
 }
 
 // ApplySettings send ApplySettings request to the device
@@ -394,7 +284,7 @@ func (d *Device) ApplySettings(usePassphrase *bool, label string, language strin
 		return wire.Message{}, err
 	}
 
-	return d.Driver.SendToDevice(d.dev, applySettingsChunks)
+	return d.sendToDevice(applySettingsChunks)
 }
 
 // Backup ask the device to perform the seed backup
@@ -407,13 +297,39 @@ func (d *Device) Backup() (msg wire.Message, err error) {
 	if err != nil {
 		return wire.Message{}, err
 	}
-	msg, err = d.Driver.SendToDevice(d.dev, backupChunks)
+	msg, err = d.sendToDevice(backupChunks)
 	if err != nil {
 		return wire.Message{}, err
 	}
 	return msg, err
 }
 
+// Call sends msg to the device as a message of the given kind and returns the answer,
+// it allows sending messages without a dedicated method, i.e added by a newer firmware.
+// The chunks of the kinds carrying secrets, see codec.Sensitive, are zeroed once sent.
+func (d *Device) Call(kind uint16, msg proto.Message) (wire.Message, error) {
+	if err := d.Connect(); err != nil {
+		return wire.Message{}, err
+	}
+	defer d.Disconnect()
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return wire.Message{}, err
+	}
+	// the payload may hold a secret, only the chunks are kept
+	defer redact.Wipe(data)
+	chunks, err := codec.Chunk(kind, data)
+	if err != nil {
+		return wire.Message{}, err
+	}
+
+	if codec.Sensitive(kind) {
+		return d.sendSecretToDevice(chunks)
+	}
+	return d.sendToDevice(chunks)
+}
+
 // Cancel sends a Cancel request
 func (d *Device) Cancel() (wire.Message, error) {
 	if err := d.Connect(); err != nil {
@@ -426,7 +342,7 @@ func (d *Device) Cancel() (wire.Message, error) {
 		return wire.Message{}, err
 	}
 
-	return d.Driver.SendToDevice(d.dev, cancelChunks)
+	return d.sendToDevice(cancelChunks)
 }
 
 // CheckMessageSignature Check a message signature matches the given address.
@@ -442,7 +358,7 @@ func (d *Device) CheckMessageSignature(message, signature, address string) (wire
 		return wire.Message{}, err
 	}
 
-	return d.Driver.SendToDevice(d.dev, checkMessageSignatureChunks)
+	return d.sendToDevice(checkMessageSignatureChunks)
 }
 
 // ChangePin changes device's PIN code
@@ -476,7 +392,7 @@ func (d *Device) ChangePin(removePin *bool) (wire.Message, error) {
 		return wire.Message{}, err
 	}
 
-	msg, err := d.Driver.SendToDevice(d.dev, changePinChunks)
+	msg, err := d.sendToDevice(changePinChunks)
 	if err != nil {
 		return wire.Message{}, err
 	}
@@ -577,7 +493,7 @@ func (d *Device) FirmwareUpload(payload []byte, hash [32]byte) error {
 	if err != nil {
 		return err
 	}
-	erasemsg, err := d.Driver.SendToDevice(d.dev, chunks)
+	erasemsg, err := d.sendToDevice(chunks)
 	if err != nil {
 		return err
 	}
@@ -602,7 +518,7 @@ func (d *Device) FirmwareUpload(payload []byte, hash [32]byte) error {
 	if err != nil {
 		return err
 	}
-	uploadmsg, err := d.Driver.SendToDevice(d.dev, chunks)
+	uploadmsg, err := d.sendToDeviceWithProgress(chunks)
 	if err != nil {
 		return err
 	}
@@ -615,7 +531,7 @@ func (d *Device) FirmwareUpload(payload []byte, hash [32]byte) error {
 		if err != nil {
 			return err
 		}
-		resp, err := d.Driver.SendToDevice(d.dev, chunks)
+		resp, err := d.sendToDevice(chunks)
 		if err != nil {
 			return err
 		}
@@ -655,7 +571,7 @@ func (d *Device) GetFeatures() (wire.Message, error) {
 		return wire.Message{}, err
 	}
 
-	return d.Driver.SendToDevice(d.dev, getFeaturesChunks)
+	return d.sendToDevice(getFeaturesChunks)
 }
 
 // GenerateMnemonic Ask the device to generate a mnemonic and configure itself with it.
@@ -674,7 +590,7 @@ func (d *Device) GenerateMnemonic(wordCount uint32, usePassphrase bool) (wire.Me
 		return wire.Message{}, err
 	}
 
-	msg, err := d.Driver.SendToDevice(d.dev, generateMnemonicChunks)
+	msg, err := d.sendToDevice(generateMnemonicChunks)
 	if err != nil {
 		return msg, err
 	}
@@ -682,6 +598,42 @@ func (d *Device) GenerateMnemonic(wordCount uint32, usePassphrase bool) (wire.Me
 	return msg, err
 }
 
+// LoadDevice configure the device with a mnemonic or a HD node without any user interaction,
+// meant to quickly set up fixtures in tests
+func (d *Device) LoadDevice(mnemonic string, node *messages.HDNodeType, pin, label string, usePassphrase, skipChecksum bool) (wire.Message, error) {
+	if err := d.Connect(); err != nil {
+		return wire.Message{}, err
+	}
+	defer d.Disconnect()
+
+	if mnemonic == "" && node == nil {
+		return wire.Message{}, ErrNoSeed
+	}
+
+	loadDeviceChunks, err := MessageLoadDevice(mnemonic, node, pin, label, usePassphrase, skipChecksum)
+	if err != nil {
+		return wire.Message{}, err
+	}
+
+	return d.sendSecretToDevice(loadDeviceChunks)
+}
+
+// Ping send a message the device echoes back, optionally asking for a button press,
+// the PIN or the passphrase before answering
+func (d *Device) Ping(message string, buttonProtection, pinProtection, passphraseProtection bool) (wire.Message, error) {
+	if err := d.Connect(); err != nil {
+		return wire.Message{}, err
+	}
+	defer d.Disconnect()
+
+	pingChunks, err := MessagePing(message, buttonProtection, pinProtection, passphraseProtection)
+	if err != nil {
+		return wire.Message{}, err
+	}
+
+	return d.sendToDevice(pingChunks)
+}
+
 // Recovery ask the device to perform the seed backup
 func (d *Device) Recovery(wordCount uint32, usePassphrase *bool, dryRun bool) (wire.Message, error) {
 	if err := d.Connect(); err != nil {
@@ -693,13 +645,12 @@ func (d *Device) Recovery(wordCount uint32, usePassphrase *bool, dryRun bool) (w
 		return wire.Message{}, ErrInvalidWordCount
 	}
 
-	log.Printf("Using passphrase %t\n", usePassphrase)
 	recoveryChunks, err := MessageRecovery(wordCount, usePassphrase, dryRun)
 	if err != nil {
 		return wire.Message{}, err
 	}
 
-	msg, err := d.Driver.SendToDevice(d.dev, recoveryChunks)
+	msg, err := d.sendToDevice(recoveryChunks)
 	if err != nil {
 		return wire.Message{}, err
 	}
@@ -708,6 +659,27 @@ func (d *Device) Recovery(wordCount uint32, usePassphrase *bool, dryRun bool) (w
 	return msg, nil
 }
 
+// ResetDevice ask the device to generate a new seed mixing its entropy with entropy sent by the host
+func (d *Device) ResetDevice(wordCount uint32, usePassphrase, usePin, displayRandom, skipBackup bool, label string) (wire.Message, error) {
+	if err := d.Connect(); err != nil {
+		return wire.Message{}, err
+	}
+	defer d.Disconnect()
+
+	if wordCount != 12 && wordCount != 24 {
+		return wire.Message{}, ErrInvalidWordCount
+	}
+
+	// 11 bits per word, 1 bit out of 33 is the checksum
+	strength := wordCount * 11 * 32 / 33
+	resetDeviceChunks, err := MessageResetDevice(strength, usePassphrase, usePin, displayRandom, skipBackup, label)
+	if err != nil {
+		return wire.Message{}, err
+	}
+
+	return d.sendToDevice(resetDeviceChunks)
+}
+
 // SetMnemonic Configure the device with a mnemonic.
 func (d *Device) SetMnemonic(mnemonic string) (wire.Message, error) {
 	if err := d.Connect(); err != nil {
@@ -720,7 +692,7 @@ func (d *Device) SetMnemonic(mnemonic string) (wire.Message, error) {
 	if err != nil {
 		return wire.Message{}, err
 	}
-	msg, err := d.Driver.SendToDevice(d.dev, setMnemonicChunks)
+	msg, err := d.sendSecretToDevice(setMnemonicChunks)
 	if err != nil {
 		return wire.Message{}, err
 	}
@@ -740,7 +712,7 @@ func (d *Device) SignMessage(addressIndex int, message string) (wire.Message, er
 		return wire.Message{}, err
 	}
 
-	msg, err := d.Driver.SendToDevice(d.dev, signMessageChunks)
+	msg, err := d.sendToDevice(signMessageChunks)
 	if err != nil {
 		return wire.Message{}, err
 	}
@@ -749,7 +721,22 @@ func (d *Device) SignMessage(addressIndex int, message string) (wire.Message, er
 }
 
 // TransactionSign Ask the device to sign a transaction using the given information.
+// The change outputs are checked first if SetChangeVerification was called and
+// transactions over TransactionSignLimit are refused with a TransactionLimitError.
 func (d *Device) TransactionSign(inputs []*messages.SkycoinTransactionInput, outputs []*messages.SkycoinTransactionOutput) (wire.Message, error) {
+	if err := checkTransactionSignLimit(len(inputs), len(outputs), d.TransactionSignLimit()); err != nil {
+		return wire.Message{}, err
+	}
+
+	d.Lock()
+	handle := d.changeVerification
+	d.Unlock()
+	if handle != nil {
+		if err := VerifyChangeAddresses(d, outputs, handle); err != nil {
+			return wire.Message{}, err
+		}
+	}
+
 	if err := d.Connect(); err != nil {
 		return wire.Message{}, err
 	}
@@ -760,7 +747,7 @@ func (d *Device) TransactionSign(inputs []*messages.SkycoinTransactionInput, out
 		return wire.Message{}, err
 	}
 
-	return d.Driver.SendToDevice(d.dev, transactionSignChunks)
+	return d.sendToDevice(transactionSignChunks)
 }
 
 // Wipe wipes out device configuration
@@ -775,7 +762,7 @@ func (d *Device) Wipe() (wire.Message, error) {
 		return wire.Message{}, err
 	}
 
-	msg, err := d.Driver.SendToDevice(d.dev, wipeChunks)
+	msg, err := d.sendToDevice(wipeChunks)
 	if err != nil {
 		return wire.Message{}, err
 	}
@@ -813,35 +800,12 @@ func (d *Device) ButtonAck() (wire.Message, error) {
 	if err != nil {
 		return wire.Message{}, err
 	}
-	for msg.Kind == uint16(messages.MessageType_MessageType_EntropyRequest) {
-		var wg sync.WaitGroup
-		wg.Add(1)
-
-		go func() {
-			defer wg.Done()
-			entropyChunks, err := MessageEntropyAck(entropyBufferSize)
-			if err != nil {
-				log.Errorf("failed to create entropy ack msg: %v", err)
-				return
-			}
-
-			for _, element := range entropyChunks {
-				_, err := d.dev.Write(element[:])
-				if err != nil {
-					log.Errorf("entropy ack error: %v", err)
-					return
-				}
-			}
-		}()
-
-		msg, err = wire.ReadFrom(d.dev)
-		if err != nil {
-			return wire.Message{}, err
-		}
-		wg.Wait()
+	msg, err = ackEntropyRequests(d.dev, msg)
+	if err != nil {
+		return wire.Message{}, err
 	}
 
-	return *msg, err
+	return d.ackPassphraseState(*msg)
 }
 
 // PassphraseAck send this message when the device is waiting for the user to input a passphrase
@@ -856,7 +820,7 @@ func (d *Device) PassphraseAck(passphrase string) (wire.Message, error) {
 		return wire.Message{}, err
 	}
 
-	return d.Driver.SendToDevice(d.dev, passphraseChunks)
+	return d.sendSecretToDevice(passphraseChunks)
 }
 
 // WordAck send a word to the device during device "recovery procedure"
@@ -871,7 +835,7 @@ func (d *Device) WordAck(word string) (wire.Message, error) {
 		return wire.Message{}, err
 	}
 
-	return d.Driver.SendToDevice(d.dev, wordAckChunks)
+	return d.sendSecretToDevice(wordAckChunks)
 }
 
 // PinMatrixAck during PIN code setting use this message to send user input to device
@@ -882,14 +846,12 @@ func (d *Device) PinMatrixAck(p string) (wire.Message, error) {
 	}
 	defer d.Disconnect()
 
-	log.Printf("Setting pin: %s\n", p)
-
 	pinMatrixChunks, err := MessagePinMatrixAck(p)
 	if err != nil {
 		return wire.Message{}, err
 	}
 
-	return d.Driver.SendToDevice(d.dev, pinMatrixChunks)
+	return d.sendSecretToDevice(pinMatrixChunks)
 }
 
 // SimulateButtonPress simulates a button press on emulator
@@ -911,6 +873,36 @@ func (d *Device) SimulateButtonPress() error {
 	return nil
 }
 
+// SetProgressReporter sets the reporter notified by long running operations,
+// a nil reporter discards all progress updates
+func (d *Device) SetProgressReporter(reporter ProgressReporter) {
+	if reporter == nil {
+		reporter = NopProgressReporter{}
+	}
+	d.progress = reporter
+}
+
+// sendToDeviceWithProgress sends chunks in batches reporting how many of them were written
+func (d *Device) sendToDeviceWithProgress(chunks [][64]byte) (wire.Message, error) {
+	const batchSize = 64
+	d.progress.Start(len(chunks))
+	defer d.progress.Done()
+	sent := 0
+	for len(chunks)-sent > batchSize {
+		if err := d.Driver.SendToDeviceNoAnswer(d.dev, chunks[sent:sent+batchSize]); err != nil {
+			return wire.Message{}, err
+		}
+		sent += batchSize
+		d.progress.Update(sent)
+	}
+	msg, err := d.sendToDevice(chunks[sent:])
+	if err != nil {
+		return wire.Message{}, err
+	}
+	d.progress.Update(len(chunks))
+	return msg, nil
+}
+
 // SetAutoPressButton enables and sets button press type
 func (d *Device) SetAutoPressButton(simulateButtonPress bool, simulateButtonType ButtonType) error {
 	if d.Driver.DeviceType() == DeviceTypeEmulator {
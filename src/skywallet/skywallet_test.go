@@ -2,11 +2,17 @@ package skywallet
 
 import (
 	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
 	messages "github.com/skycoin/hardware-wallet-protob/go"
 
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/codec"
 	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
 
 	"github.com/stretchr/testify/require"
@@ -133,6 +139,58 @@ func (suite *devicerSuit) TestBackup() {
 	require.Equal(suite.T(), msg.Kind, uint16(messages.MessageType_MessageType_Success))
 }
 
+func (suite *devicerSuit) TestCall() {
+	// NOTE: Giving
+	kind := uint16(messages.MessageType_MessageType_Ping)
+	msg := &messages.Ping{Message: proto.String("hello")}
+	data, err := proto.Marshal(msg)
+	suite.Nil(err)
+	chunks, err := codec.Chunk(kind, data)
+	suite.Nil(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, chunks).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Success), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	resp, err := device.Call(kind, msg)
+
+	// NOTE: Assert
+	suite.Nil(err)
+	driverMock.AssertCalled(suite.T(), "GetDevice")
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 1)
+	mock.AssertExpectationsForObjects(suite.T(), driverMock)
+	require.Equal(suite.T(), resp.Kind, uint16(messages.MessageType_MessageType_Success))
+}
+
+func (suite *devicerSuit) TestCallWipesSecretChunks() {
+	// NOTE: Giving
+	var sent [][64]byte
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(1).([][64]byte)
+		require.NotEqual(suite.T(), [64]byte{}, sent[0])
+	}).Return(wire.Message{Kind: uint16(messages.MessageType_MessageType_Success), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	_, err := device.Call(uint16(messages.MessageType_MessageType_PinMatrixAck), &messages.PinMatrixAck{Pin: proto.String("1234")})
+
+	// NOTE: Assert
+	suite.Nil(err)
+	require.NotEmpty(suite.T(), sent)
+	for _, chunk := range sent {
+		require.Equal(suite.T(), [64]byte{}, chunk)
+	}
+
+	// NOTE: the chunks of other kinds are left untouched
+	_, err = device.Call(uint16(messages.MessageType_MessageType_Ping), &messages.Ping{Message: proto.String("hello")})
+	suite.Nil(err)
+	require.NotEqual(suite.T(), [64]byte{}, sent[0])
+}
+
 func (suite *devicerSuit) TestCancel() {
 	// NOTE(denisacostaq@gmail.com): Giving
 	driverMock := &MockDeviceDriver{}
@@ -235,6 +293,74 @@ func (suite *devicerSuit) TestGenerateMnemonic() {
 	mock.AssertExpectationsForObjects(suite.T(), driverMock)
 }
 
+func (suite *devicerSuit) TestLoadDevice() {
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Success), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+
+	tt := []struct {
+		name     string
+		mnemonic string
+		node     *messages.HDNodeType
+		err      error
+		msgKind  uint16
+	}{
+		{
+			name:    "no seed",
+			err:     ErrNoSeed,
+			msgKind: 0,
+		},
+		{
+			name:     "mnemonic",
+			mnemonic: "cloud flower upset remain green metal below cup stem infant art thank",
+			msgKind:  uint16(messages.MessageType_MessageType_Success),
+		},
+		{
+			name: "node",
+			node: &messages.HDNodeType{
+				Depth:       proto.Uint32(0),
+				Fingerprint: proto.Uint32(0),
+				ChildNum:    proto.Uint32(0),
+				ChainCode:   make([]byte, 32),
+			},
+			msgKind: uint16(messages.MessageType_MessageType_Success),
+		},
+	}
+
+	for _, tc := range tt {
+		msg, err := device.LoadDevice(tc.mnemonic, tc.node, "", "", false, false)
+		suite.Equal(tc.err, err, tc.name)
+		suite.Equal(tc.msgKind, msg.Kind, tc.name)
+	}
+
+	driverMock.AssertCalled(suite.T(), "GetDevice")
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 2)
+	mock.AssertExpectationsForObjects(suite.T(), driverMock)
+}
+
+func (suite *devicerSuit) TestPing() {
+	// NOTE: Giving
+	chunks, err := MessagePing("hello", true, false, false)
+	suite.Nil(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, chunks).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_ButtonRequest), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	msg, err := device.Ping("hello", true, false, false)
+
+	// NOTE: Assert
+	suite.Nil(err)
+	driverMock.AssertCalled(suite.T(), "GetDevice")
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 1)
+	mock.AssertExpectationsForObjects(suite.T(), driverMock)
+	require.Equal(suite.T(), uint16(messages.MessageType_MessageType_ButtonRequest), msg.Kind)
+}
+
 func (suite *devicerSuit) TestRecovery() {
 	driverMock := &MockDeviceDriver{}
 	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
@@ -275,6 +401,54 @@ func (suite *devicerSuit) TestRecovery() {
 	mock.AssertExpectationsForObjects(suite.T(), driverMock)
 }
 
+func (suite *devicerSuit) TestResetDevice() {
+	chunks12, err := MessageResetDevice(128, false, false, false, false, "")
+	suite.Nil(err)
+	chunks24, err := MessageResetDevice(256, false, false, false, false, "")
+	suite.Nil(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, chunks12).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_ButtonRequest), Data: nil}, nil)
+	driverMock.On("SendToDevice", mock.Anything, chunks24).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_ButtonRequest), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+
+	tt := []struct {
+		name      string
+		wordCount uint32
+		err       error
+		msgKind   uint16
+	}{
+		{
+			name:      "invalid word count",
+			wordCount: 18,
+			err:       ErrInvalidWordCount,
+			msgKind:   0,
+		},
+		{
+			name:      "12 words",
+			wordCount: 12,
+			msgKind:   uint16(messages.MessageType_MessageType_ButtonRequest),
+		},
+		{
+			name:      "24 words",
+			wordCount: 24,
+			msgKind:   uint16(messages.MessageType_MessageType_ButtonRequest),
+		},
+	}
+
+	for _, tc := range tt {
+		msg, err := device.ResetDevice(tc.wordCount, false, false, false, false, "")
+		suite.Equal(tc.err, err, tc.name)
+		suite.Equal(tc.msgKind, msg.Kind, tc.name)
+	}
+
+	driverMock.AssertCalled(suite.T(), "GetDevice")
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 2)
+	mock.AssertExpectationsForObjects(suite.T(), driverMock)
+}
+
 func (suite *devicerSuit) TestSetMnemonic() {
 	// NOTE(denisacostaq@gmail.com): Giving
 	driverMock := &MockDeviceDriver{}
@@ -368,6 +542,76 @@ func (suite *devicerSuit) TestWipe() {
 	require.Equal(suite.T(), msg.Kind, uint16(messages.MessageType_MessageType_Success))
 }
 
+func (suite *devicerSuit) TestEntropyReader() {
+	// NOTE: Giving
+	chunk := []byte{1, 2, 3}
+	data, err := proto.Marshal(&messages.Entropy{Entropy: chunk})
+	suite.NoError(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Entropy), Data: data}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	r, err := device.EntropyReader(EntropyTypeRaw)
+	suite.NoError(err)
+	buf := make([]byte, 8)
+	_, err = io.ReadFull(r, buf)
+
+	// NOTE: Assert
+	suite.NoError(err)
+	suite.Equal([]byte{1, 2, 3, 1, 2, 3, 1, 2}, buf)
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 3)
+	mock.AssertExpectationsForObjects(suite.T(), driverMock)
+}
+
+func (suite *devicerSuit) TestSaveDeviceEntropyInFile() {
+	// NOTE: Giving
+	data, err := proto.Marshal(&messages.Entropy{Entropy: []byte{1, 2, 3}})
+	suite.NoError(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Entropy), Data: data}, nil)
+	device := getMockDevice(driverMock)
+	dir, err := ioutil.TempDir("", "entropy")
+	suite.NoError(err)
+	defer os.RemoveAll(dir)
+	outFile := filepath.Join(dir, "entropy.bin")
+
+	// NOTE: When
+	err = device.SaveDeviceEntropyInFile(outFile, 5, MessageDeviceGetRawEntropy)
+
+	// NOTE: Assert
+	suite.NoError(err)
+	saved, err := ioutil.ReadFile(outFile)
+	suite.NoError(err)
+	suite.Equal([]byte{1, 2, 3, 1, 2}, saved)
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 2)
+}
+
+func (suite *devicerSuit) TestEntropyReaderFailure() {
+	// NOTE: Giving
+	data, err := proto.Marshal(&messages.Failure{Message: proto.String("entropy not available")})
+	suite.NoError(err)
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Failure), Data: data}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	r, err := device.EntropyReader(EntropyTypeMixed)
+	suite.NoError(err)
+	_, err = r.Read(make([]byte, 4))
+
+	// NOTE: Assert
+	suite.EqualError(err, "entropy not available")
+	_, err = device.EntropyReader(EntropyType(-1))
+	suite.Equal(ErrInvalidEntropyType, err)
+}
+
 func getMockDevice(mock *MockDeviceDriver) Device {
-	return Device{mock, sync.Mutex{}, nil, false, false, ButtonType(-1)}
+	return Device{mock, sync.Mutex{}, nil, false, false, ButtonType(-1), NopProgressReporter{}, nil, false, nil, 0}
 }
@@ -0,0 +1,61 @@
+package skywallet
+
+import (
+	"fmt"
+)
+
+// DefaultTransactionSignLimit is the maximum number of inputs and of outputs
+// the firmware accepts in a TransactionSign message. Features does not report
+// it, see SetTransactionSignLimit for firmwares built with another limit.
+const DefaultTransactionSignLimit = 8
+
+// TransactionLimitError is returned by TransactionSign if a transaction has
+// more inputs or outputs than the device accepts.
+//
+// Signing the inputs over several requests is not supported: the signature of
+// each input commits to the hash of all the inputs and outputs, which the
+// device computes from the inputs it is sent. The unspent outputs have to be
+// consolidated first, by transactions sending at most Limit of them to an
+// address of the wallet.
+type TransactionLimitError struct {
+	Inputs  int
+	Outputs int
+	Limit   int
+}
+
+func (e *TransactionLimitError) Error() string {
+	if e.Inputs > e.Limit {
+		return fmt.Sprintf("transaction has %d inputs but the device signs at most %d and large transactions are not split over several requests, consolidate the outputs first by sending at most %d of them to an address of the wallet per transaction",
+			e.Inputs, e.Limit, e.Limit)
+	}
+	return fmt.Sprintf("transaction has %d outputs but the device signs at most %d, send to fewer outputs per transaction",
+		e.Outputs, e.Limit)
+}
+
+// SetTransactionSignLimit sets the maximum number of inputs and of outputs
+// TransactionSign sends to the device, 0 restores DefaultTransactionSignLimit
+func (d *Device) SetTransactionSignLimit(limit int) {
+	d.Lock()
+	defer d.Unlock()
+	d.transactionSignLimit = limit
+}
+
+// TransactionSignLimit returns the maximum number of inputs and of outputs
+// TransactionSign sends to the device
+func (d *Device) TransactionSignLimit() int {
+	d.Lock()
+	defer d.Unlock()
+	if d.transactionSignLimit <= 0 {
+		return DefaultTransactionSignLimit
+	}
+	return d.transactionSignLimit
+}
+
+// checkTransactionSignLimit returns a TransactionLimitError if the transaction
+// has more than limit inputs or outputs
+func checkTransactionSignLimit(inputs, outputs, limit int) error {
+	if inputs > limit || outputs > limit {
+		return &TransactionLimitError{Inputs: inputs, Outputs: outputs, Limit: limit}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package skywallet
+
+import (
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func limitInputs(n int) []*messages.SkycoinTransactionInput {
+	inputs := make([]*messages.SkycoinTransactionInput, n)
+	for i := range inputs {
+		inputs[i] = &messages.SkycoinTransactionInput{
+			HashIn: proto.String("c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59"),
+			Index:  proto.Uint32(0),
+		}
+	}
+	return inputs
+}
+
+func (suite *devicerSuit) TestTransactionSignLimit() {
+	// NOTE: Giving
+	driverMock := &MockDeviceDriver{}
+	driverMock.On("GetDevice").Return(&testHelperCloseableBuffer{}, nil)
+	driverMock.On("SendToDevice", mock.Anything, mock.Anything).Return(
+		wire.Message{Kind: uint16(messages.MessageType_MessageType_Success), Data: nil}, nil)
+	device := getMockDevice(driverMock)
+
+	// NOTE: When
+	_, err := device.TransactionSign(limitInputs(DefaultTransactionSignLimit+1), nil)
+
+	// NOTE: Assert
+	suite.Equal(&TransactionLimitError{Inputs: DefaultTransactionSignLimit + 1, Limit: DefaultTransactionSignLimit}, err)
+	suite.Contains(err.Error(), "consolidate the outputs first")
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 0)
+
+	_, err = device.TransactionSign(limitInputs(DefaultTransactionSignLimit), nil)
+	suite.Nil(err)
+	driverMock.AssertNumberOfCalls(suite.T(), "SendToDevice", 1)
+
+	// NOTE: the limit is configurable
+	device.SetTransactionSignLimit(2)
+	suite.Equal(2, device.TransactionSignLimit())
+	_, err = device.TransactionSign(limitInputs(3), nil)
+	suite.Equal(&TransactionLimitError{Inputs: 3, Limit: 2}, err)
+
+	device.SetTransactionSignLimit(0)
+	suite.Equal(DefaultTransactionSignLimit, device.TransactionSignLimit())
+}
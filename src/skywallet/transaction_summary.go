@@ -0,0 +1,215 @@
+package skywallet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+)
+
+const (
+	// DropletsPerSky is the number of droplets, the smallest unit of the amounts, in one SKY
+	DropletsPerSky = 1000000
+	// dropletDecimals is the number of decimals of an amount in SKY
+	dropletDecimals = 6
+)
+
+var (
+	// ErrAmountOverflow is returned if the sum of coins or hours overflows
+	ErrAmountOverflow = errors.New("sum of the amounts overflows")
+	// ErrInputValuesCount is returned if the input values do not match the inputs one to one
+	ErrInputValuesCount = errors.New("every input must have a value when input values are given")
+)
+
+// InputValue holds the coins and the hours of the unspent output spent by an input
+type InputValue struct {
+	Coins uint64 `json:"coins"`
+	Hours uint64 `json:"hours"`
+}
+
+// InputSummary describes an input of a transaction
+type InputSummary struct {
+	Hash string `json:"hash"`
+	// AddressIndex is the index in the wallet of the address owning the input
+	AddressIndex uint32 `json:"address_index"`
+	// Value is nil if the coins and hours of the input are unknown
+	Value *InputValue `json:"value,omitempty"`
+}
+
+// OutputSummary describes an output of a transaction
+type OutputSummary struct {
+	Address string `json:"address"`
+	Coins   uint64 `json:"coins"`
+	Hours   uint64 `json:"hours"`
+	// Change is set if the output goes back to the wallet at AddressIndex
+	Change       bool    `json:"change"`
+	AddressIndex *uint32 `json:"address_index,omitempty"`
+}
+
+// TransactionSummary describes a transaction before it is signed, the coins are in droplets
+type TransactionSummary struct {
+	Inputs  []InputSummary  `json:"inputs"`
+	Outputs []OutputSummary `json:"outputs"`
+	// Spent and SpentHours are the coins and hours sent to other wallets
+	Spent      uint64 `json:"spent"`
+	SpentHours uint64 `json:"spent_hours"`
+	// Change and ChangeHours are the coins and hours going back to the wallet
+	Change      uint64 `json:"change"`
+	ChangeHours uint64 `json:"change_hours"`
+	// InputCoins, InputHours and BurnedHours are nil if the input values are unknown
+	InputCoins  *uint64 `json:"input_coins,omitempty"`
+	InputHours  *uint64 `json:"input_hours,omitempty"`
+	BurnedHours *uint64 `json:"burned_hours,omitempty"`
+}
+
+// NewTransactionSummary sums the outputs of a transaction. values holds the
+// coins and hours of each input, the hours burned are only known if they are given.
+func NewTransactionSummary(inputs []*messages.SkycoinTransactionInput, outputs []*messages.SkycoinTransactionOutput, values []InputValue) (*TransactionSummary, error) {
+	if values != nil && len(values) != len(inputs) {
+		return nil, ErrInputValuesCount
+	}
+
+	s := &TransactionSummary{
+		Inputs:  make([]InputSummary, len(inputs)),
+		Outputs: make([]OutputSummary, len(outputs)),
+	}
+	for i, in := range inputs {
+		s.Inputs[i] = InputSummary{
+			Hash:         in.GetHashIn(),
+			AddressIndex: in.GetIndex(),
+		}
+	}
+
+	var err error
+	for i, out := range outputs {
+		o := OutputSummary{
+			Address:      out.GetAddress(),
+			Coins:        out.GetCoin(),
+			Hours:        out.GetHour(),
+			Change:       out.AddressIndex != nil,
+			AddressIndex: out.AddressIndex,
+		}
+		if o.Change {
+			s.Change, err = addAmounts(s.Change, o.Coins)
+			if err == nil {
+				s.ChangeHours, err = addAmounts(s.ChangeHours, o.Hours)
+			}
+		} else {
+			s.Spent, err = addAmounts(s.Spent, o.Coins)
+			if err == nil {
+				s.SpentHours, err = addAmounts(s.SpentHours, o.Hours)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("output %d: %v", i+1, err)
+		}
+		s.Outputs[i] = o
+	}
+
+	if values == nil {
+		return s, nil
+	}
+
+	var inputCoins, inputHours uint64
+	for i := range values {
+		s.Inputs[i].Value = &values[i]
+		inputCoins, err = addAmounts(inputCoins, values[i].Coins)
+		if err == nil {
+			inputHours, err = addAmounts(inputHours, values[i].Hours)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %v", i+1, err)
+		}
+	}
+	outputCoins, err := addAmounts(s.Spent, s.Change)
+	if err != nil {
+		return nil, err
+	}
+	outputHours, err := addAmounts(s.SpentHours, s.ChangeHours)
+	if err != nil {
+		return nil, err
+	}
+	if inputCoins != outputCoins {
+		return nil, fmt.Errorf("inputs hold %s SKY but outputs send %s SKY", FormatDroplets(inputCoins), FormatDroplets(outputCoins))
+	}
+	if inputHours < outputHours {
+		return nil, fmt.Errorf("inputs hold %d hours but outputs send %d hours", inputHours, outputHours)
+	}
+	burned := inputHours - outputHours
+	s.InputCoins = &inputCoins
+	s.InputHours = &inputHours
+	s.BurnedHours = &burned
+	return s, nil
+}
+
+// Write prints the summary for the user to review before signing
+func (s *TransactionSummary) Write(w io.Writer) {
+	fmt.Fprintln(w, "Inputs:")
+	for i, in := range s.Inputs {
+		fmt.Fprintf(w, "%4d  %s  address index %d", i+1, in.Hash, in.AddressIndex)
+		if in.Value != nil {
+			fmt.Fprintf(w, "  %s SKY  %d hours", FormatDroplets(in.Value.Coins), in.Value.Hours)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, "Outputs:")
+	for i, out := range s.Outputs {
+		fmt.Fprintf(w, "%4d  %-35s  %s SKY  %d hours", i+1, out.Address, FormatDroplets(out.Coins), out.Hours)
+		if out.Change {
+			fmt.Fprintf(w, "  change, address index %d", *out.AddressIndex)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "Total spent: %s SKY, %d hours\n", FormatDroplets(s.Spent), s.SpentHours)
+	fmt.Fprintf(w, "Change: %s SKY, %d hours\n", FormatDroplets(s.Change), s.ChangeHours)
+	if s.BurnedHours != nil {
+		fmt.Fprintf(w, "Hours burned: %d\n", *s.BurnedHours)
+	} else {
+		fmt.Fprintln(w, "Hours burned: unknown, the coins and hours of the inputs were not given")
+	}
+}
+
+// FormatDroplets formats an amount of droplets in SKY, without trailing zeroes
+func FormatDroplets(droplets uint64) string {
+	sky := strconv.FormatUint(droplets/DropletsPerSky, 10)
+	decimals := strings.TrimRight(fmt.Sprintf("%0*d", dropletDecimals, droplets%DropletsPerSky), "0")
+	if decimals == "" {
+		return sky
+	}
+	return sky + "." + decimals
+}
+
+// ParseDroplets parses an amount of SKY, such as the coins listed by a node,
+// in droplets
+func ParseDroplets(sky string) (uint64, error) {
+	parts := strings.SplitN(sky, ".", 2)
+	whole, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %v", sky, err)
+	}
+	var decimals uint64
+	if len(parts) == 2 {
+		if len(parts[1]) == 0 || len(parts[1]) > dropletDecimals {
+			return 0, fmt.Errorf("invalid amount %q: at most %d decimals", sky, dropletDecimals)
+		}
+		decimals, err = strconv.ParseUint(parts[1]+strings.Repeat("0", dropletDecimals-len(parts[1])), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %q: %v", sky, err)
+		}
+	}
+	if whole > (^uint64(0)-decimals)/DropletsPerSky {
+		return 0, ErrAmountOverflow
+	}
+	return whole*DropletsPerSky + decimals, nil
+}
+
+// addAmounts returns a + b or ErrAmountOverflow
+func addAmounts(a, b uint64) (uint64, error) {
+	if a+b < a {
+		return 0, ErrAmountOverflow
+	}
+	return a + b, nil
+}
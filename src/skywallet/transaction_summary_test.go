@@ -0,0 +1,130 @@
+package skywallet
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+)
+
+func summaryTransaction() ([]*messages.SkycoinTransactionInput, []*messages.SkycoinTransactionOutput) {
+	inputs := []*messages.SkycoinTransactionInput{
+		{HashIn: proto.String("c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59"), Index: proto.Uint32(0)},
+		{HashIn: proto.String("ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da"), Index: proto.Uint32(1)},
+	}
+	outputs := []*messages.SkycoinTransactionOutput{
+		{Address: proto.String("K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot"), Coin: proto.Uint64(10000000), Hour: proto.Uint64(4)},
+		{Address: proto.String("VNz8LR9JTSoz5o7qPHm3QHj4EiJB6LV18L"), Coin: proto.Uint64(5500000), Hour: proto.Uint64(2)},
+		{Address: proto.String("2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"), Coin: proto.Uint64(4500000), Hour: proto.Uint64(1), AddressIndex: proto.Uint32(0)},
+	}
+	return inputs, outputs
+}
+
+func (suite *devicerSuit) TestTransactionSummary() {
+	// NOTE: Giving
+	inputs, outputs := summaryTransaction()
+
+	// NOTE: When
+	summary, err := NewTransactionSummary(inputs, outputs, nil)
+
+	// NOTE: Assert
+	suite.Nil(err)
+	suite.Equal(uint64(15500000), summary.Spent)
+	suite.Equal(uint64(6), summary.SpentHours)
+	suite.Equal(uint64(4500000), summary.Change)
+	suite.Equal(uint64(1), summary.ChangeHours)
+	suite.Nil(summary.BurnedHours)
+	suite.False(summary.Outputs[0].Change)
+	suite.True(summary.Outputs[2].Change)
+	suite.Equal(uint32(1), summary.Inputs[1].AddressIndex)
+
+	var out bytes.Buffer
+	summary.Write(&out)
+	suite.Contains(out.String(), "Total spent: 15.5 SKY, 6 hours")
+	suite.Contains(out.String(), "change, address index 0")
+	suite.Contains(out.String(), "Hours burned: unknown")
+}
+
+func (suite *devicerSuit) TestTransactionSummaryInputValues() {
+	// NOTE: Giving
+	inputs, outputs := summaryTransaction()
+	values := []InputValue{{Coins: 12000000, Hours: 10}, {Coins: 8000000, Hours: 4}}
+
+	// NOTE: When
+	summary, err := NewTransactionSummary(inputs, outputs, values)
+
+	// NOTE: Assert
+	suite.Nil(err)
+	suite.Equal(uint64(20000000), *summary.InputCoins)
+	suite.Equal(uint64(14), *summary.InputHours)
+	suite.Equal(uint64(7), *summary.BurnedHours)
+	suite.Equal(values[1], *summary.Inputs[1].Value)
+
+	_, err = NewTransactionSummary(inputs, outputs, values[:1])
+	suite.Equal(ErrInputValuesCount, err)
+
+	values[0].Coins++
+	_, err = NewTransactionSummary(inputs, outputs, values)
+	suite.EqualError(err, "inputs hold 20.000001 SKY but outputs send 20 SKY")
+
+	values[0] = InputValue{Coins: 12000000, Hours: 2}
+	_, err = NewTransactionSummary(inputs, outputs, values)
+	suite.EqualError(err, "inputs hold 6 hours but outputs send 7 hours")
+}
+
+func (suite *devicerSuit) TestTransactionSummaryOverflow() {
+	// NOTE: Giving
+	inputs, outputs := summaryTransaction()
+	outputs[1].Coin = proto.Uint64(math.MaxUint64)
+
+	// NOTE: When
+	_, err := NewTransactionSummary(inputs, outputs, nil)
+
+	// NOTE: Assert
+	suite.EqualError(err, "output 2: "+ErrAmountOverflow.Error())
+}
+
+func TestFormatDroplets(t *testing.T) {
+	tt := []struct {
+		droplets uint64
+		sky      string
+	}{
+		{0, "0"},
+		{1, "0.000001"},
+		{1000000, "1"},
+		{1900000, "1.9"},
+		{111000000, "111"},
+		{123456789, "123.456789"},
+	}
+	for _, tc := range tt {
+		require.Equal(t, tc.sky, FormatDroplets(tc.droplets))
+	}
+}
+
+func TestParseDroplets(t *testing.T) {
+	tt := []struct {
+		sky      string
+		droplets uint64
+	}{
+		{"0", 0},
+		{"0.000001", 1},
+		{"1", 1000000},
+		{"1.9", 1900000},
+		{"2.000000", 2000000},
+		{"123.456789", 123456789},
+	}
+	for _, tc := range tt {
+		droplets, err := ParseDroplets(tc.sky)
+		require.NoError(t, err)
+		require.Equal(t, tc.droplets, droplets)
+	}
+
+	for _, invalid := range []string{"", "1.", ".5", "1.0000001", "-1", "1.-5", "18446744073709.551616"} {
+		_, err := ParseDroplets(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -35,6 +35,7 @@ const (
 	TypeT2           DeviceType = 3
 	TypeT2Boot       DeviceType = 4
 	TypeEmulator     DeviceType = 5
+	TypeReplay       DeviceType = 6
 )
 
 type Info struct {
@@ -0,0 +1,323 @@
+package usb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	tracePrefix = "replay"
+
+	// headerLen is the length of the header of the first packet of a
+	// message, ?## followed by the message kind and the payload size
+	headerLen = 9
+)
+
+var (
+	ErrTraceExhausted = errors.New("no more packets in trace")
+	ErrTraceMismatch  = errors.New("written packet does not match trace")
+)
+
+// TraceDirection tells if a packet was sent to or received from the device
+type TraceDirection string
+
+const (
+	// TraceOut packet written to the device
+	TraceOut TraceDirection = "out"
+	// TraceIn packet read from the device
+	TraceIn TraceDirection = "in"
+)
+
+// TracePacket is a single packet exchanged with the device
+type TracePacket struct {
+	Time      time.Time      `json:"time"`
+	Direction TraceDirection `json:"direction"`
+	Data      TraceData      `json:"data"`
+}
+
+// TraceData is the packet payload, hex encoded in trace files
+type TraceData []byte
+
+// MarshalJSON implements json.Marshaler
+func (d TraceData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(d))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *TraceData) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*d = data
+	return nil
+}
+
+// ReadTrace parses a trace file, one JSON encoded TracePacket per line
+func ReadTrace(r io.Reader) ([]TracePacket, error) {
+	var packets []TracePacket
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var p TracePacket
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			return nil, fmt.Errorf("trace line %d: %v", line, err)
+		}
+		if p.Direction != TraceOut && p.Direction != TraceIn {
+			return nil, fmt.Errorf("trace line %d: invalid direction %q", line, p.Direction)
+		}
+		packets = append(packets, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return packets, nil
+}
+
+// TraceWriter writes packets to a trace file,
+// it can be shared by several recorders
+type TraceWriter struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	secret func(kind uint16) bool
+}
+
+// NewTraceWriter creates a TraceWriter writing to w
+func NewTraceWriter(w io.Writer) *TraceWriter {
+	return &TraceWriter{
+		enc: json.NewEncoder(w),
+	}
+}
+
+// Record writes a packet with the current time
+func (tw *TraceWriter) Record(direction TraceDirection, data []byte) error {
+	p := TracePacket{
+		Time:      time.Now().UTC(),
+		Direction: direction,
+		Data:      append(TraceData(nil), data...),
+	}
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.enc.Encode(p)
+}
+
+// RedactKinds makes the recorders writing with tw blank the payload of the
+// messages whose kind secret reports, such as the ones carrying a mnemonic or
+// a PIN. The packet headers are kept so the trace can still be dissected.
+func (tw *TraceWriter) RedactKinds(secret func(kind uint16) bool) {
+	tw.secret = secret
+}
+
+// redactor blanks the payload of the secret messages going in one direction
+type redactor struct {
+	// left is the amount of payload bytes of a secret message not seen yet
+	left int
+}
+
+// packet returns data, or a copy with the payload blanked if it belongs to
+// a message whose kind secret reports
+func (r *redactor) packet(data []byte, secret func(kind uint16) bool) []byte {
+	if secret == nil || len(data) == 0 {
+		return data
+	}
+	start := 1
+	if r.left == 0 {
+		if len(data) < headerLen || !bytes.HasPrefix(data, []byte("?##")) {
+			return data
+		}
+		if !secret(binary.BigEndian.Uint16(data[3:5])) {
+			return data
+		}
+		r.left = int(binary.BigEndian.Uint32(data[5:headerLen]))
+		start = headerLen
+	}
+	end := start + r.left
+	if end > len(data) {
+		end = len(data)
+	}
+	if end <= start {
+		return data
+	}
+	blanked := append([]byte(nil), data...)
+	for i := start; i < end; i++ {
+		blanked[i] = 0
+	}
+	r.left -= end - start
+	return blanked
+}
+
+// TraceRecorder is a Device recording every packet written and read
+type TraceRecorder struct {
+	dev Device
+	tw  *TraceWriter
+	out redactor
+	in  redactor
+}
+
+// NewTraceRecorder wraps dev recording its traffic with tw
+func NewTraceRecorder(dev Device, tw *TraceWriter) *TraceRecorder {
+	return &TraceRecorder{
+		dev: dev,
+		tw:  tw,
+	}
+}
+
+func (d *TraceRecorder) Write(buf []byte) (int, error) {
+	n, err := d.dev.Write(buf)
+	if n > 0 {
+		if rerr := d.tw.Record(TraceOut, d.out.packet(buf[:n], d.tw.secret)); rerr != nil {
+			log.Errorf("trace record error: %v", rerr)
+		}
+	}
+	return n, err
+}
+
+func (d *TraceRecorder) Read(buf []byte) (int, error) {
+	n, err := d.dev.Read(buf)
+	if n > 0 {
+		if rerr := d.tw.Record(TraceIn, d.in.packet(buf[:n], d.tw.secret)); rerr != nil {
+			log.Errorf("trace record error: %v", rerr)
+		}
+	}
+	return n, err
+}
+
+func (d *TraceRecorder) Close(disconnected bool) error {
+	return d.dev.Close(disconnected)
+}
+
+// TraceReplayer is a fake Device serving the packets read in a trace.
+// Reads return the recorded incoming packets in order, writes are checked
+// against the recorded outgoing packets if strict is set.
+// Closing the device keeps the replay position so a session spanning
+// several connections can be replayed.
+type TraceReplayer struct {
+	mu     sync.Mutex
+	in     []TracePacket
+	out    []TracePacket
+	strict bool
+}
+
+// NewTraceReplayer creates a TraceReplayer serving packets
+func NewTraceReplayer(packets []TracePacket, strict bool) *TraceReplayer {
+	r := &TraceReplayer{
+		strict: strict,
+	}
+	for _, p := range packets {
+		switch p.Direction {
+		case TraceIn:
+			r.in = append(r.in, p)
+		case TraceOut:
+			r.out = append(r.out, p)
+		}
+	}
+	return r
+}
+
+func (d *TraceReplayer) Write(buf []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.out) == 0 {
+		return 0, ErrTraceExhausted
+	}
+	p := d.out[0]
+	d.out = d.out[1:]
+	if d.strict && !bytes.Equal(p.Data, buf) {
+		return 0, ErrTraceMismatch
+	}
+	return len(buf), nil
+}
+
+func (d *TraceReplayer) Read(buf []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.in) == 0 {
+		return 0, io.EOF
+	}
+	p := d.in[0]
+	d.in = d.in[1:]
+	return copy(buf, p.Data), nil
+}
+
+func (d *TraceReplayer) Close(disconnected bool) error {
+	return nil
+}
+
+// Remaining returns the amount of incoming and outgoing packets not replayed yet
+func (d *TraceReplayer) Remaining() (in, out int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.in), len(d.out)
+}
+
+// ReplayBus is a Bus with a single TraceReplayer device
+type ReplayBus struct {
+	dev *TraceReplayer
+}
+
+// InitReplay creates a ReplayBus connecting to dev
+func InitReplay(dev *TraceReplayer) *ReplayBus {
+	return &ReplayBus{
+		dev: dev,
+	}
+}
+
+func (b *ReplayBus) Enumerate(_, _ uint16) ([]Info, error) {
+	return []Info{{
+		Path: tracePrefix,
+		Type: TypeReplay,
+	}}, nil
+}
+
+func (b *ReplayBus) Has(path string) bool {
+	return path == tracePrefix
+}
+
+func (b *ReplayBus) Connect(path string) (Device, error) {
+	if !b.Has(path) {
+		return nil, ErrNotFound
+	}
+	return b.dev, nil
+}
+
+func (b *ReplayBus) Close() {
+	// nothing
+}
+
+// RecordingBus wraps a Bus recording the traffic of every connected device
+type RecordingBus struct {
+	Bus
+	tw *TraceWriter
+}
+
+// InitRecording creates a RecordingBus over bus writing the trace with tw
+func InitRecording(bus Bus, tw *TraceWriter) *RecordingBus {
+	return &RecordingBus{
+		Bus: bus,
+		tw:  tw,
+	}
+}
+
+func (b *RecordingBus) Connect(path string) (Device, error) {
+	dev, err := b.Bus.Connect(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewTraceRecorder(dev, b.tw), nil
+}
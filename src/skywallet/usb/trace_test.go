@@ -0,0 +1,77 @@
+package usb
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceRecordAndReplay(t *testing.T) {
+	out := []byte("?##request")
+	in := []byte("?##response")
+
+	// record a session against a replayer serving the expected response
+	device := NewTraceReplayer([]TracePacket{
+		{Direction: TraceOut, Data: out},
+		{Direction: TraceIn, Data: in},
+	}, true)
+	var trace bytes.Buffer
+	recorder := NewTraceRecorder(device, NewTraceWriter(&trace))
+	_, err := recorder.Write(out)
+	require.NoError(t, err)
+	buf := make([]byte, 64)
+	n, err := recorder.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, in, buf[:n])
+
+	// replay the recorded session
+	packets, err := ReadTrace(&trace)
+	require.NoError(t, err)
+	require.Len(t, packets, 2)
+	require.Equal(t, TraceOut, packets[0].Direction)
+	require.Equal(t, TraceData(out), packets[0].Data)
+	require.Equal(t, TraceIn, packets[1].Direction)
+	require.Equal(t, TraceData(in), packets[1].Data)
+
+	replayer := NewTraceReplayer(packets, true)
+	_, err = replayer.Write([]byte("?##other"))
+	require.Equal(t, ErrTraceMismatch, err)
+	n, err = replayer.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, in, buf[:n])
+	_, err = replayer.Read(buf)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestTraceRecorderRedactKinds(t *testing.T) {
+	// a secret message of kind 1 spanning two packets, then a message of kind 2
+	first := append([]byte{'?', '#', '#', 0, 1, 0, 0, 0, 60}, bytes.Repeat([]byte{'s'}, 55)...)
+	second := append([]byte{'?'}, bytes.Repeat([]byte{'s'}, 5)...)
+	second = append(second, make([]byte, 58)...)
+	public := append([]byte{'?', '#', '#', 0, 2, 0, 0, 0, 4}, []byte("ping")...)
+	packets := [][]byte{first, second, public}
+
+	var session []TracePacket
+	for _, p := range packets {
+		session = append(session, TracePacket{Direction: TraceOut, Data: p})
+	}
+	var trace bytes.Buffer
+	tw := NewTraceWriter(&trace)
+	tw.RedactKinds(func(kind uint16) bool { return kind == 1 })
+	recorder := NewTraceRecorder(NewTraceReplayer(session, true), tw)
+	for _, p := range packets {
+		_, err := recorder.Write(p)
+		require.NoError(t, err)
+	}
+	// the packets written to the device are left untouched
+	require.Equal(t, byte('s'), first[headerLen])
+
+	recorded, err := ReadTrace(&trace)
+	require.NoError(t, err)
+	require.Len(t, recorded, 3)
+	require.Equal(t, TraceData(append(first[:headerLen:headerLen], make([]byte, 55)...)), recorded[0].Data)
+	require.Equal(t, TraceData(append([]byte{'?'}, make([]byte, 63)...)), recorded[1].Data)
+	require.Equal(t, TraceData(public), recorded[2].Data)
+}
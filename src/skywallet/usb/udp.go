@@ -13,6 +13,23 @@ const (
 	emulatorAddress = "127.0.0.1"
 )
 
+// EmulatorPath returns the path of the emulator listening on port
+func EmulatorPath(port int) string {
+	return emulatorPrefix + strconv.Itoa(port)
+}
+
+// EmulatorPort returns the port of the emulator at path
+func EmulatorPort(path string) (int, bool) {
+	if !strings.HasPrefix(path, emulatorPrefix) {
+		return 0, false
+	}
+	port, err := strconv.Atoi(strings.TrimPrefix(path, emulatorPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return port, true
+}
+
 type UDP struct {
 	ports []int
 }
@@ -30,7 +47,7 @@ func (udp *UDP) Enumerate(_, _ uint16) ([]Info, error) {
 
 	for _, port := range udp.ports {
 		info := Info{
-			Path:      emulatorPrefix + strconv.Itoa(port),
+			Path:      EmulatorPath(port),
 			VendorID:  0,
 			ProductID: 0,
 			Type:      TypeEmulator,
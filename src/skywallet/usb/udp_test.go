@@ -0,0 +1,28 @@
+package usb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmulatorPath(t *testing.T) {
+	path := EmulatorPath(21324)
+	require.Equal(t, "emulator21324", path)
+	port, ok := EmulatorPort(path)
+	require.True(t, ok)
+	require.Equal(t, 21324, port)
+
+	for _, path := range []string{"21324", "emulator", "emulatorfoo", "1-1:1.0"} {
+		_, ok := EmulatorPort(path)
+		require.False(t, ok, path)
+	}
+
+	udp, err := InitUDP([]int{21324, 21325})
+	require.NoError(t, err)
+	infos, err := udp.Enumerate(0, 0)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	require.Equal(t, "emulator21325", infos[1].Path)
+	require.True(t, udp.Has(infos[1].Path))
+}
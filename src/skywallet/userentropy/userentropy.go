@@ -0,0 +1,168 @@
+/*
+Package userentropy turns dice rolls and shuffled cards supplied by the user
+into entropy, for users who do not want to trust any random number generator.
+
+The entropy is the beginning of the sha256 of the normalized input, anybody
+can check it with a sha256 tool. The input is rejected if its estimated
+entropy is lower than the entropy requested.
+*/
+package userentropy
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DiceSides is the number of sides of the supported dice
+	DiceSides = 6
+	// DeckSize is the number of cards of a standard deck
+	DeckSize = 52
+
+	cardRanks = "A23456789TJQK"
+	cardSuits = "CDHS"
+)
+
+var (
+	// ErrInvalidBits is returned if the requested entropy is not a multiple of 8 bits up to 256 bits
+	ErrInvalidBits = errors.New("entropy bits must be a multiple of 8 between 8 and 256")
+	// ErrEmptyInput is returned if the input has no dice roll or card
+	ErrEmptyInput = errors.New("no dice roll or card found in the input")
+)
+
+// Source is the normalized input of the user with its estimated entropy
+type Source struct {
+	// Kind is "dice" or "cards"
+	Kind string
+	// Normalized is the input hashed to get the entropy, dice rolls as digits
+	// and cards as space separated rank and suit codes such as "TH AS 2C"
+	Normalized string
+	// Count is the number of dice rolls or cards
+	Count int
+	// Bits is the estimated entropy of the input
+	Bits float64
+}
+
+// NotEnoughEntropyError is returned if the input does not hold the entropy requested
+type NotEnoughEntropyError struct {
+	Source Source
+	Bits   int
+	// Needed is the minimum number of dice rolls or cards to get the entropy requested
+	Needed int
+}
+
+func (e *NotEnoughEntropyError) Error() string {
+	return fmt.Sprintf("%d %s hold %.1f bits of entropy, %d bits need at least %d",
+		e.Source.Count, e.Source.Kind, e.Source.Bits, e.Bits, e.Needed)
+}
+
+// ParseDice reads rolls of six sided dice, digits 1 to 6. Spaces and commas are ignored.
+func ParseDice(input string) (Source, error) {
+	var rolls strings.Builder
+	for _, r := range input {
+		switch {
+		case r >= '1' && r <= '0'+DiceSides:
+			rolls.WriteRune(r)
+		case unicode.IsSpace(r) || r == ',':
+		default:
+			return Source{}, fmt.Errorf("invalid dice roll %q, rolls must be digits between 1 and %d", r, DiceSides)
+		}
+	}
+	if rolls.Len() == 0 {
+		return Source{}, ErrEmptyInput
+	}
+
+	return Source{
+		Kind:       "dice",
+		Normalized: rolls.String(),
+		Count:      rolls.Len(),
+		Bits:       diceBits(rolls.Len()),
+	}, nil
+}
+
+// ParseCards reads a sequence of cards separated by spaces or commas. A card is
+// its rank, one of A 2 3 4 5 6 7 8 9 10 T J Q K, followed by its suit, one of
+// C D H S, for instance "10H", "as" or "Qd". The cards of a deck must not be put
+// back, a repeated card is rejected until the 52 cards are drawn and a new deck
+// is started.
+func ParseCards(input string) (Source, error) {
+	tokens := strings.FieldsFunc(strings.ToUpper(input), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+	if len(tokens) == 0 {
+		return Source{}, ErrEmptyInput
+	}
+
+	cards := make([]string, len(tokens))
+	deck := map[string]bool{}
+	for i, t := range tokens {
+		if strings.HasPrefix(t, "10") {
+			t = "T" + t[2:]
+		}
+		if len(t) != 2 || !strings.ContainsRune(cardRanks, rune(t[0])) || !strings.ContainsRune(cardSuits, rune(t[1])) {
+			return Source{}, fmt.Errorf("invalid card %q at position %d", tokens[i], i+1)
+		}
+		if len(deck) == DeckSize {
+			deck = map[string]bool{}
+		}
+		if deck[t] {
+			return Source{}, fmt.Errorf("card %q at position %d was already drawn from the deck, cards must not be put back", tokens[i], i+1)
+		}
+		deck[t] = true
+		cards[i] = t
+	}
+
+	return Source{
+		Kind:       "cards",
+		Normalized: strings.Join(cards, " "),
+		Count:      len(cards),
+		Bits:       cardsBits(len(cards)),
+	}, nil
+}
+
+// Entropy returns the first bits of the sha256 of the normalized input
+func (s Source) Entropy(bits int) ([]byte, error) {
+	if bits <= 0 || bits > 256 || bits%8 != 0 {
+		return nil, ErrInvalidBits
+	}
+	if s.Bits < float64(bits) {
+		return nil, &NotEnoughEntropyError{
+			Source: s,
+			Bits:   bits,
+			Needed: s.needed(bits),
+		}
+	}
+	hash := sha256.Sum256([]byte(s.Normalized))
+	return hash[:bits/8], nil
+}
+
+// needed returns the minimum number of dice rolls or cards holding bits of entropy
+func (s Source) needed(bits int) int {
+	if s.Kind == "dice" {
+		return int(math.Ceil(float64(bits) / math.Log2(DiceSides)))
+	}
+	n := 0
+	for total := 0.0; total < float64(bits); n++ {
+		total += math.Log2(float64(DeckSize - n%DeckSize))
+	}
+	return n
+}
+
+func diceBits(rolls int) float64 {
+	return float64(rolls) * math.Log2(DiceSides)
+}
+
+// cardsBits estimates the entropy of cards drawn without replacement: the
+// n-th card of a deck is one of the 52-n+1 cards left, the last card of a
+// deck holds no entropy
+func cardsBits(cards int) float64 {
+	bits := 0.0
+	for n := 0; n < cards; n++ {
+		bits += math.Log2(float64(DeckSize - n%DeckSize))
+	}
+	return bits
+}
@@ -0,0 +1,107 @@
+package userentropy
+
+import (
+	"crypto/sha256"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDice(t *testing.T) {
+	s, err := ParseDice("1 2 3,4\n5 6 61")
+	require.NoError(t, err)
+	require.Equal(t, "dice", s.Kind)
+	require.Equal(t, "12345661", s.Normalized)
+	require.Equal(t, 8, s.Count)
+	require.InDelta(t, 8*math.Log2(6), s.Bits, 1e-9)
+
+	_, err = ParseDice("1 2 7")
+	require.Error(t, err)
+	_, err = ParseDice("0")
+	require.Error(t, err)
+	_, err = ParseDice(" , ")
+	require.Equal(t, ErrEmptyInput, err)
+}
+
+func TestParseCards(t *testing.T) {
+	s, err := ParseCards("10h as, qd 2C TS")
+	require.NoError(t, err)
+	require.Equal(t, "cards", s.Kind)
+	require.Equal(t, "TH AS QD 2C TS", s.Normalized)
+	require.Equal(t, 5, s.Count)
+	require.InDelta(t, math.Log2(52*51*50*49*48), s.Bits, 1e-9)
+
+	// a card can only be drawn again from a new deck
+	_, err = ParseCards("AS KH AS")
+	require.EqualError(t, err, `card "AS" at position 3 was already drawn from the deck, cards must not be put back`)
+	_, err = ParseCards(strings.Repeat("AS ", 50))
+	require.Error(t, err)
+	s, err = ParseCards(fullDeck() + " AS KH")
+	require.NoError(t, err)
+	require.Equal(t, DeckSize+2, s.Count)
+	require.InDelta(t, cardsBits(DeckSize)+math.Log2(52*51), s.Bits, 1e-9)
+
+	for _, input := range []string{"1H", "AX", "ASH", "11H"} {
+		_, err = ParseCards(input)
+		require.Error(t, err, input)
+	}
+	_, err = ParseCards("")
+	require.Equal(t, ErrEmptyInput, err)
+}
+
+// fullDeck returns the 52 cards of a deck
+func fullDeck() string {
+	var cards []string
+	for _, suit := range cardSuits {
+		for _, rank := range cardRanks {
+			cards = append(cards, string(rank)+string(suit))
+		}
+	}
+	return strings.Join(cards, " ")
+}
+
+func TestRepeatedCardsEntropy(t *testing.T) {
+	// repeating a card must not be credited the entropy of a new deck
+	for _, input := range []string{strings.Repeat("AS ", 50), fullDeck() + strings.Repeat(" AS", 10)} {
+		s, err := ParseCards(input)
+		if err == nil {
+			_, err = s.Entropy(256)
+		}
+		require.Error(t, err)
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	rolls := strings.Repeat("123456", 9)[:50]
+	s, err := ParseDice(rolls)
+	require.NoError(t, err)
+
+	entropy, err := s.Entropy(128)
+	require.NoError(t, err)
+	hash := sha256.Sum256([]byte(rolls))
+	require.Equal(t, hash[:16], entropy)
+
+	_, err = s.Entropy(256)
+	require.IsType(t, &NotEnoughEntropyError{}, err)
+	require.Equal(t, 100, err.(*NotEnoughEntropyError).Needed)
+	require.NotContains(t, err.Error(), rolls)
+
+	_, err = s.Entropy(100)
+	require.Equal(t, ErrInvalidBits, err)
+
+	s, err = ParseDice(rolls[:49])
+	require.NoError(t, err)
+	_, err = s.Entropy(128)
+	require.IsType(t, &NotEnoughEntropyError{}, err)
+	require.Equal(t, 50, err.(*NotEnoughEntropyError).Needed)
+}
+
+func TestCardsNeeded(t *testing.T) {
+	s := Source{Kind: "cards"}
+	// log2(52*51*...*28) > 128
+	require.Equal(t, 25, s.needed(128))
+	// a full deck holds 225.6 bits
+	require.Equal(t, 58, s.needed(256))
+}
@@ -0,0 +1,225 @@
+package skywallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+)
+
+const (
+	// DefaultBurnFactor is the burn factor Skycoin nodes require for the
+	// transactions they are sent, params.UserVerifyTxn.BurnFactor in
+	// https://github.com/skycoin/skycoin/blob/develop/src/params/params.go,
+	// nodes override it with the USER_BURN_FACTOR environment variable
+	DefaultBurnFactor = 10
+	// DefaultMaxDropletPrecision is the number of decimals Skycoin nodes allow
+	// in the amounts of the transactions they are sent,
+	// params.UserVerifyTxn.MaxDropletPrecision, overridden with USER_MAX_DECIMALS
+	DefaultMaxDropletPrecision = 3
+)
+
+// VerifyParams are the rules of the node a transaction is checked against
+type VerifyParams struct {
+	// BurnFactor is the inverse of the minimum share of the input hours to burn
+	BurnFactor uint64
+	// MaxDropletPrecision is the maximum number of decimals of an amount of SKY
+	MaxDropletPrecision uint64
+}
+
+// DefaultVerifyParams are the rules of a Skycoin node run with its defaults
+var DefaultVerifyParams = VerifyParams{
+	BurnFactor:          DefaultBurnFactor,
+	MaxDropletPrecision: DefaultMaxDropletPrecision,
+}
+
+var (
+	// ErrNoInputs is returned if a transaction has no inputs
+	ErrNoInputs = errors.New("transaction has no inputs")
+	// ErrNoOutputs is returned if a transaction has no outputs
+	ErrNoOutputs = errors.New("transaction has no outputs")
+	// ErrZeroCoins is returned if an output sends no coins
+	ErrZeroCoins = errors.New("coins must not be zero")
+	// ErrDropletPrecision is returned if an amount has more than MaxDropletPrecision decimals
+	ErrDropletPrecision = errors.New("amount has more decimals than the node allows")
+	// ErrDuplicateInput is returned if a transaction spends the same output twice
+	ErrDuplicateInput = errors.New("input is spent twice")
+	// ErrInsufficientFee is returned if fewer than 1/BurnFactor of the input hours are burned
+	ErrInsufficientFee = errors.New("fewer input hours are burned than the burn factor of the node requires")
+	// ErrInvalidVerifyParams is returned if the burn factor is 0 or more than 6 decimals are allowed
+	ErrInvalidVerifyParams = fmt.Errorf("burn factor must not be 0 and amounts have at most %d decimals", dropletDecimals)
+)
+
+// FieldError tells which field of a request is invalid
+type FieldError struct {
+	// Field is the name of the field followed by its position if it is repeated
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// ValidationErrors lists the invalid fields of a request
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// fieldName returns the name of the i-th value of a repeated field
+func fieldName(name string, i int) string {
+	return fmt.Sprintf("%s %d", name, i+1)
+}
+
+// ValidateAddress checks address is a valid Skycoin base58 address
+func ValidateAddress(address string) error {
+	_, err := cipher.DecodeBase58Address(address)
+	return err
+}
+
+// Validate checks the burn factor and the precision are usable
+func (p VerifyParams) Validate() error {
+	if p.BurnFactor == 0 || p.MaxDropletPrecision > dropletDecimals {
+		return ErrInvalidVerifyParams
+	}
+	return nil
+}
+
+// ValidateTransaction checks the transaction against DefaultVerifyParams,
+// see VerifyParams.ValidateTransaction
+func ValidateTransaction(inputs []*messages.SkycoinTransactionInput, outputs []*messages.SkycoinTransactionOutput, values []InputValue) error {
+	return DefaultVerifyParams.ValidateTransaction(inputs, outputs, values)
+}
+
+// ValidateTransaction checks the transaction against the rules of Skycoin
+// before it is sent to the device, the fields are named after the flags of
+// transactionSign. values are the coins and hours of the inputs, the fee is
+// only checked if they are given. The error is nil or ValidationErrors.
+func (p VerifyParams) ValidateTransaction(inputs []*messages.SkycoinTransactionInput, outputs []*messages.SkycoinTransactionOutput, values []InputValue) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	var errs ValidationErrors
+	add := func(field string, err error) {
+		errs = append(errs, FieldError{Field: field, Err: err})
+	}
+
+	if len(inputs) == 0 {
+		add("inputHash", ErrNoInputs)
+	}
+	if len(outputs) == 0 {
+		add("outputAddress", ErrNoOutputs)
+	}
+
+	spent := make(map[cipher.SHA256]bool, len(inputs))
+	for i, in := range inputs {
+		hash, err := cipher.SHA256FromHex(in.GetHashIn())
+		if err != nil {
+			add(fieldName("inputHash", i), err)
+			continue
+		}
+		if spent[hash] {
+			add(fieldName("inputHash", i), ErrDuplicateInput)
+		}
+		spent[hash] = true
+	}
+
+	var coins, hours uint64
+	var err error
+	for i, out := range outputs {
+		if err = ValidateAddress(out.GetAddress()); err != nil {
+			add(fieldName("outputAddress", i), err)
+		}
+		if err = p.validateCoins(out.GetCoin()); err != nil {
+			add(fieldName("coin", i), err)
+		}
+		if coins, err = addAmounts(coins, out.GetCoin()); err != nil {
+			add(fieldName("coin", i), err)
+		}
+		if hours, err = addAmounts(hours, out.GetHour()); err != nil {
+			add(fieldName("hour", i), err)
+		}
+	}
+
+	if len(errs) == 0 && values != nil {
+		errs = p.validateInputValues(values, len(inputs), coins, hours)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validateInputValues checks the inputs hold the coins sent by the outputs
+// and that enough hours are burned. The coins of the inputs are not checked
+// against the precision, nodes only apply it to the outputs and outputs left
+// by older transactions may have more decimals.
+func (p VerifyParams) validateInputValues(values []InputValue, inputs int, coins, hours uint64) ValidationErrors {
+	if len(values) != inputs {
+		return ValidationErrors{{Field: "inputCoin", Err: ErrInputValuesCount}}
+	}
+
+	var errs ValidationErrors
+	var inputCoins, inputHours uint64
+	var err error
+	for i, v := range values {
+		if inputCoins, err = addAmounts(inputCoins, v.Coins); err != nil {
+			errs = append(errs, FieldError{Field: fieldName("inputCoin", i), Err: err})
+		}
+		if inputHours, err = addAmounts(inputHours, v.Hours); err != nil {
+			errs = append(errs, FieldError{Field: fieldName("inputHour", i), Err: err})
+		}
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+
+	if inputCoins != coins {
+		errs = append(errs, FieldError{
+			Field: "coin",
+			Err:   fmt.Errorf("outputs send %s SKY but inputs hold %s SKY", FormatDroplets(coins), FormatDroplets(inputCoins)),
+		})
+	}
+	if hours > inputHours || inputHours-hours < p.RequiredFee(inputHours) {
+		errs = append(errs, FieldError{Field: "hour", Err: ErrInsufficientFee})
+	}
+	return errs
+}
+
+// validateCoins checks an amount of droplets is not zero and has at most MaxDropletPrecision decimals
+func (p VerifyParams) validateCoins(droplets uint64) error {
+	if droplets == 0 {
+		return ErrZeroCoins
+	}
+	multiple := uint64(1)
+	for i := p.MaxDropletPrecision; i < dropletDecimals; i++ {
+		multiple *= 10
+	}
+	if droplets%multiple != 0 {
+		return ErrDropletPrecision
+	}
+	return nil
+}
+
+// RequiredFee returns the hours to burn when spending hours with DefaultVerifyParams
+func RequiredFee(hours uint64) uint64 {
+	return DefaultVerifyParams.RequiredFee(hours)
+}
+
+// RequiredFee returns the hours to burn when spending hours, rounded up
+func (p VerifyParams) RequiredFee(hours uint64) uint64 {
+	fee := hours / p.BurnFactor
+	if hours%p.BurnFactor != 0 {
+		fee++
+	}
+	return fee
+}
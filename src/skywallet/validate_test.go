@@ -0,0 +1,137 @@
+package skywallet
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+)
+
+func TestValidateTransaction(t *testing.T) {
+	const (
+		hash    = "c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59"
+		address = "K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot"
+	)
+	input := func(hash string) *messages.SkycoinTransactionInput {
+		return &messages.SkycoinTransactionInput{HashIn: proto.String(hash), Index: proto.Uint32(0)}
+	}
+	output := func(address string, coins, hours uint64) *messages.SkycoinTransactionOutput {
+		return &messages.SkycoinTransactionOutput{Address: proto.String(address), Coin: proto.Uint64(coins), Hour: proto.Uint64(hours)}
+	}
+
+	tt := []struct {
+		name    string
+		inputs  []*messages.SkycoinTransactionInput
+		outputs []*messages.SkycoinTransactionOutput
+		values  []InputValue
+		fields  []string
+	}{
+		{
+			name:    "valid",
+			inputs:  []*messages.SkycoinTransactionInput{input(hash)},
+			outputs: []*messages.SkycoinTransactionOutput{output(address, 1001000, 2)},
+		},
+		{
+			name:    "valid with input values",
+			inputs:  []*messages.SkycoinTransactionInput{input(hash)},
+			outputs: []*messages.SkycoinTransactionOutput{output(address, 1000000, 2)},
+			values:  []InputValue{{Coins: 1000000, Hours: 5}},
+		},
+		{
+			name:    "input values with more decimals than the outputs allow",
+			inputs:  []*messages.SkycoinTransactionInput{input(hash), input("ae6fcae589898d6003362aaf39c56852f65369d55bf0f2f672bcc268c15a32da")},
+			outputs: []*messages.SkycoinTransactionOutput{output(address, 1001000, 2)},
+			values:  []InputValue{{Coins: 1000001, Hours: 3}, {Coins: 999, Hours: 2}},
+		},
+		{
+			name:   "no inputs and outputs",
+			fields: []string{"inputHash", "outputAddress"},
+		},
+		{
+			name:    "invalid hash and address",
+			inputs:  []*messages.SkycoinTransactionInput{input(hash), input("c5467f")},
+			outputs: []*messages.SkycoinTransactionOutput{output(address, 1000000, 0), output("K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2oO", 1000000, 0)},
+			fields:  []string{"inputHash 2", "outputAddress 2"},
+		},
+		{
+			name:    "duplicate input",
+			inputs:  []*messages.SkycoinTransactionInput{input(hash), input(hash)},
+			outputs: []*messages.SkycoinTransactionOutput{output(address, 1000000, 0)},
+			fields:  []string{"inputHash 2"},
+		},
+		{
+			name:    "zero coins and too many decimals",
+			inputs:  []*messages.SkycoinTransactionInput{input(hash)},
+			outputs: []*messages.SkycoinTransactionOutput{output(address, 0, 0), output(address, 1000001, 0)},
+			fields:  []string{"coin 1", "coin 2"},
+		},
+		{
+			name:    "overflow",
+			inputs:  []*messages.SkycoinTransactionInput{input(hash)},
+			outputs: []*messages.SkycoinTransactionOutput{output(address, 1000000, math.MaxUint64), output(address, math.MaxUint64-math.MaxUint64%1000, 1)},
+			fields:  []string{"coin 2", "hour 2"},
+		},
+		{
+			name:    "coins and fee not matching the inputs",
+			inputs:  []*messages.SkycoinTransactionInput{input(hash)},
+			outputs: []*messages.SkycoinTransactionOutput{output(address, 1000000, 5)},
+			values:  []InputValue{{Coins: 2000000, Hours: 5}},
+			fields:  []string{"coin", "hour"},
+		},
+		{
+			name:    "missing input values",
+			inputs:  []*messages.SkycoinTransactionInput{input(hash)},
+			outputs: []*messages.SkycoinTransactionOutput{output(address, 1000000, 0)},
+			values:  []InputValue{},
+			fields:  []string{"inputCoin"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTransaction(tc.inputs, tc.outputs, tc.values)
+			if len(tc.fields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			errs, ok := err.(ValidationErrors)
+			require.True(t, ok)
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			require.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestVerifyParams(t *testing.T) {
+	inputs := []*messages.SkycoinTransactionInput{{HashIn: proto.String("c5467f398fc3b9d7255d417d9ca208c0a1dfa0ee573974a5fdeb654e1735fc59"), Index: proto.Uint32(0)}}
+	outputs := []*messages.SkycoinTransactionOutput{{Address: proto.String("K9TzLrgqz7uXn3QJHGxmzdRByAzH33J2ot"), Coin: proto.Uint64(1000100), Hour: proto.Uint64(8)}}
+	values := []InputValue{{Coins: 1000100, Hours: 10}}
+
+	// 1/10 of the hours burned with 4 decimals
+	params := VerifyParams{BurnFactor: 10, MaxDropletPrecision: 4}
+	require.NoError(t, params.ValidateTransaction(inputs, outputs, values))
+	require.Equal(t, uint64(1), params.RequiredFee(10))
+	require.Equal(t, uint64(2), params.RequiredFee(11))
+
+	// half of the hours must be burned with 3 decimals
+	params = VerifyParams{BurnFactor: 2, MaxDropletPrecision: 3}
+	require.Equal(t, ValidationErrors{{Field: "coin 1", Err: ErrDropletPrecision}}, params.ValidateTransaction(inputs, outputs, values))
+	outputs[0].Coin = proto.Uint64(1000000)
+	values[0].Coins = 1000000
+	require.Equal(t, ValidationErrors{{Field: "hour", Err: ErrInsufficientFee}}, params.ValidateTransaction(inputs, outputs, values))
+
+	require.Equal(t, ErrInvalidVerifyParams, VerifyParams{MaxDropletPrecision: 3}.ValidateTransaction(inputs, outputs, values))
+	require.Equal(t, ErrInvalidVerifyParams, VerifyParams{BurnFactor: 2, MaxDropletPrecision: 7}.Validate())
+}
+
+func TestValidateAddress(t *testing.T) {
+	require.NoError(t, ValidateAddress("2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzw"))
+	require.Error(t, ValidateAddress("2EU3JbveHdkxW6z5tdhbbB2kRAWvXC2pLzW"))
+	require.Error(t, ValidateAddress(""))
+}
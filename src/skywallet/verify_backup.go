@@ -0,0 +1,119 @@
+package skywallet
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+)
+
+var (
+	// ErrNotInitialized is returned if the device has no seed to verify
+	ErrNotInitialized = errors.New("device is not initialized, there is no seed to verify")
+	// ErrNeedsBackup is returned if the seed of the device was never backed up
+	ErrNeedsBackup = errors.New("device seed is not backed up yet, run backup first")
+	// ErrUnfinishedBackup is returned if a backup was started but not finished,
+	// the words written down may be incomplete and the device will not show them again
+	ErrUnfinishedBackup = errors.New("backup was not finished, the written words cannot be trusted, move the funds to a new seed")
+)
+
+// CheckBackupState tells if the backup of the device seed can be verified
+func CheckBackupState(features *messages.Features) error {
+	switch {
+	case !features.GetInitialized():
+		return ErrNotInitialized
+	case features.GetUnfinishedBackup():
+		return ErrUnfinishedBackup
+	case features.GetNeedsBackup():
+		return ErrNeedsBackup
+	}
+	return nil
+}
+
+// BackupVerification records the result of a dry-run recovery
+type BackupVerification struct {
+	Time      time.Time `json:"time"`
+	DeviceID  string    `json:"device_id"`
+	Label     string    `json:"label,omitempty"`
+	WordCount uint32    `json:"word_count"`
+	Verified  bool      `json:"verified"`
+	// Message is the response of the device
+	Message string `json:"message,omitempty"`
+}
+
+// WordReader returns the next recovery word asked by the device
+type WordReader func() (string, error)
+
+// VerifyBackup checks the written down recovery words against the seed of d
+// with a dry-run recovery, the seed is left untouched whatever the result.
+// The words are read with readWord in the order asked by the device and the
+// other requests of the device are answered by handle. The returned
+// verification tells if the words match, an error is returned if the backup
+// cannot be verified.
+func VerifyBackup(d Devicer, wordCount uint32, handle RequestHandler, readWord WordReader) (*BackupVerification, error) {
+	msg, err := d.GetFeatures()
+	if err != nil {
+		return nil, err
+	}
+	features, err := DecodeFeatures(msg)
+	if err != nil {
+		return nil, err
+	}
+	if err = CheckBackupState(features); err != nil {
+		return nil, err
+	}
+
+	msg, err = d.Recovery(wordCount, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	msg, err = handle(d, msg)
+	for err == nil && msg.Kind == uint16(messages.MessageType_MessageType_WordRequest) {
+		var word string
+		word, err = readWord()
+		if err != nil {
+			break
+		}
+		msg, err = d.WordAck(word)
+		if err != nil {
+			break
+		}
+		msg, err = handle(d, msg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := DecodeSuccessOrFailMsg(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &BackupVerification{
+		Time:      time.Now().UTC(),
+		DeviceID:  features.GetDeviceId(),
+		Label:     features.GetLabel(),
+		WordCount: wordCount,
+		Verified:  msg.Kind == uint16(messages.MessageType_MessageType_Success),
+		Message:   message,
+	}, nil
+}
+
+// AppendBackupVerification appends v as a JSON line to the file at path,
+// the file keeps the history of the verifications of every device
+func AppendBackupVerification(path string, v BackupVerification) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
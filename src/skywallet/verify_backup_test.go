@@ -0,0 +1,176 @@
+package skywallet
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/hardware-wallet-go/src/skywallet/wire"
+)
+
+func (suite *devicerSuit) TestCheckBackupState() {
+	tt := []struct {
+		name     string
+		features *messages.Features
+		err      error
+	}{
+		{
+			name:     "not initialized",
+			features: &messages.Features{Initialized: proto.Bool(false)},
+			err:      ErrNotInitialized,
+		},
+		{
+			name:     "needs backup",
+			features: &messages.Features{Initialized: proto.Bool(true), NeedsBackup: proto.Bool(true)},
+			err:      ErrNeedsBackup,
+		},
+		{
+			name: "unfinished backup",
+			features: &messages.Features{
+				Initialized:      proto.Bool(true),
+				NeedsBackup:      proto.Bool(false),
+				UnfinishedBackup: proto.Bool(true),
+			},
+			err: ErrUnfinishedBackup,
+		},
+		{
+			name:     "backed up",
+			features: &messages.Features{Initialized: proto.Bool(true), NeedsBackup: proto.Bool(false)},
+		},
+	}
+
+	for _, tc := range tt {
+		suite.T().Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.err, CheckBackupState(tc.features))
+		})
+	}
+}
+
+func (suite *devicerSuit) TestDecodeFeatures() {
+	// NOTE: Giving
+	data, err := proto.Marshal(&messages.Features{DeviceId: proto.String("0123456789AB"), Initialized: proto.Bool(true)})
+	suite.Nil(err)
+
+	// NOTE: When
+	features, err := DecodeFeatures(wire.Message{Kind: uint16(messages.MessageType_MessageType_Features), Data: data})
+
+	// NOTE: Assert
+	suite.Nil(err)
+	suite.Equal("0123456789AB", features.GetDeviceId())
+	suite.True(features.GetInitialized())
+
+	_, err = DecodeFeatures(wire.Message{Kind: uint16(messages.MessageType_MessageType_Success)})
+	suite.NotNil(err)
+}
+
+func (suite *devicerSuit) TestAppendBackupVerification() {
+	dir, err := ioutil.TempDir("", "backup-verification")
+	suite.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "backups.jsonl")
+
+	verifications := []BackupVerification{
+		{Time: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), DeviceID: "0123456789AB", WordCount: 12, Verified: false},
+		{Time: time.Date(2020, 1, 2, 3, 10, 0, 0, time.UTC), DeviceID: "0123456789AB", WordCount: 12, Verified: true},
+	}
+	for _, v := range verifications {
+		suite.Nil(AppendBackupVerification(path, v))
+	}
+
+	f, err := os.Open(path)
+	suite.Nil(err)
+	defer f.Close()
+	var records []BackupVerification
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var v BackupVerification
+		suite.Nil(json.Unmarshal(scanner.Bytes(), &v))
+		records = append(records, v)
+	}
+	suite.Nil(scanner.Err())
+	suite.Equal(verifications, records)
+
+	info, err := os.Stat(path)
+	suite.Nil(err)
+	suite.Equal(os.FileMode(0600), info.Mode().Perm())
+}
+
+func (suite *devicerSuit) TestVerifyBackup() {
+	featuresData, err := proto.Marshal(&messages.Features{
+		DeviceId:    proto.String("0123456789AB"),
+		Label:       proto.String("savings"),
+		Initialized: proto.Bool(true),
+		NeedsBackup: proto.Bool(false),
+	})
+	suite.Nil(err)
+	features := wire.Message{Kind: uint16(messages.MessageType_MessageType_Features), Data: featuresData}
+	wordRequest := wire.Message{Kind: uint16(messages.MessageType_MessageType_WordRequest)}
+	buttonRequest := wire.Message{Kind: uint16(messages.MessageType_MessageType_ButtonRequest)}
+	successData, err := proto.Marshal(&messages.Success{Message: proto.String("The seed is valid and matches the one in the device")})
+	suite.Nil(err)
+	success := wire.Message{Kind: uint16(messages.MessageType_MessageType_Success), Data: successData}
+	failureData, err := proto.Marshal(&messages.Failure{Message: proto.String("The seed is valid but does not match the one in the device")})
+	suite.Nil(err)
+	failure := wire.Message{Kind: uint16(messages.MessageType_MessageType_Failure), Data: failureData}
+
+	for _, result := range []wire.Message{success, failure} {
+		// NOTE: Giving
+		device := &MockDevicer{}
+		device.On("GetFeatures").Return(features, nil)
+		device.On("Recovery", uint32(12), (*bool)(nil), true).Return(buttonRequest, nil)
+		device.On("ButtonAck").Return(wordRequest, nil)
+		device.On("WordAck", "cloud").Return(wordRequest, nil)
+		device.On("WordAck", "flower").Return(result, nil)
+		words := []string{"cloud", "flower"}
+		readWord := func() (string, error) {
+			word := words[0]
+			words = words[1:]
+			return word, nil
+		}
+		handle := func(d Devicer, msg wire.Message) (wire.Message, error) {
+			if msg.Kind == uint16(messages.MessageType_MessageType_ButtonRequest) {
+				return d.ButtonAck()
+			}
+			return msg, nil
+		}
+
+		// NOTE: When
+		v, err := VerifyBackup(device, 12, handle, readWord)
+
+		// NOTE: Assert
+		suite.Nil(err)
+		device.AssertExpectations(suite.T())
+		suite.Empty(words)
+		suite.Equal("0123456789AB", v.DeviceID)
+		suite.Equal("savings", v.Label)
+		suite.Equal(uint32(12), v.WordCount)
+		suite.Equal(result.Kind == success.Kind, v.Verified)
+		expected, err := DecodeSuccessOrFailMsg(result)
+		suite.Nil(err)
+		suite.Equal(expected, v.Message)
+	}
+}
+
+func (suite *devicerSuit) TestVerifyBackupNotBackedUp() {
+	// NOTE: Giving
+	data, err := proto.Marshal(&messages.Features{Initialized: proto.Bool(true), NeedsBackup: proto.Bool(true)})
+	suite.Nil(err)
+	device := &MockDevicer{}
+	device.On("GetFeatures").Return(wire.Message{Kind: uint16(messages.MessageType_MessageType_Features), Data: data}, nil)
+
+	// NOTE: When
+	_, err = VerifyBackup(device, 12, noRequests, nil)
+
+	// NOTE: Assert
+	suite.Equal(ErrNeedsBackup, err)
+	device.AssertNotCalled(suite.T(), "Recovery", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -14,11 +14,14 @@ var (
 	ErrMalformedProtobuf = errors.New("malformed protobuf")
 )
 
+// Validate checks buf is a well formed protobuf message using only
+// varint and length delimited fields, as all the skywallet messages do
 func Validate(buf []byte) error {
 	const (
 		wireVarint   = 0               // int32, int64, uint32, uint64, sint32, sint64, bool, enum
 		wireData     = 2               // string, bytes, embedded messages, packed repeated fields
 		maxFieldSize = 1024 * 1024 * 4 // 4mb field size
+		maxFieldNum  = 1<<29 - 1       // biggest field number allowed by protobuf
 	)
 
 	r := bytes.NewReader(buf)
@@ -27,23 +30,23 @@ func Validate(buf []byte) error {
 		// read the field key (combination of tag and type)
 		key, err := binary.ReadUvarint(r)
 		if err != nil {
-			return err
+			return ErrMalformedProtobuf
 		}
 
-		// validate the field type
-		typ := key & 7
-		if typ != wireVarint && typ != wireData {
+		// validate the field number and type
+		typ, num := key&7, key>>3
+		if num == 0 || num > maxFieldNum || (typ != wireVarint && typ != wireData) {
 			return ErrMalformedProtobuf
 		}
 
 		// read the field value
 		val, err := binary.ReadUvarint(r)
 		if err != nil {
-			return err
+			return ErrMalformedProtobuf
 		}
 		if typ == wireData {
 			// field is length-delimited data, skip the data
-			if val > maxFieldSize || int64(val) < 0 {
+			if val > maxFieldSize || val > uint64(r.Len()) {
 				return ErrMalformedProtobuf
 			}
 			_, err = r.Seek(int64(val), ioSeekCurrent)
@@ -18,6 +18,9 @@ type Message struct {
 }
 
 func (m *Message) WriteTo(w io.Writer) (int64, error) {
+	if len(m.Data) > MaxMessageSize {
+		return 0, ErrMessageTooLarge
+	}
 	var (
 		rep  [packetLen]byte
 		kind = m.Kind
@@ -60,23 +63,45 @@ func (m *Message) WriteTo(w io.Writer) (int64, error) {
 	return int64(written), nil
 }
 
+const (
+	// MaxMessageSize is the biggest message payload accepted by ReadFrom and WriteTo
+	MaxMessageSize = 1024 * 1024 * 4
+)
+
 var (
 	ErrMalformedMessage = errors.New("malformed wire format")
+	ErrMessageTooLarge  = errors.New("message exceeds the maximum size")
+	ErrTooManySkipped   = errors.New("too many packets without message header")
+)
+
+const (
+	// maxSkippedPackets is the number of packets without message header
+	// ReadFrom skips before giving up
+	maxSkippedPackets = 1024
 )
 
+// ReadFrom reads a message from r, each read should return a 64 bytes packet.
+// Packets in the bus previous to a message header are skipped. A stream ending
+// in the middle of a message returns io.ErrUnexpectedEOF and the payload is
+// validated to be a well formed protobuf.
 func ReadFrom(r io.Reader) (*Message, error) {
 	var (
 		rep  [packetLen]byte
 		read = 0 // number of read bytes
 	)
-	n, err := r.Read(rep[:])
+	n, err := io.ReadFull(r, rep[:])
 	if err != nil {
 		return nil, err
 	}
 
 	// skip all the previous messages in the bus
+	skipped := 0
 	for rep[0] != repMarker || rep[1] != repMagic || rep[2] != repMagic {
-		n, err = r.Read(rep[:])
+		skipped++
+		if skipped > maxSkippedPackets {
+			return nil, ErrTooManySkipped
+		}
+		n, err = io.ReadFull(r, rep[:])
 		if err != nil {
 			return nil, err
 		}
@@ -87,13 +112,19 @@ func ReadFrom(r io.Reader) (*Message, error) {
 	var (
 		kind = binary.BigEndian.Uint16(rep[3:])
 		size = binary.BigEndian.Uint32(rep[5:])
-		data = make([]byte, 0, size)
 	)
+	if size > MaxMessageSize {
+		return nil, ErrMessageTooLarge
+	}
+	data := make([]byte, 0, size)
 	data = append(data, rep[9:]...) // read data after header
 
 	for uint32(len(data)) < size {
-		n, err := r.Read(rep[:])
+		n, err := io.ReadFull(r, rep[:])
 		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
 			return nil, err
 		}
 		if rep[0] != repMarker {
@@ -104,6 +135,10 @@ func ReadFrom(r io.Reader) (*Message, error) {
 	}
 	data = data[:size]
 
+	if err := Validate(data); err != nil {
+		return nil, err
+	}
+
 	return &Message{
 		Kind: kind,
 		Data: data,
@@ -0,0 +1,81 @@
+//go:build go1.18
+// +build go1.18
+
+package wire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+)
+
+func FuzzReadFrom(f *testing.F) {
+	f.Add(encode(f, Message{Kind: 17, Data: []byte{0x0a, 0x03, 'a', 'b', 'c'}}))
+	f.Add(encode(f, Message{Kind: 2, Data: nil}))
+	f.Add(header(3, 0xffffffff))
+	f.Add(append(make([]byte, packetLen), header(4, 0)...))
+
+	f.Fuzz(func(t *testing.T, in []byte) {
+		msg, err := ReadFrom(bytes.NewReader(in))
+		if err != nil {
+			return
+		}
+		if len(msg.Data) > MaxMessageSize {
+			t.Fatalf("message of %d bytes exceeds the maximum size", len(msg.Data))
+		}
+		if err := Validate(msg.Data); err != nil {
+			t.Fatalf("message with invalid payload accepted: %v", err)
+		}
+		again, err := ReadFrom(bytes.NewReader(encode(t, *msg)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.Kind != msg.Kind || !bytes.Equal(again.Data, msg.Data) {
+			t.Fatalf("round trip mismatch: %v != %v", again, msg)
+		}
+	})
+}
+
+func FuzzMessageWriteTo(f *testing.F) {
+	f.Add(uint16(113), []byte{0x0a, 0x03, 'a', 'b', 'c'})
+	f.Add(uint16(0), []byte{})
+	f.Add(uint16(7), bytes.Repeat([]byte{0x08, 0x7f}, 100))
+
+	f.Fuzz(func(t *testing.T, kind uint16, data []byte) {
+		in := encode(t, Message{Kind: kind, Data: data})
+		if len(in)%packetLen != 0 {
+			t.Fatalf("written %d bytes, not a multiple of the packet size", len(in))
+		}
+		msg, err := ReadFrom(bytes.NewReader(in))
+		if Validate(data) != nil {
+			if err != ErrMalformedProtobuf {
+				t.Fatalf("expected ErrMalformedProtobuf, got %v", err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Kind != kind || !bytes.Equal(msg.Data, data) {
+			t.Fatalf("round trip mismatch: %v != %v", msg.Data, data)
+		}
+	})
+}
+
+func FuzzValidate(f *testing.F) {
+	f.Add([]byte{0x0a, 0x03, 'a', 'b', 'c', 0x10, 0x01})
+	f.Add([]byte{0x0a, 0xff, 0xff, 0xff, 0xff, 0x0f})
+	f.Add([]byte{0x00, 0x00})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if Validate(data) != nil {
+			return
+		}
+		// a valid payload can be unmarshalled keeping all fields as unknown
+		if err := proto.Unmarshal(data, &messages.Cancel{}); err != nil {
+			t.Fatalf("valid payload failed to unmarshal: %v", err)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"testing/quick"
+
+	"github.com/gogo/protobuf/proto"
+	messages "github.com/skycoin/hardware-wallet-protob/go"
+	"github.com/stretchr/testify/require"
+)
+
+func encode(t testing.TB, m Message) []byte {
+	var buf bytes.Buffer
+	_, err := m.WriteTo(&buf)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func header(kind uint16, size uint32) []byte {
+	rep := make([]byte, packetLen)
+	rep[0], rep[1], rep[2] = repMarker, repMagic, repMagic
+	binary.BigEndian.PutUint16(rep[3:], kind)
+	binary.BigEndian.PutUint32(rep[5:], size)
+	return rep
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	roundTrip := func(kind uint16, payload []byte) bool {
+		data, err := proto.Marshal(&messages.Entropy{Entropy: payload})
+		if err != nil {
+			return false
+		}
+		msg, err := ReadFrom(bytes.NewReader(encode(t, Message{Kind: kind, Data: data})))
+		if err != nil {
+			return false
+		}
+		return msg.Kind == kind && bytes.Equal(msg.Data, data)
+	}
+	require.NoError(t, quick.Check(roundTrip, nil))
+}
+
+func TestReadFromErrors(t *testing.T) {
+	valid := encode(t, Message{Kind: 1, Data: bytes.Repeat([]byte{0x08, 0x01}, 64)})
+	require.Len(t, valid, 3*packetLen)
+
+	tt := []struct {
+		name string
+		in   []byte
+		err  error
+	}{
+		{
+			name: "empty stream",
+			in:   nil,
+			err:  io.EOF,
+		},
+		{
+			name: "truncated packet",
+			in:   valid[:10],
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			name: "truncated message",
+			in:   valid[:2*packetLen],
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			name: "too large",
+			in:   header(1, MaxMessageSize+1),
+			err:  ErrMessageTooLarge,
+		},
+		{
+			name: "missing continuation marker",
+			in:   append(header(1, 100), make([]byte, packetLen)...),
+			err:  ErrMalformedMessage,
+		},
+		{
+			name: "malformed protobuf",
+			in:   encode(t, Message{Kind: 1, Data: []byte{0x0a, 0x05, 0x01}}),
+			err:  ErrMalformedProtobuf,
+		},
+		{
+			name: "no header",
+			in:   make([]byte, (maxSkippedPackets+1)*packetLen),
+			err:  ErrTooManySkipped,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadFrom(bytes.NewReader(tc.in))
+			require.Equal(t, tc.err, err)
+		})
+	}
+}